@@ -29,6 +29,7 @@ const (
 	ContainerNamekey = "ContainerName"
 	ContainerIDkey   = "ContainerId"
 	PodOwnersKey     = "PodOwners"
+	ServiceNameKey   = "Service"
 
 	PodStatus       = "pod_status"
 	ContainerStatus = "container_status"
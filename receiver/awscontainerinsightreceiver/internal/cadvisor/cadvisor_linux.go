@@ -17,24 +17,409 @@
 package cadvisor
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/host"
 )
 
-// TODO: add proper field for Cadvisor
+const (
+	defaultCgroupRoot = "/sys/fs/cgroup"
+	defaultProcRoot   = "/proc"
+	defaultRootfs     = "/"
+
+	// nodeNameEnv is the environment variable holding the kubernetes node
+	// name, set from the downward API in the daemonset spec.
+	nodeNameEnv = "HOST_NAME"
+
+	sources = `["cadvisor"]`
+	version = "0"
+)
+
+// hostInfo provides the information of the host used to decorate the metrics
+type hostInfo interface {
+	GetNumCores() int64
+	GetMemoryCapacity() int64
+	GetClusterName() string
+	GetInstanceID() string
+	GetInstanceType() string
+	GetAutoScalingGroupName() string
+	GetEbsVolumeID(devName string) string
+}
+
+// Cadvisor generates the Container Insights metrics of the node, its pods and
+// their containers from the cgroup (v1) hierarchies and the proc filesystem.
 type Cadvisor struct {
+	logger                *zap.Logger
+	containerOrchestrator string
+	hostInfo              hostInfo
+	nodeName              string
+	cgroupRoot            string
+	procRoot              string
+	rootfs                string
+	rates                 *rateCalculator
+	now                   func() time.Time
+
+	// capacity of the node, refreshed on each collection
+	numCores       int64
+	memoryCapacity uint64
 }
 
-// New creates a Cadvisor struct which can generate metrics from embedded cadvisor lib
+// measurement is a set of fields sharing the same metric type and tags, it
+// is converted to a pdata.Metrics.
+type measurement struct {
+	metricType string
+	fields     map[string]interface{}
+	tags       map[string]string
+}
+
+// New creates a Cadvisor struct which can generate metrics from the cgroups of the host
 func New(containerOrchestrator string, machineInfo *host.MachineInfo, logger *zap.Logger) *Cadvisor {
-	// TODO: initialize the cadvisor
-	return &Cadvisor{}
+	return newCadvisor(containerOrchestrator, machineInfo, logger, defaultCgroupRoot, defaultProcRoot, defaultRootfs)
+}
+
+func newCadvisor(containerOrchestrator string, hostInfo hostInfo, logger *zap.Logger, cgroupRoot, procRoot, rootfs string) *Cadvisor {
+	return &Cadvisor{
+		logger:                logger,
+		containerOrchestrator: containerOrchestrator,
+		hostInfo:              hostInfo,
+		nodeName:              os.Getenv(nodeNameEnv),
+		cgroupRoot:            cgroupRoot,
+		procRoot:              procRoot,
+		rootfs:                rootfs,
+		rates:                 newRateCalculator(),
+		now:                   time.Now,
+	}
 }
 
-// GetMetrics generates metrics from cadvisor
+// GetMetrics generates metrics from the cgroups of the host. The rates and
+// utilizations are computed from the previous collection, so they are
+// missing from the first one.
 func (c *Cadvisor) GetMetrics() []pdata.Metrics {
-	// TODO: add the logic to generate the metrics from cadvisor
-	return []pdata.Metrics{}
+	now := c.now()
+	c.refreshCapacity()
+	devices, err := readDiskDevices(filepath.Join(c.procRoot, "diskstats"))
+	if err != nil {
+		c.logger.Debug("Failed to read the disk devices", zap.Error(err))
+	}
+
+	measurements := c.nodeMeasurements(devices, now)
+	// the pods are only known on kubernetes, on ecs only the instance metrics are generated
+	if c.containerOrchestrator == ci.EKS {
+		measurements = append(measurements, c.podMeasurements(devices, now)...)
+	}
+	c.rates.endCollection()
+
+	result := make([]pdata.Metrics, 0, len(measurements))
+	for _, m := range measurements {
+		if len(m.fields) == 0 {
+			continue
+		}
+		m.tags[ci.MetricType] = m.metricType
+		c.decorate(m.tags, now)
+		result = append(result, ci.ConvertToOTLPMetrics(m.fields, m.tags, c.logger))
+	}
+	return result
+}
+
+func (c *Cadvisor) refreshCapacity() {
+	c.numCores = c.hostInfo.GetNumCores()
+	if c.numCores <= 0 {
+		c.numCores = int64(runtime.NumCPU())
+	}
+
+	c.memoryCapacity = 0
+	if capacity := c.hostInfo.GetMemoryCapacity(); capacity > 0 {
+		c.memoryCapacity = uint64(capacity)
+	} else if memTotal, err := readMemTotal(filepath.Join(c.procRoot, "meminfo")); err == nil {
+		c.memoryCapacity = memTotal
+	} else {
+		c.logger.Debug("Failed to read the memory capacity", zap.Error(err))
+	}
+}
+
+// hostType returns the type of the host metrics, which depends on the orchestrator
+func (c *Cadvisor) hostType(eksType, ecsType string) string {
+	if c.containerOrchestrator == ci.EKS {
+		return eksType
+	}
+	return ecsType
+}
+
+func (c *Cadvisor) nodeMeasurements(devices map[string]string, now time.Time) []*measurement {
+	node := newMeasurement(c.hostType(ci.TypeNode, ci.TypeInstance), nil)
+	result := []*measurement{node}
+
+	stats, err := readCgroupStats(c.cgroupRoot, "")
+	if err != nil {
+		c.logger.Warn("Failed to read the node cgroup", zap.Error(err))
+	} else {
+		node.add(ci.CPULimit, c.numCores*1000)
+		c.addCPUFields(node, "node", stats.cpu, now)
+		if c.memoryCapacity > 0 {
+			node.add(ci.MemLimit, c.memoryCapacity)
+		}
+		c.addMemoryFields(node, "node", stats.memory, now)
+		result = append(result, c.diskIOMeasurements(c.hostType(ci.TypeNodeDiskIO, ci.TypeInstanceDiskIO), "node", stats.diskIO, devices, nil, now)...)
+	}
+
+	ifaces, err := readNetDev(filepath.Join(c.procRoot, "net", "dev"), ignoredInterfacePrefixes)
+	if err != nil {
+		c.logger.Warn("Failed to read the node network interfaces", zap.Error(err))
+	} else {
+		result = append(result, c.networkMeasurements(c.hostType(ci.TypeNodeNet, ci.TypeInstanceNet), node, "node", ifaces, nil, now)...)
+	}
+
+	fs, err := readRootFilesystem(c.rootfs, filepath.Join(c.procRoot, "mounts"))
+	if err != nil {
+		c.logger.Warn("Failed to read the node filesystem", zap.Error(err))
+	} else {
+		result = append(result, c.filesystemMeasurement(c.hostType(ci.TypeNodeFS, ci.TypeInstanceFS), fs))
+	}
+
+	return result
+}
+
+func (c *Cadvisor) podMeasurements(devices map[string]string, now time.Time) []*measurement {
+	pods, err := discoverPods(c.cgroupRoot)
+	if err != nil {
+		c.logger.Warn("Failed to discover the pod cgroups", zap.Error(err))
+		return nil
+	}
+
+	var result []*measurement
+	for _, pod := range pods {
+		stats, err := readCgroupStats(c.cgroupRoot, pod.path)
+		if err != nil {
+			// the pod might have been deleted since its discovery
+			c.logger.Debug("Failed to read the pod cgroup", zap.String("path", pod.path), zap.Error(err))
+			continue
+		}
+
+		podTags := map[string]string{ci.PodIDKey: pod.uid}
+		podKey := "pod/" + pod.uid
+		podMeasurement := newMeasurement(ci.TypePod, podTags)
+		result = append(result, podMeasurement)
+		c.addCPUFields(podMeasurement, podKey, stats.cpu, now)
+		c.addMemoryFields(podMeasurement, podKey, stats.memory, now)
+
+		// the containers of a pod share its network namespace, which is
+		// found from any of its processes
+		if pid := cgroupPid(c.cgroupRoot, pod.path); pid != "" {
+			ifaces, err := readNetDev(filepath.Join(c.procRoot, pid, "net", "dev"), []string{"lo"})
+			if err != nil {
+				c.logger.Debug("Failed to read the pod network interfaces", zap.String("pod", pod.uid), zap.Error(err))
+			} else {
+				result = append(result, c.networkMeasurements(ci.TypePodNet, podMeasurement, podKey, ifaces, podTags, now)...)
+			}
+		}
+
+		for _, container := range pod.containers {
+			stats, err := readCgroupStats(c.cgroupRoot, container.path)
+			if err != nil {
+				c.logger.Debug("Failed to read the container cgroup", zap.String("path", container.path), zap.Error(err))
+				continue
+			}
+
+			containerTags := map[string]string{ci.PodIDKey: pod.uid, ci.ContainerIDkey: container.id}
+			containerKey := "container/" + container.id
+			containerMeasurement := newMeasurement(ci.TypeContainer, containerTags)
+			result = append(result, containerMeasurement)
+			c.addCPUFields(containerMeasurement, containerKey, stats.cpu, now)
+			c.addMemoryFields(containerMeasurement, containerKey, stats.memory, now)
+			result = append(result, c.diskIOMeasurements(ci.TypeContainerDiskIO, containerKey, stats.diskIO, devices, containerTags, now)...)
+		}
+	}
+	return result
+}
+
+// addCPUFields adds the cpu usage in millicores, and its utilization of the node cores
+func (c *Cadvisor) addCPUFields(m *measurement, key string, cpu cpuStats, now time.Time) {
+	if rate, ok := c.rates.rate(key+"/cpu_total", cpu.total, now); ok {
+		millicores := rate / 1e6
+		m.add(ci.CPUTotal, millicores)
+		if c.numCores > 0 {
+			m.add(ci.CPUUtilization, millicores/float64(c.numCores*1000)*100)
+		}
+	}
+	if rate, ok := c.rates.rate(key+"/cpu_user", cpu.user, now); ok {
+		m.add(ci.CPUUser, rate/1e6)
+	}
+	if rate, ok := c.rates.rate(key+"/cpu_system", cpu.system, now); ok {
+		m.add(ci.CPUSystem, rate/1e6)
+	}
+}
+
+// addMemoryFields adds the memory usage, and the utilization of the node memory by the working set
+func (c *Cadvisor) addMemoryFields(m *measurement, key string, mem memoryStats, now time.Time) {
+	m.add(ci.MemUsage, mem.usage)
+	m.add(ci.MemMaxusage, mem.maxUsage)
+	m.add(ci.MemFailcnt, mem.failcnt)
+	m.add(ci.MemCache, mem.cache)
+	m.add(ci.MemRss, mem.rss)
+	m.add(ci.MemMappedfile, mem.mappedFile)
+	m.add(ci.MemSwap, mem.swap)
+	m.add(ci.MemWorkingset, mem.workingSet)
+	if c.memoryCapacity > 0 {
+		m.add(ci.MemUtilization, float64(mem.workingSet)/float64(c.memoryCapacity)*100)
+	}
+
+	for name, value := range map[string]uint64{
+		ci.MemPgfault:                mem.pgfault,
+		ci.MemPgmajfault:             mem.pgmajfault,
+		ci.MemHierarchicalPgfault:    mem.hierarchicalPgfault,
+		ci.MemHierarchicalPgmajfault: mem.hierarchicalPgmajfault,
+	} {
+		if rate, ok := c.rates.rate(key+"/"+name, value, now); ok {
+			m.add(name, rate)
+		}
+	}
+}
+
+// networkMeasurements returns a measurement per interface, and adds the sum
+// of their rates to the aggregate measurement.
+func (c *Cadvisor) networkMeasurements(netType string, aggregate *measurement, key string, ifaces []interfaceStats,
+	tags map[string]string, now time.Time) []*measurement {
+	var result []*measurement
+	sums := map[string]float64{}
+	for _, iface := range ifaces {
+		ifaceTags := copyTags(tags)
+		ifaceTags[ci.NetIfce] = iface.name
+		m := newMeasurement(netType, ifaceTags)
+
+		for _, counter := range []struct {
+			name  string
+			value uint64
+		}{
+			{ci.NetRxBytes, iface.rxBytes},
+			{ci.NetRxPackets, iface.rxPackets},
+			{ci.NetRxErrors, iface.rxErrors},
+			{ci.NetRxDropped, iface.rxDropped},
+			{ci.NetTxBytes, iface.txBytes},
+			{ci.NetTxPackets, iface.txPackets},
+			{ci.NetTxErrors, iface.txErrors},
+			{ci.NetTxDropped, iface.txDropped},
+		} {
+			if rate, ok := c.rates.rate(key+"/"+iface.name+"/"+counter.name, counter.value, now); ok {
+				m.add(counter.name, rate)
+				sums[counter.name] += rate
+			}
+		}
+		rx, rxOK := m.fields[ci.MetricName(netType, ci.NetRxBytes)].(float64)
+		tx, txOK := m.fields[ci.MetricName(netType, ci.NetTxBytes)].(float64)
+		if rxOK && txOK {
+			m.add(ci.NetTotalBytes, rx+tx)
+		}
+		result = append(result, m)
+	}
+
+	if len(sums) > 0 {
+		for name, sum := range sums {
+			aggregate.add(name, sum)
+		}
+		aggregate.add(ci.NetTotalBytes, sums[ci.NetRxBytes]+sums[ci.NetTxBytes])
+	}
+	return result
+}
+
+// diskIOMeasurements returns a measurement per block device
+func (c *Cadvisor) diskIOMeasurements(ioType string, key string, stats map[string]*diskIOStats, devices map[string]string,
+	tags map[string]string, now time.Time) []*measurement {
+	var result []*measurement
+	for majorMinor, device := range stats {
+		name, ok := devices[majorMinor]
+		if !ok {
+			name = majorMinor
+		}
+		deviceTags := copyTags(tags)
+		deviceTags[ci.DiskDev] = name
+		if ci.IsNode(ioType) || ci.IsInstance(ioType) {
+			addNonEmpty(deviceTags, ci.HostEbsVolumeID, c.hostInfo.GetEbsVolumeID(name))
+		}
+		m := newMeasurement(ioType, deviceTags)
+
+		for _, op := range []string{ci.DiskIOAsync, ci.DiskIORead, ci.DiskIOSync, ci.DiskIOWrite, ci.DiskIOTotal} {
+			if value, ok := device.serviceBytes[op]; ok {
+				name := strings.ToLower(ci.DiskIOServiceBytesPrefix + op)
+				if rate, ok := c.rates.rate(key+"/"+majorMinor+"/"+name, value, now); ok {
+					m.add(name, rate)
+				}
+			}
+			if value, ok := device.serviced[op]; ok {
+				name := strings.ToLower(ci.DiskIOServicedPrefix + op)
+				if rate, ok := c.rates.rate(key+"/"+majorMinor+"/"+name, value, now); ok {
+					m.add(name, rate)
+				}
+			}
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func (c *Cadvisor) filesystemMeasurement(fsType string, fs *filesystemStats) *measurement {
+	tags := map[string]string{}
+	addNonEmpty(tags, ci.DiskDev, fs.device)
+	addNonEmpty(tags, ci.FSType, fs.fsType)
+	if fs.device != "" {
+		addNonEmpty(tags, ci.HostEbsVolumeID, c.hostInfo.GetEbsVolumeID(fs.device))
+	}
+
+	m := newMeasurement(fsType, tags)
+	m.add(ci.FSCapacity, fs.capacity)
+	m.add(ci.FSAvailable, fs.available)
+	m.add(ci.FSUsage, fs.usage)
+	m.add(ci.FSInodes, fs.inodes)
+	m.add(ci.FSInodesfree, fs.inodesFree)
+	if fs.capacity > 0 {
+		m.add(ci.FSUtilization, float64(fs.usage)/float64(fs.capacity)*100)
+	}
+	return m
+}
+
+// decorate adds the tags common to all the metrics
+func (c *Cadvisor) decorate(tags map[string]string, now time.Time) {
+	tags[ci.Timestamp] = strconv.FormatInt(now.UnixNano(), 10)
+	tags[ci.Version] = version
+	tags[ci.SourcesKey] = sources
+	addNonEmpty(tags, ci.NodeNameKey, c.nodeName)
+	addNonEmpty(tags, ci.ClusterNameKey, c.hostInfo.GetClusterName())
+	addNonEmpty(tags, ci.InstanceID, c.hostInfo.GetInstanceID())
+	addNonEmpty(tags, ci.InstanceType, c.hostInfo.GetInstanceType())
+	addNonEmpty(tags, ci.AutoScalingGroupNameKey, c.hostInfo.GetAutoScalingGroupName())
+}
+
+func newMeasurement(metricType string, tags map[string]string) *measurement {
+	return &measurement{
+		metricType: metricType,
+		fields:     map[string]interface{}{},
+		tags:       copyTags(tags),
+	}
+}
+
+// add sets the field of the given measurement name, prefixed according to the metric type
+func (m *measurement) add(name string, value interface{}) {
+	m.fields[ci.MetricName(m.metricType, name)] = value
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	result := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		result[k] = v
+	}
+	return result
+}
+
+func addNonEmpty(tags map[string]string, key string, value string) {
+	if value != "" {
+		tags[key] = value
+	}
 }
@@ -17,18 +17,207 @@
 package cadvisor
 
 import (
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/host"
 )
 
+const (
+	testPodUID      = "0b4d5c5e-8a6f-4b4e-9f7a-1c2d3e4f5a6b"
+	testContainerID = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+type mockHostInfo struct{}
+
+func (m *mockHostInfo) GetNumCores() int64       { return 2 }
+func (m *mockHostInfo) GetMemoryCapacity() int64 { return 0 }
+func (m *mockHostInfo) GetClusterName() string   { return "cluster" }
+func (m *mockHostInfo) GetInstanceID() string    { return "i-1234" }
+func (m *mockHostInfo) GetInstanceType() string  { return "m5.large" }
+func (m *mockHostInfo) GetAutoScalingGroupName() string {
+	return "asg"
+}
+func (m *mockHostInfo) GetEbsVolumeID(devName string) string {
+	if devName == "/dev/nvme0n1" || devName == "/dev/nvme0n1p1" {
+		return "vol-1234"
+	}
+	return ""
+}
+
 func TestGetMetrics(t *testing.T) {
 	machineInfo := host.NewMachineInfo(time.Minute, zap.NewNop())
 	c := New("eks", machineInfo, zap.NewNop())
 	assert.NotNil(t, c)
 	assert.NotNil(t, c.GetMetrics())
 }
+
+// metricsByType indexes the metric values of each pdata.Metrics by their metric type
+func metricsByType(t *testing.T, mds []pdata.Metrics) map[string][]map[string]interface{} {
+	result := map[string][]map[string]interface{}{}
+	for _, md := range mds {
+		rm := md.ResourceMetrics().At(0)
+		metricType, ok := rm.Resource().Attributes().Get(ci.MetricType)
+		require.True(t, ok)
+
+		values := map[string]interface{}{}
+		rm.Resource().Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+			values[k] = v.StringVal()
+			return true
+		})
+		ilms := rm.InstrumentationLibraryMetrics()
+		for i := 0; i < ilms.Len(); i++ {
+			metric := ilms.At(i).Metrics().At(0)
+			switch metric.DataType() {
+			case pdata.MetricDataTypeIntGauge:
+				values[metric.Name()] = metric.IntGauge().DataPoints().At(0).Value()
+			case pdata.MetricDataTypeDoubleGauge:
+				values[metric.Name()] = metric.DoubleGauge().DataPoints().At(0).Value()
+			}
+		}
+		result[metricType.StringVal()] = append(result[metricType.StringVal()], values)
+	}
+	return result
+}
+
+func TestGetMetricsFromFixture(t *testing.T) {
+	c := newCadvisor(ci.EKS, &mockHostInfo{}, zap.NewNop(), "testdata/cgroup", "testdata/proc", "testdata")
+	c.nodeName = "node-1"
+	now := time.Unix(1622548800, 0)
+	c.now = func() time.Time { return now }
+
+	// the first collection has no rates
+	byType := metricsByType(t, c.GetMetrics())
+	require.Len(t, byType[ci.TypeNode], 1)
+	node := byType[ci.TypeNode][0]
+	assert.Equal(t, int64(2000), node["node_cpu_limit"])
+	assert.NotContains(t, node, "node_cpu_usage_total")
+	assert.Equal(t, float64(8388608*1024), node["node_memory_limit"])
+	assert.Equal(t, float64(4294967296), node["node_memory_usage"])
+	assert.Equal(t, float64(4294967296-1073741824), node["node_memory_working_set"])
+	assert.InDelta(t, 37.5, node["node_memory_utilization"], 0.001)
+	assert.Equal(t, "node-1", node[ci.NodeNameKey])
+	assert.Equal(t, "cluster", node[ci.ClusterNameKey])
+	assert.Equal(t, "i-1234", node[ci.InstanceID])
+	assert.Equal(t, "m5.large", node[ci.InstanceType])
+	assert.Equal(t, "asg", node[ci.AutoScalingGroupNameKey])
+	assert.Equal(t, "1622548800000", node[ci.Timestamp])
+	assert.NotContains(t, byType, ci.TypeNodeNet)
+	assert.NotContains(t, byType, ci.TypeNodeDiskIO)
+
+	require.Len(t, byType[ci.TypeNodeFS], 1)
+	fs := byType[ci.TypeNodeFS][0]
+	assert.Equal(t, "/dev/nvme0n1p1", fs[ci.DiskDev])
+	assert.Equal(t, "xfs", fs[ci.FSType])
+	assert.Equal(t, "vol-1234", fs[ci.HostEbsVolumeID])
+	assert.Contains(t, fs, "node_filesystem_capacity")
+	assert.Contains(t, fs, "node_filesystem_utilization")
+
+	require.Len(t, byType[ci.TypePod], 1)
+	pod := byType[ci.TypePod][0]
+	assert.Equal(t, testPodUID, pod[ci.PodIDKey])
+	assert.Equal(t, float64(104857600), pod["pod_memory_usage"])
+
+	require.Len(t, byType[ci.TypeContainer], 1)
+	container := byType[ci.TypeContainer][0]
+	assert.Equal(t, testPodUID, container[ci.PodIDKey])
+	assert.Equal(t, testContainerID, container[ci.ContainerIDkey])
+	assert.Equal(t, float64(83886080), container["container_memory_usage"])
+
+	// the second collection has the rates of the counters, which are all 0
+	// since the fixture doesn't change
+	now = now.Add(time.Minute)
+	byType = metricsByType(t, c.GetMetrics())
+	node = byType[ci.TypeNode][0]
+	assert.Equal(t, float64(0), node["node_cpu_usage_total"])
+	assert.Equal(t, float64(0), node["node_cpu_utilization"])
+	assert.Equal(t, float64(0), node["node_memory_pgfault"])
+	assert.Equal(t, float64(0), node["node_network_total_bytes"])
+
+	require.Len(t, byType[ci.TypeNodeNet], 1)
+	nodeNet := byType[ci.TypeNodeNet][0]
+	assert.Equal(t, "eth0", nodeNet[ci.NetIfce])
+	assert.Equal(t, float64(0), nodeNet["node_interface_network_rx_bytes"])
+
+	require.Len(t, byType[ci.TypeNodeDiskIO], 1)
+	nodeDiskIO := byType[ci.TypeNodeDiskIO][0]
+	assert.Equal(t, "/dev/nvme0n1", nodeDiskIO[ci.DiskDev])
+	assert.Equal(t, "vol-1234", nodeDiskIO[ci.HostEbsVolumeID])
+	assert.Equal(t, float64(0), nodeDiskIO["node_diskio_io_service_bytes_read"])
+	assert.Equal(t, float64(0), nodeDiskIO["node_diskio_io_serviced_total"])
+
+	pod = byType[ci.TypePod][0]
+	assert.Equal(t, float64(0), pod["pod_cpu_usage_total"])
+	assert.Equal(t, float64(0), pod["pod_network_rx_bytes"])
+
+	require.Len(t, byType[ci.TypePodNet], 1)
+	podNet := byType[ci.TypePodNet][0]
+	assert.Equal(t, "eth0", podNet[ci.NetIfce])
+	assert.Equal(t, testPodUID, podNet[ci.PodIDKey])
+
+	require.Len(t, byType[ci.TypeContainerDiskIO], 1)
+	containerDiskIO := byType[ci.TypeContainerDiskIO][0]
+	assert.Equal(t, testContainerID, containerDiskIO[ci.ContainerIDkey])
+	assert.NotContains(t, containerDiskIO, ci.HostEbsVolumeID)
+}
+
+func TestGetMetricsECS(t *testing.T) {
+	c := newCadvisor("ecs", &mockHostInfo{}, zap.NewNop(), "testdata/cgroup", "testdata/proc", "testdata")
+
+	byType := metricsByType(t, c.GetMetrics())
+	assert.Len(t, byType[ci.TypeInstance], 1)
+	assert.Len(t, byType[ci.TypeInstanceFS], 1)
+	assert.NotContains(t, byType, ci.TypeNode)
+	assert.NotContains(t, byType, ci.TypePod)
+}
+
+func TestRateCalculator(t *testing.T) {
+	r := newRateCalculator()
+	now := time.Unix(1622548800, 0)
+
+	_, ok := r.rate("key", 100, now)
+	assert.False(t, ok)
+
+	rate, ok := r.rate("key", 400, now.Add(10*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, float64(30), rate)
+
+	// too close to the previous value
+	_, ok = r.rate("key", 500, now.Add(10*time.Second+time.Microsecond))
+	assert.False(t, ok)
+
+	// counter reset
+	_, ok = r.rate("key", 10, now.Add(20*time.Second))
+	assert.False(t, ok)
+
+	rate, ok = r.rate("key", 20, now.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), rate)
+
+	// samples not updated during a collection are dropped
+	r.endCollection()
+	r.endCollection()
+	_, ok = r.rate("key", 30, now.Add(40*time.Second))
+	assert.False(t, ok)
+}
+
+func TestDiscoverPodsSystemd(t *testing.T) {
+	root := t.TempDir()
+	podDir := root + "/cpuacct/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0b4d5c5e_8a6f_4b4e_9f7a_1c2d3e4f5a6b.slice"
+	require.NoError(t, os.MkdirAll(podDir+"/cri-containerd-"+testContainerID+".scope", 0755))
+	require.NoError(t, os.MkdirAll(podDir+"/not-a-container", 0755))
+
+	pods, err := discoverPods(root)
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, testPodUID, pods[0].uid)
+	require.Len(t, pods[0].containers, 1)
+	assert.Equal(t, testContainerID, pods[0].containers[0].id)
+}
@@ -0,0 +1,310 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package cadvisor
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// cgroup v1 subsystems read by the provider
+	cpuacctSubsystem = "cpuacct"
+	memorySubsystem  = "memory"
+	blkioSubsystem   = "blkio"
+
+	// kubepodsCgroup is the parent cgroup of the pods created by kubelet
+	kubepodsCgroup = "kubepods"
+
+	// userHZ is the unit of the cpuacct.stat values, it is 100 on all the
+	// architectures supported by EKS.
+	userHZ = 100
+)
+
+var (
+	// The pod cgroup is named pod<uid> with the cgroupfs driver, and
+	// kubepods-<qos>-pod<uid with underscores>.slice with the systemd driver.
+	podCgroupRegexp = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(\.slice)?$`)
+	// The container cgroup is named after the container id, possibly with a
+	// runtime prefix like docker- or cri-containerd- and the systemd .scope
+	// suffix.
+	containerCgroupRegexp = regexp.MustCompile(`([0-9a-f]{64})(\.scope)?$`)
+)
+
+// cgroupStats contains the raw values read from the cgroup of a node, pod or container.
+type cgroupStats struct {
+	cpu    cpuStats
+	memory memoryStats
+	// diskIO is keyed by the "major:minor" number of the device
+	diskIO map[string]*diskIOStats
+}
+
+// cpuStats contains the cumulative cpu usage in nanoseconds
+type cpuStats struct {
+	total  uint64
+	user   uint64
+	system uint64
+}
+
+type memoryStats struct {
+	usage                  uint64
+	maxUsage               uint64
+	failcnt                uint64
+	cache                  uint64
+	rss                    uint64
+	mappedFile             uint64
+	swap                   uint64
+	workingSet             uint64
+	pgfault                uint64
+	pgmajfault             uint64
+	hierarchicalPgfault    uint64
+	hierarchicalPgmajfault uint64
+}
+
+// diskIOStats contains the cumulative io bytes and operations of a device, keyed by operation (Read, Write, ...)
+type diskIOStats struct {
+	serviceBytes map[string]uint64
+	serviced     map[string]uint64
+}
+
+// podCgroup is the cgroup of a kubernetes pod
+type podCgroup struct {
+	uid string
+	// path of the cgroup, relative to the subsystem hierarchy
+	path       string
+	containers []containerCgroup
+}
+
+type containerCgroup struct {
+	id   string
+	path string
+}
+
+// readCgroupStats reads the stats of the cgroup at path, relative to the subsystem hierarchies under cgroupRoot.
+// The cpu stats are required, the memory and blkio stats are skipped when the subsystem is not mounted.
+func readCgroupStats(cgroupRoot string, path string) (*cgroupStats, error) {
+	stats := &cgroupStats{diskIO: map[string]*diskIOStats{}}
+
+	cpuDir := filepath.Join(cgroupRoot, cpuacctSubsystem, path)
+	total, err := readUint(filepath.Join(cpuDir, "cpuacct.usage"))
+	if err != nil {
+		return nil, err
+	}
+	stats.cpu.total = total
+	cpuStat, err := readKeyValues(filepath.Join(cpuDir, "cpuacct.stat"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	stats.cpu.user = cpuStat["user"] * (1e9 / userHZ)
+	stats.cpu.system = cpuStat["system"] * (1e9 / userHZ)
+
+	if err := readMemoryStats(filepath.Join(cgroupRoot, memorySubsystem, path), &stats.memory); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	blkioDir := filepath.Join(cgroupRoot, blkioSubsystem, path)
+	if err := readBlkio(filepath.Join(blkioDir, "blkio.throttle.io_service_bytes"), stats.diskIO, func(s *diskIOStats) map[string]uint64 {
+		return s.serviceBytes
+	}); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := readBlkio(filepath.Join(blkioDir, "blkio.throttle.io_serviced"), stats.diskIO, func(s *diskIOStats) map[string]uint64 {
+		return s.serviced
+	}); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func readMemoryStats(dir string, stats *memoryStats) error {
+	var err error
+	if stats.usage, err = readUint(filepath.Join(dir, "memory.usage_in_bytes")); err != nil {
+		return err
+	}
+	if stats.maxUsage, err = readUint(filepath.Join(dir, "memory.max_usage_in_bytes")); err != nil {
+		return err
+	}
+	if stats.failcnt, err = readUint(filepath.Join(dir, "memory.failcnt")); err != nil {
+		return err
+	}
+	memStat, err := readKeyValues(filepath.Join(dir, "memory.stat"))
+	if err != nil {
+		return err
+	}
+
+	// The total_ values include the descendant cgroups, the same as the usage.
+	stats.cache = memStat["total_cache"]
+	stats.rss = memStat["total_rss"]
+	stats.mappedFile = memStat["total_mapped_file"]
+	stats.swap = memStat["total_swap"]
+	stats.pgfault = memStat["pgfault"]
+	stats.pgmajfault = memStat["pgmajfault"]
+	stats.hierarchicalPgfault = memStat["total_pgfault"]
+	stats.hierarchicalPgmajfault = memStat["total_pgmajfault"]
+
+	// The working set is the memory that cannot be reclaimed under pressure,
+	// computed the same way as cadvisor.
+	if inactiveFile := memStat["total_inactive_file"]; inactiveFile < stats.usage {
+		stats.workingSet = stats.usage - inactiveFile
+	}
+	return nil
+}
+
+// readBlkio reads a blkio file made of "major:minor operation value" lines into the stats of each device.
+func readBlkio(path string, devices map[string]*diskIOStats, values func(*diskIOStats) map[string]uint64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// skip the "Total <value>" line summing all the devices
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		device, ok := devices[fields[0]]
+		if !ok {
+			device = &diskIOStats{serviceBytes: map[string]uint64{}, serviced: map[string]uint64{}}
+			devices[fields[0]] = device
+		}
+		values(device)[fields[1]] = value
+	}
+	return scanner.Err()
+}
+
+// discoverPods finds the cgroups of the pods and their containers under the kubepods cgroup.
+func discoverPods(cgroupRoot string) ([]podCgroup, error) {
+	subsystemDir := filepath.Join(cgroupRoot, cpuacctSubsystem)
+	var pods []podCgroup
+
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		match := podCgroupRegexp.FindStringSubmatch(info.Name())
+		if match == nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(subsystemDir, path)
+		if err != nil {
+			return err
+		}
+		pod := podCgroup{
+			uid:  strings.ReplaceAll(match[1], "_", "-"),
+			path: relPath,
+		}
+		children, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if !child.IsDir() {
+				continue
+			}
+			if match := containerCgroupRegexp.FindStringSubmatch(child.Name()); match != nil {
+				pod.containers = append(pod.containers, containerCgroup{
+					id:   match[1],
+					path: filepath.Join(relPath, child.Name()),
+				})
+			}
+		}
+		pods = append(pods, pod)
+		return filepath.SkipDir
+	}
+
+	// The kubepods cgroup is named kubepods.slice with the systemd driver.
+	for _, kubepods := range []string{kubepodsCgroup, kubepodsCgroup + ".slice"} {
+		if err := filepath.Walk(filepath.Join(subsystemDir, kubepods), walkFn); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return pods, nil
+}
+
+// cgroupPid returns a process running in the cgroup at path or one of its children, or "" if there is none.
+func cgroupPid(cgroupRoot string, path string) string {
+	var pid string
+	filepath.Walk(filepath.Join(cgroupRoot, cpuacctSubsystem, path), func(path string, info os.FileInfo, err error) error {
+		if err != nil || pid != "" {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if info.Name() != "cgroup.procs" {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if fields := strings.Fields(string(content)); len(fields) > 0 {
+			pid = fields[0]
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return pid
+}
+
+func readUint(path string) (uint64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// readKeyValues reads a file made of "key value" lines, lines that cannot be parsed are skipped.
+func readKeyValues(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			result[fields[0]] = value
+		}
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,198 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package cadvisor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ignoredInterfacePrefixes are the interfaces excluded from the node network
+// metrics: the loopback and the host side of the pod and bridge interfaces,
+// whose traffic is already reported by the pods.
+var ignoredInterfacePrefixes = []string{"lo", "veth", "eni", "cali", "docker", "cni"}
+
+// interfaceStats contains the cumulative counters of a network interface
+type interfaceStats struct {
+	name      string
+	rxBytes   uint64
+	rxPackets uint64
+	rxErrors  uint64
+	rxDropped uint64
+	txBytes   uint64
+	txPackets uint64
+	txErrors  uint64
+	txDropped uint64
+}
+
+// filesystemStats contains the usage of a mounted filesystem
+type filesystemStats struct {
+	device     string
+	fsType     string
+	capacity   uint64
+	available  uint64
+	usage      uint64
+	inodes     uint64
+	inodesFree uint64
+}
+
+// readNetDev reads the interfaces from a /proc/<pid>/net/dev file, skipping
+// the ones with the given prefixes.
+func readNetDev(path string, ignoredPrefixes []string) ([]interfaceStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []interfaceStats
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// lines look like "  eth0: 1234 12 0 0 0 0 0 0 5678 34 0 0 0 0 0 0",
+		// the two header lines don't have a colon followed by 16 values
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if hasAnyPrefix(name, ignoredPrefixes) {
+			continue
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) < 16 {
+			continue
+		}
+		values := make([]uint64, 16)
+		valid := true
+		for i := range values {
+			if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			continue
+		}
+		result = append(result, interfaceStats{
+			name:      name,
+			rxBytes:   values[0],
+			rxPackets: values[1],
+			rxErrors:  values[2],
+			rxDropped: values[3],
+			txBytes:   values[8],
+			txPackets: values[9],
+			txErrors:  values[10],
+			txDropped: values[11],
+		})
+	}
+	return result, scanner.Err()
+}
+
+// readDiskDevices maps the "major:minor" numbers of the block devices to
+// their names, using /proc/diskstats.
+func readDiskDevices(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		result[fields[0]+":"+fields[1]] = "/dev/" + fields[2]
+	}
+	return result, scanner.Err()
+}
+
+// readMemTotal returns the MemTotal of /proc/meminfo in bytes.
+func readMemTotal(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the line looks like "MemTotal:       16085120 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, os.ErrNotExist
+}
+
+// readRootFilesystem returns the usage of the filesystem mounted at rootfs,
+// the device and type are looked up in /proc/mounts for the "/" mount point.
+func readRootFilesystem(rootfs string, mountsPath string) (*filesystemStats, error) {
+	var statfs syscall.Statfs_t
+	if err := syscall.Statfs(rootfs, &statfs); err != nil {
+		return nil, err
+	}
+
+	bsize := uint64(statfs.Bsize)
+	stats := &filesystemStats{
+		capacity:   statfs.Blocks * bsize,
+		available:  statfs.Bavail * bsize,
+		usage:      (statfs.Blocks - statfs.Bfree) * bsize,
+		inodes:     statfs.Files,
+		inodesFree: statfs.Ffree,
+	}
+
+	f, err := os.Open(mountsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the line looks like "/dev/nvme0n1p1 / xfs rw,noatime 0 0"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && filepath.Clean(fields[1]) == "/" && strings.HasPrefix(fields[0], "/dev/") {
+			stats.device = fields[0]
+			stats.fsType = fields[2]
+		}
+	}
+	return stats, scanner.Err()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
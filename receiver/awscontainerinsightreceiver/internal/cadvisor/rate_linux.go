@@ -0,0 +1,73 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package cadvisor
+
+import (
+	"time"
+
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
+)
+
+// rateCalculator turns cumulative counters into per second rates, using the
+// value of the previous collection.
+type rateCalculator struct {
+	samples map[string]rateSample
+	// generation is incremented on every collection, the samples that were
+	// not updated during the last collection belong to cgroups or devices
+	// that are gone and are dropped.
+	generation uint64
+}
+
+type rateSample struct {
+	value      uint64
+	timestamp  time.Time
+	generation uint64
+}
+
+func newRateCalculator() *rateCalculator {
+	return &rateCalculator{samples: map[string]rateSample{}}
+}
+
+// rate records the value of the counter identified by key and returns its
+// rate per second since the previous value. There is no rate for the first
+// value, or when the counter was reset.
+func (r *rateCalculator) rate(key string, value uint64, timestamp time.Time) (float64, bool) {
+	prev, ok := r.samples[key]
+	if ok && value >= prev.value && timestamp.Sub(prev.timestamp) < ci.MinTimeDiff {
+		// too close to the previous value for a meaningful rate, keep the
+		// previous value for the next collection
+		prev.generation = r.generation
+		r.samples[key] = prev
+		return 0, false
+	}
+
+	r.samples[key] = rateSample{value: value, timestamp: timestamp, generation: r.generation}
+	if !ok || value < prev.value {
+		return 0, false
+	}
+	return float64(value-prev.value) / timestamp.Sub(prev.timestamp).Seconds(), true
+}
+
+// endCollection drops the samples not updated since the previous call.
+func (r *rateCalculator) endCollection() {
+	for key, sample := range r.samples {
+		if sample.generation != r.generation {
+			delete(r.samples, key)
+		}
+	}
+	r.generation++
+}
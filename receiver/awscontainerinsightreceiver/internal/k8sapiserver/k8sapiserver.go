@@ -16,16 +16,67 @@ package k8sapiserver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	k8s "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+const (
+	// lockName is the name of the configmap used as leader election lock
+	lockName = "otel-container-insight-clusterleader"
+
+	// nodeNameEnv is the environment variable holding the kubernetes node
+	// name, it identifies the receiver in the leader election.
+	nodeNameEnv = "HOST_NAME"
+	// namespaceEnv is the environment variable holding the namespace of the
+	// receiver pod, where the lock is created.
+	namespaceEnv     = "K8S_NAMESPACE"
+	defaultNamespace = "default"
+
+	leaseDuration = 60 * time.Second
+	renewDeadline = 15 * time.Second
+	retryPeriod   = 5 * time.Second
+
+	version = "0"
 )
 
 // K8sAPIServer is a struct that produces metrics from kubernetes api server
 type K8sAPIServer struct {
 	logger              *zap.Logger
 	clusterNameProvider clusterNameProvider
+	client              k8s.Interface
+	nodeName            string
+	namespace           string
 	cancel              context.CancelFunc
+
+	// leading is true while this receiver is the elected leader, the only
+	// one generating the cluster-level metrics
+	leading *atomic.Bool
+
+	// the listers read the nodes, pods and endpoints from the caches of the
+	// informers, which are only run by the leader
+	mu              sync.Mutex
+	nodeLister      corelisters.NodeLister
+	podLister       corelisters.PodLister
+	endpointsLister corelisters.EndpointsLister
 }
 
 type clusterNameProvider interface {
@@ -34,11 +85,25 @@ type clusterNameProvider interface {
 
 // New creates a k8sApiServer which can generate cluster-level metrics
 func New(clusterNameProvider clusterNameProvider, logger *zap.Logger) *K8sAPIServer {
-	_, cancel := context.WithCancel(context.Background())
+	client, err := k8sconfig.MakeClient(k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount})
+	if err != nil {
+		logger.Warn("Fail to create the kubernetes client", zap.Error(err))
+		return nil
+	}
+	return newK8sAPIServer(clusterNameProvider, client, logger)
+}
+
+func newK8sAPIServer(clusterNameProvider clusterNameProvider, client k8s.Interface, logger *zap.Logger) *K8sAPIServer {
 	k := &K8sAPIServer{
 		logger:              logger,
 		clusterNameProvider: clusterNameProvider,
-		cancel:              cancel,
+		client:              client,
+		nodeName:            os.Getenv(nodeNameEnv),
+		namespace:           os.Getenv(namespaceEnv),
+		leading:             atomic.NewBool(false),
+	}
+	if k.namespace == "" {
+		k.namespace = defaultNamespace
 	}
 
 	if err := k.start(); err != nil {
@@ -49,15 +114,219 @@ func New(clusterNameProvider clusterNameProvider, logger *zap.Logger) *K8sAPISer
 	return k
 }
 
-// GetMetrics returns an array of metrics
+// GetMetrics returns an array of metrics, which is empty unless the receiver is the elected leader
 func (k *K8sAPIServer) GetMetrics() []pdata.Metrics {
-	// TODO: add the logic to generate the metrics
 	var result []pdata.Metrics
+	if !k.leading.Load() {
+		return result
+	}
+
+	k.mu.Lock()
+	nodeLister, podLister, endpointsLister := k.nodeLister, k.podLister, k.endpointsLister
+	k.mu.Unlock()
+	nodes, err := nodeLister.List(labels.Everything())
+	if err != nil {
+		k.logger.Warn("Fail to list the nodes", zap.Error(err))
+		return result
+	}
+	pods, err := podLister.List(labels.Everything())
+	if err != nil {
+		k.logger.Warn("Fail to list the pods", zap.Error(err))
+		return result
+	}
+	endpoints, err := endpointsLister.List(labels.Everything())
+	if err != nil {
+		k.logger.Warn("Fail to list the endpoints", zap.Error(err))
+		return result
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)
+	clusterName := k.clusterNameProvider.GetClusterName()
+	newTags := func(metricType string) map[string]string {
+		tags := map[string]string{
+			ci.MetricType: metricType,
+			ci.Timestamp:  timestamp,
+			ci.Version:    version,
+		}
+		if clusterName != "" {
+			tags[ci.ClusterNameKey] = clusterName
+		}
+		if k.nodeName != "" {
+			tags[ci.NodeNameKey] = k.nodeName
+		}
+		return tags
+	}
+
+	// cluster
+	fields := map[string]interface{}{
+		ci.MetricName(ci.TypeCluster, ci.NodeCount):       len(nodes),
+		ci.MetricName(ci.TypeCluster, ci.FailedNodeCount): failedNodeCount(nodes),
+	}
+	result = append(result, ci.ConvertToOTLPMetrics(fields, newTags(ci.TypeCluster), k.logger))
+
+	// namespaces
+	runningPods := map[string]map[string]bool{}
+	runningPodsPerNamespace := map[string]int{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		runningPodsPerNamespace[pod.Namespace]++
+		if runningPods[pod.Namespace] == nil {
+			runningPods[pod.Namespace] = map[string]bool{}
+		}
+		runningPods[pod.Namespace][pod.Name] = true
+	}
+	for namespace, count := range runningPodsPerNamespace {
+		fields := map[string]interface{}{
+			ci.MetricName(ci.TypeClusterNamespace, ci.RunningPodCount): count,
+		}
+		tags := newTags(ci.TypeClusterNamespace)
+		tags[ci.K8sNamespace] = namespace
+		result = append(result, ci.ConvertToOTLPMetrics(fields, tags, k.logger))
+	}
+
+	// services, the pods of a service are the ones of its endpoints
+	for _, endpoint := range endpoints {
+		count := 0
+		for _, pod := range endpointPods(endpoint) {
+			if runningPods[endpoint.Namespace][pod] {
+				count++
+			}
+		}
+		fields := map[string]interface{}{
+			ci.MetricName(ci.TypeClusterService, ci.RunningPodCount): count,
+		}
+		tags := newTags(ci.TypeClusterService)
+		tags[ci.K8sNamespace] = endpoint.Namespace
+		tags[ci.ServiceNameKey] = endpoint.Name
+		result = append(result, ci.ConvertToOTLPMetrics(fields, tags, k.logger))
+	}
+
 	return result
 }
 
+// failedNodeCount returns the number of nodes that are not ready
+func failedNodeCount(nodes []*corev1.Node) int {
+	failed := 0
+	for _, node := range nodes {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			failed++
+		}
+	}
+	return failed
+}
+
+// endpointPods returns the names of the pods backing an endpoint, once each
+func endpointPods(endpoint *corev1.Endpoints) []string {
+	seen := map[string]bool{}
+	var pods []string
+	for _, subset := range endpoint.Subsets {
+		for _, addresses := range [][]corev1.EndpointAddress{subset.Addresses, subset.NotReadyAddresses} {
+			for _, address := range addresses {
+				if address.TargetRef == nil || address.TargetRef.Kind != "Pod" || seen[address.TargetRef.Name] {
+					continue
+				}
+				seen[address.TargetRef.Name] = true
+				pods = append(pods, address.TargetRef.Name)
+			}
+		}
+	}
+	return pods
+}
+
+// start joins the leader election, only the elected receiver of the cluster generates the metrics
 func (k *K8sAPIServer) start() error {
-	//TODO: add implementation
+	identity := k.nodeName
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+		identity = hostname
+	}
+	if identity == "" {
+		return errors.New("missing identity for the leader election")
+	}
+
+	lock := &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{
+			Namespace: k.namespace,
+			Name:      lockName,
+		},
+		Client: k.client.CoreV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				k.logger.Info("k8sapiserver became the leader", zap.String("identity", identity))
+				// the informers run until the leadership is lost
+				if err := k.startInformers(ctx); err != nil {
+					k.logger.Warn("Fail to sync the k8sapiserver informers", zap.Error(err))
+					return
+				}
+				k.leading.Store(true)
+			},
+			OnStoppedLeading: func() {
+				k.logger.Info("k8sapiserver lost the leadership", zap.String("identity", identity))
+				k.leading.Store(false)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	k.cancel = cancel
+	go func() {
+		// Run returns when the leadership is lost, run it again to become a
+		// candidate until the receiver is stopped.
+		for ctx.Err() == nil {
+			elector.Run(ctx)
+		}
+	}()
+	return nil
+}
+
+// startInformers runs the informers of the nodes, pods and endpoints until ctx
+// is done, and waits for their caches to be synced, so that the metrics are
+// generated from the caches instead of listing the whole cluster on each
+// collection.
+func (k *K8sAPIServer) startInformers(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactory(k.client, 0)
+	nodeLister := factory.Core().V1().Nodes().Lister()
+	podLister := factory.Core().V1().Pods().Lister()
+	endpointsLister := factory.Core().V1().Endpoints().Lister()
+
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync the cache of the %v informer", informerType)
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.nodeLister = nodeLister
+	k.podLister = podLister
+	k.endpointsLister = endpointsLister
 	return nil
 }
 
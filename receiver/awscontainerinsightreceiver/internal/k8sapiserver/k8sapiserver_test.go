@@ -15,20 +15,153 @@
 package k8sapiserver
 
 import (
+	"context"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/host"
 )
 
-func TestK8sapiserver(t *testing.T) {
+type mockClusterNameProvider struct{}
+
+func (m mockClusterNameProvider) GetClusterName() string {
+	return "cluster-name"
+}
+
+func TestK8sapiserverWithoutCluster(t *testing.T) {
+	// there is no kubernetes service to connect to
 	machineInfo := host.NewMachineInfo(time.Minute, zap.NewNop())
-	k := New(machineInfo, zap.NewNop())
-	k.start()
-	assert.NotNil(t, k)
-	assert.Nil(t, k.GetMetrics())
-	k.Stop()
+	assert.Nil(t, New(machineInfo, zap.NewNop()))
+}
+
+func TestK8sapiserverLeaderElection(t *testing.T) {
+	os.Setenv(nodeNameEnv, "node-1")
+	defer os.Unsetenv(nodeNameEnv)
+	client := fake.NewSimpleClientset()
+	k := newK8sAPIServer(mockClusterNameProvider{}, client, zap.NewNop())
+	require.NotNil(t, k)
+	defer k.Stop()
+
+	// the leader generates the metrics once the informers are synced
+	require.Eventually(t, k.leading.Load, 10*time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, k.GetMetrics())
+	lock, err := client.CoreV1().ConfigMaps(defaultNamespace).Get(context.Background(), lockName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, lock.Annotations["control-plane.alpha.kubernetes.io/leader"], `"holderIdentity":"node-1"`)
+}
+
+func TestK8sapiserverNotLeader(t *testing.T) {
+	k := &K8sAPIServer{
+		logger:              zap.NewNop(),
+		clusterNameProvider: mockClusterNameProvider{},
+		client:              fake.NewSimpleClientset(),
+	}
+	k.leading = atomic.NewBool(false)
+	assert.Empty(t, k.GetMetrics())
+}
+
+func TestK8sapiserverGetMetrics(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		node("node-1", corev1.ConditionTrue),
+		node("node-2", corev1.ConditionFalse),
+		node("node-3", corev1.ConditionUnknown),
+		pod("default", "web-1", corev1.PodRunning),
+		pod("default", "web-2", corev1.PodRunning),
+		pod("default", "web-3", corev1.PodPending),
+		pod("kube-system", "coredns-1", corev1.PodRunning),
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses:         []corev1.EndpointAddress{podAddress("web-1"), podAddress("web-2")},
+				NotReadyAddresses: []corev1.EndpointAddress{podAddress("web-3")},
+			}},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kubernetes"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			}},
+		},
+	)
+	k := &K8sAPIServer{
+		logger:              zap.NewNop(),
+		clusterNameProvider: mockClusterNameProvider{},
+		client:              client,
+		nodeName:            "node-1",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, k.startInformers(ctx))
+	k.leading = atomic.NewBool(true)
+	// the objects are read from the caches of the informers
+	client.ClearActions()
+
+	byType := map[string][]map[string]interface{}{}
+	for _, md := range k.GetMetrics() {
+		rm := md.ResourceMetrics().At(0)
+		values := map[string]interface{}{}
+		rm.Resource().Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+			values[k] = v.StringVal()
+			return true
+		})
+		ilms := rm.InstrumentationLibraryMetrics()
+		for i := 0; i < ilms.Len(); i++ {
+			metric := ilms.At(i).Metrics().At(0)
+			values[metric.Name()] = metric.IntGauge().DataPoints().At(0).Value()
+		}
+		metricType := values[ci.MetricType].(string)
+		byType[metricType] = append(byType[metricType], values)
+	}
+
+	require.Len(t, byType[ci.TypeCluster], 1)
+	cluster := byType[ci.TypeCluster][0]
+	assert.Equal(t, int64(3), cluster["cluster_node_count"])
+	assert.Equal(t, int64(2), cluster["cluster_failed_node_count"])
+	assert.Equal(t, "cluster-name", cluster[ci.ClusterNameKey])
+	assert.Equal(t, "node-1", cluster[ci.NodeNameKey])
+
+	namespaces := map[string]int64{}
+	for _, values := range byType[ci.TypeClusterNamespace] {
+		namespaces[values[ci.K8sNamespace].(string)] = values["namespace_number_of_running_pods"].(int64)
+	}
+	assert.Equal(t, map[string]int64{"default": 2, "kube-system": 1}, namespaces)
+
+	services := map[string]int64{}
+	for _, values := range byType[ci.TypeClusterService] {
+		assert.Equal(t, "default", values[ci.K8sNamespace])
+		services[values[ci.ServiceNameKey].(string)] = values["service_number_of_running_pods"].(int64)
+	}
+	assert.Equal(t, map[string]int64{"web": 2, "kubernetes": 0}, services)
+	assert.Empty(t, client.Actions())
+}
+
+func node(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func pod(namespace, name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func podAddress(name string) corev1.EndpointAddress {
+	return corev1.EndpointAddress{TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: name}}
 }
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 
+	ci "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/containerinsight"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/cadvisor"
 	hostInfo "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/host"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awscontainerinsightreceiver/internal/k8sapiserver"
@@ -69,7 +70,14 @@ func (acir *awsContainerInsightReceiver) Start(ctx context.Context, host compone
 	ctx, acir.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, acir.config.ID(), "http"))
 	machineInfo := hostInfo.NewMachineInfo(acir.config.CollectionInterval, acir.logger)
 	acir.cadvisor = cadvisor.New(acir.config.ContainerOrchestrator, machineInfo, acir.logger)
-	acir.k8sapiserver = k8sapiserver.New(machineInfo, acir.logger)
+	// the cluster-level metrics are only generated on kubernetes. k8sapiserver
+	// is nil when it fails to start, it must not be stored as a non-nil
+	// MetricsProvider
+	if acir.config.ContainerOrchestrator == ci.EKS {
+		if k8sAPIServer := k8sapiserver.New(machineInfo, acir.logger); k8sAPIServer != nil {
+			acir.k8sapiserver = k8sAPIServer
+		}
+	}
 
 	// TODO: add more intialization code
 
@@ -93,6 +101,9 @@ func (acir *awsContainerInsightReceiver) Start(ctx context.Context, host compone
 // Shutdown stops the awsContainerInsightReceiver receiver.
 func (acir *awsContainerInsightReceiver) Shutdown(context.Context) error {
 	acir.cancel()
+	if k8sAPIServer, ok := acir.k8sapiserver.(*k8sapiserver.K8sAPIServer); ok {
+		k8sAPIServer.Stop()
+	}
 	return nil
 }
 
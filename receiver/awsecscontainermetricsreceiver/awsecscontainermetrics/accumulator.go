@@ -30,6 +30,8 @@ type metricDataAccumulator struct {
 func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*ContainerStats, metadata TaskMetadata, logger *zap.Logger) {
 
 	taskMetrics := ECSMetrics{}
+	// onlineCPUs is the number of CPUs of the instance running the task
+	var onlineCPUs uint64
 	timestamp := pdata.TimestampFromTime(time.Now())
 	taskResource := taskResource(metadata)
 
@@ -48,6 +50,9 @@ func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*C
 			containerMetrics := convertContainerMetrics(stats, logger, containerMetadata)
 			acc.accumulate(convertToOTLPMetrics(ContainerPrefix, containerMetrics, containerResource, timestamp))
 			aggregateTaskMetrics(&taskMetrics, containerMetrics)
+			if containerMetrics.CPUOnlineCpus > onlineCPUs {
+				onlineCPUs = containerMetrics.CPUOnlineCpus
+			}
 
 		} else if containerMetadata.FinishedAt != "" && containerMetadata.StartedAt != "" {
 
@@ -61,7 +66,7 @@ func (acc *metricDataAccumulator) getMetricsData(containerStatsMap map[string]*C
 
 		}
 	}
-	overrideWithTaskLevelLimit(&taskMetrics, metadata)
+	overrideWithTaskLevelLimit(&taskMetrics, metadata, onlineCPUs)
 	acc.accumulate(convertToOTLPMetrics(TaskPrefix, taskMetrics, taskResource, timestamp))
 }
 
@@ -89,7 +94,7 @@ func convertContainerMetrics(stats *ContainerStats, logger *zap.Logger, containe
 	return containerMetrics
 }
 
-func overrideWithTaskLevelLimit(taskMetrics *ECSMetrics, metadata TaskMetadata) {
+func overrideWithTaskLevelLimit(taskMetrics *ECSMetrics, metadata TaskMetadata, onlineCPUs uint64) {
 	// Overwrite Memory limit with task level limit
 	if metadata.Limits.Memory != nil {
 		taskMetrics.MemoryReserved = *metadata.Limits.Memory
@@ -102,12 +107,16 @@ func overrideWithTaskLevelLimit(taskMetrics *ECSMetrics, metadata TaskMetadata)
 		taskMetrics.CPUReserved = *metadata.Limits.CPU
 	}
 
-	// taskMetrics.CPUReserved cannot be zero. In ECS, user needs to set CPU limit
-	// at least in one place (either in task level or in container level). If the
-	// task level CPULimit is not present, we calculate it from the summation of
-	// all container CPU limits.
-	if taskMetrics.CPUReserved > 0 {
-		taskMetrics.CPUUtilized = ((taskMetrics.CPUUsageInVCPU / taskMetrics.CPUReserved) * 100)
+	// If the task level CPULimit is not present, we calculate it from the
+	// summation of all container CPU limits. The tasks on EC2 may have no CPU
+	// limit at all, they can then use all the CPUs of the instance and the
+	// utilization is relative to them.
+	cpuLimit := taskMetrics.CPUReserved
+	if cpuLimit == 0 {
+		cpuLimit = float64(onlineCPUs)
+	}
+	if cpuLimit > 0 {
+		taskMetrics.CPUUtilized = ((taskMetrics.CPUUsageInVCPU / cpuLimit) * 100)
 	}
 }
 
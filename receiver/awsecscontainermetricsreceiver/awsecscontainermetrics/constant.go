@@ -74,6 +74,13 @@ const (
 	AttributeStorageRead  = "storage.read_bytes"
 	AttributeStorageWrite = "storage.write_bytes"
 
+	AttributeStorageDeviceRead  = "storage.device.read_bytes"
+	AttributeStorageDeviceWrite = "storage.device.write_bytes"
+
+	// LabelDevice is the major and minor numbers of the block device of the
+	// storage device metrics.
+	LabelDevice = "device"
+
 	AttributeDuration = "duration"
 
 	UnitBytes       = "Bytes"
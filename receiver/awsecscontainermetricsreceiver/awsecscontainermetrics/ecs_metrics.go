@@ -46,4 +46,14 @@ type ECSMetrics struct {
 
 	StorageReadBytes  uint64
 	StorageWriteBytes uint64
+
+	// StorageDevices are the bytes read and written by block device, keyed
+	// by the major and minor numbers of the devices.
+	StorageDevices map[string]StorageDeviceUsage
+}
+
+// StorageDeviceUsage defines the bytes read and written on a block device
+type StorageDeviceUsage struct {
+	ReadBytes  uint64
+	WriteBytes uint64
 }
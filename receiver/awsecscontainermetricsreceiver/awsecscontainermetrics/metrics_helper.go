@@ -14,41 +14,58 @@
 
 package awsecscontainermetrics
 
-import "go.uber.org/zap"
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
 
 // getContainerMetrics generate ECS Container metrics from Container stats
 func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 	m := ECSMetrics{}
 
 	if stats.Memory != nil {
-		m.MemoryUsage = *stats.Memory.Usage
-		m.MemoryMaxUsage = *stats.Memory.MaxUsage
-		m.MemoryLimit = *stats.Memory.Limit
+		// max_usage is not reported on cgroup v2 hosts
+		m.MemoryUsage = uint64Value(stats.Memory.Usage)
+		m.MemoryMaxUsage = uint64Value(stats.Memory.MaxUsage)
+		m.MemoryLimit = uint64Value(stats.Memory.Limit)
 
 		if stats.Memory.Stats != nil {
-			m.MemoryUtilized = (*stats.Memory.Usage - stats.Memory.Stats["cache"]) / BytesInMiB
+			m.MemoryUtilized = memoryWorkingSet(stats.Memory) / BytesInMiB
 		}
 	} else {
 		logger.Debug("Nil memory stats found for docker container:" + stats.Name)
 	}
 
 	if stats.CPU != nil && stats.CPU.CPUUsage != nil {
+		// percpu_usage is not reported on cgroup v2 hosts, the number of
+		// online CPUs is used instead
 		numOfCores := (uint64)(len(stats.CPU.CPUUsage.PerCPUUsage))
+		if numOfCores == 0 {
+			numOfCores = uint64Value(stats.CPU.OnlineCpus)
+		}
 		timeDiffSinceLastRead := (float64)(stats.Read.Sub(stats.PreviousRead).Nanoseconds())
 
+		totalUsage := uint64Value(stats.CPU.CPUUsage.TotalUsage)
+		var previousTotalUsage uint64
+		if stats.PreviousCPU != nil && stats.PreviousCPU.CPUUsage != nil {
+			previousTotalUsage = uint64Value(stats.PreviousCPU.CPUUsage.TotalUsage)
+		}
+
 		cpuUsageInVCpu := float64(0)
-		if timeDiffSinceLastRead > 0 {
-			cpuDelta := (float64)(*stats.CPU.CPUUsage.TotalUsage - *stats.PreviousCPU.CPUUsage.TotalUsage)
+		if timeDiffSinceLastRead > 0 && totalUsage >= previousTotalUsage {
+			cpuDelta := (float64)(totalUsage - previousTotalUsage)
 			cpuUsageInVCpu = cpuDelta / timeDiffSinceLastRead
 		}
 		cpuUtilized := cpuUsageInVCpu * 100
 
-		m.CPUTotalUsage = *stats.CPU.CPUUsage.TotalUsage
-		m.CPUUsageInKernelmode = *stats.CPU.CPUUsage.UsageInKernelmode
-		m.CPUUsageInUserMode = *stats.CPU.CPUUsage.UsageInUserMode
+		m.CPUTotalUsage = totalUsage
+		m.CPUUsageInKernelmode = uint64Value(stats.CPU.CPUUsage.UsageInKernelmode)
+		m.CPUUsageInUserMode = uint64Value(stats.CPU.CPUUsage.UsageInUserMode)
 		m.NumOfCPUCores = numOfCores
-		m.CPUOnlineCpus = *stats.CPU.OnlineCpus
-		m.SystemCPUUsage = *stats.CPU.SystemCPUUsage
+		m.CPUOnlineCpus = uint64Value(stats.CPU.OnlineCpus)
+		m.SystemCPUUsage = uint64Value(stats.CPU.SystemCPUUsage)
 		m.CPUUsageInVCPU = cpuUsageInVCpu
 		m.CPUUtilized = cpuUtilized
 	} else {
@@ -56,8 +73,8 @@ func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 	}
 
 	if stats.NetworkRate != nil {
-		m.NetworkRateRxBytesPerSecond = *stats.NetworkRate.RxBytesPerSecond
-		m.NetworkRateTxBytesPerSecond = *stats.NetworkRate.TxBytesPerSecond
+		m.NetworkRateRxBytesPerSecond = float64Value(stats.NetworkRate.RxBytesPerSecond)
+		m.NetworkRateTxBytesPerSecond = float64Value(stats.NetworkRate.TxBytesPerSecond)
 	} else {
 		logger.Debug("Nil NetworkRate stats found for docker container:" + stats.Name)
 	}
@@ -79,53 +96,82 @@ func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 	}
 
 	if stats.Disk != nil {
-		storageReadBytes, storageWriteBytes := extractStorageUsage(stats.Disk)
-
-		m.StorageReadBytes = storageReadBytes
-		m.StorageWriteBytes = storageWriteBytes
+		m.StorageDevices = extractDeviceStorageUsage(stats.Disk)
+		m.StorageReadBytes, m.StorageWriteBytes = extractStorageUsage(stats.Disk)
 	}
 
 	return m
 }
 
+// memoryWorkingSet returns the memory usage of a container without the
+// memory that can be reclaimed. The page cache is subtracted on cgroup v1
+// hosts, as reported by docker, and the inactive file memory on cgroup v2
+// hosts, which don't report the cache.
+func memoryWorkingSet(stats *MemoryStats) uint64 {
+	usage := uint64Value(stats.Usage)
+	reclaimable, ok := stats.Stats["cache"]
+	if !ok {
+		reclaimable = stats.Stats["inactive_file"]
+	}
+	if reclaimable > usage {
+		return 0
+	}
+	return usage - reclaimable
+}
+
 // Followed ECS Agent calculations
 // https://github.com/aws/amazon-ecs-agent/blob/1ebf0604c13013596cfd4eb239574a85890b13e8/agent/stats/utils.go#L30
 func getNetworkStats(stats map[string]NetworkStats) [8]uint64 {
 	var netStatArray [8]uint64
 	for _, netStat := range stats {
-		netStatArray[0] += *netStat.RxBytes
-		netStatArray[1] += *netStat.RxPackets
-		netStatArray[2] += *netStat.RxErrors
-		netStatArray[3] += *netStat.RxDropped
-
-		netStatArray[4] += *netStat.TxBytes
-		netStatArray[5] += *netStat.TxPackets
-		netStatArray[6] += *netStat.TxErrors
-		netStatArray[7] += *netStat.TxDropped
+		netStatArray[0] += uint64Value(netStat.RxBytes)
+		netStatArray[1] += uint64Value(netStat.RxPackets)
+		netStatArray[2] += uint64Value(netStat.RxErrors)
+		netStatArray[3] += uint64Value(netStat.RxDropped)
+
+		netStatArray[4] += uint64Value(netStat.TxBytes)
+		netStatArray[5] += uint64Value(netStat.TxPackets)
+		netStatArray[6] += uint64Value(netStat.TxErrors)
+		netStatArray[7] += uint64Value(netStat.TxDropped)
 	}
 	return netStatArray
 }
 
 // Followed ECS Agent calculations
 // https://github.com/aws/amazon-ecs-agent/blob/1ebf0604c13013596cfd4eb239574a85890b13e8/agent/stats/utils_unix.go#L48
+// The bytes read and written are summed over all the block devices.
 func extractStorageUsage(stats *DiskStats) (uint64, uint64) {
 	var readBytes, writeBytes uint64
+	for _, device := range extractDeviceStorageUsage(stats) {
+		readBytes += device.ReadBytes
+		writeBytes += device.WriteBytes
+	}
+	return readBytes, writeBytes
+}
+
+// extractDeviceStorageUsage returns the bytes read and written by block device.
+// The operations are capitalized on cgroup v1 hosts and lowercase on cgroup v2 hosts.
+func extractDeviceStorageUsage(stats *DiskStats) map[string]StorageDeviceUsage {
 	if stats == nil {
-		return uint64(0), uint64(0)
+		return nil
 	}
 
+	devices := make(map[string]StorageDeviceUsage)
 	for _, blockStat := range stats.IoServiceBytesRecursives {
-		switch op := blockStat.Op; op {
-		case "Read":
-			readBytes = *blockStat.Value
-		case "Write":
-			writeBytes = *blockStat.Value
+		device := fmt.Sprintf("%d:%d", uint64Value(blockStat.Major), uint64Value(blockStat.Minor))
+		usage := devices[device]
+		switch op := strings.ToLower(blockStat.Op); op {
+		case "read":
+			usage.ReadBytes += uint64Value(blockStat.Value)
+		case "write":
+			usage.WriteBytes += uint64Value(blockStat.Value)
 		default:
 			//ignoring "Async", "Total", "Sum", etc
 			continue
 		}
+		devices[device] = usage
 	}
-	return readBytes, writeBytes
+	return devices
 }
 
 func aggregateTaskMetrics(taskMetrics *ECSMetrics, conMetrics ECSMetrics) {
@@ -159,4 +205,28 @@ func aggregateTaskMetrics(taskMetrics *ECSMetrics, conMetrics ECSMetrics) {
 
 	taskMetrics.StorageReadBytes += conMetrics.StorageReadBytes
 	taskMetrics.StorageWriteBytes += conMetrics.StorageWriteBytes
+
+	for device, usage := range conMetrics.StorageDevices {
+		if taskMetrics.StorageDevices == nil {
+			taskMetrics.StorageDevices = make(map[string]StorageDeviceUsage)
+		}
+		taskUsage := taskMetrics.StorageDevices[device]
+		taskUsage.ReadBytes += usage.ReadBytes
+		taskUsage.WriteBytes += usage.WriteBytes
+		taskMetrics.StorageDevices[device] = taskUsage
+	}
+}
+
+func uint64Value(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func float64Value(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
 }
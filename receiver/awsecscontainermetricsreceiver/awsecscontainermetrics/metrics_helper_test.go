@@ -288,6 +288,82 @@ func TestExtractStorageUsage(t *testing.T) {
 	require.EqualValues(t, v, write)
 }
 
+func TestExtractDeviceStorageUsage(t *testing.T) {
+	major, minor0, minor1 := uint64(259), uint64(0), uint64(1)
+	read, write, total := uint64(100), uint64(200), uint64(300)
+	disk := &DiskStats{
+		IoServiceBytesRecursives: []IoServiceBytesRecursive{
+			{Major: &major, Minor: &minor0, Op: "Read", Value: &read},
+			{Major: &major, Minor: &minor0, Op: "Write", Value: &write},
+			{Major: &major, Minor: &minor0, Op: "Total", Value: &total},
+			// cgroup v2 operations are lowercase
+			{Major: &major, Minor: &minor1, Op: "read", Value: &read},
+			{Major: &major, Minor: &minor1, Op: "write", Value: &write},
+		},
+	}
+
+	devices := extractDeviceStorageUsage(disk)
+	require.Equal(t, map[string]StorageDeviceUsage{
+		"259:0": {ReadBytes: 100, WriteBytes: 200},
+		"259:1": {ReadBytes: 100, WriteBytes: 200},
+	}, devices)
+
+	totalRead, totalWrite := extractStorageUsage(disk)
+	require.EqualValues(t, 200, totalRead)
+	require.EqualValues(t, 400, totalWrite)
+
+	require.Nil(t, extractDeviceStorageUsage(nil))
+}
+
+func TestMemoryWorkingSet(t *testing.T) {
+	usage := uint64(1000)
+	tests := []struct {
+		name  string
+		stats map[string]uint64
+		want  uint64
+	}{
+		{name: "cgroup v1", stats: map[string]uint64{"cache": 300, "inactive_file": 100}, want: 700},
+		{name: "cgroup v2", stats: map[string]uint64{"inactive_file": 100, "file": 300}, want: 900},
+		{name: "reclaimable above usage", stats: map[string]uint64{"inactive_file": 2000}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualValues(t, tt.want, memoryWorkingSet(&MemoryStats{Usage: &usage, Stats: tt.stats}))
+		})
+	}
+}
+
+func TestGetContainerMetricsCgroupV2(t *testing.T) {
+	usage := uint64(100 * BytesInMiB)
+	total, previousTotal, onlineCPUs := uint64(3000), uint64(1000), uint64(4)
+	stats := ContainerStats{
+		Name:         "test",
+		ID:           "001",
+		Read:         time.Unix(0, 10000),
+		PreviousRead: time.Unix(0, 0),
+		// max_usage and percpu_usage are missing on cgroup v2 hosts
+		Memory: &MemoryStats{
+			Usage: &usage,
+			Stats: map[string]uint64{"inactive_file": 20 * BytesInMiB},
+		},
+		CPU: &CPUStats{
+			CPUUsage:   &CPUUsage{TotalUsage: &total},
+			OnlineCpus: &onlineCPUs,
+		},
+		PreviousCPU: &CPUStats{
+			CPUUsage: &CPUUsage{TotalUsage: &previousTotal},
+		},
+	}
+
+	containerMetrics := getContainerMetrics(&stats, logger)
+	require.EqualValues(t, usage, containerMetrics.MemoryUsage)
+	require.EqualValues(t, 0, containerMetrics.MemoryMaxUsage)
+	require.EqualValues(t, 80, containerMetrics.MemoryUtilized)
+	require.EqualValues(t, onlineCPUs, containerMetrics.NumOfCPUCores)
+	require.EqualValues(t, 0.2, containerMetrics.CPUUsageInVCPU)
+	require.EqualValues(t, 0, containerMetrics.CPUUsageInKernelmode)
+}
+
 func TestGetNetworkStats(t *testing.T) {
 	v := uint64(100)
 	stats := make(map[string]NetworkStats)
@@ -17,10 +17,30 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 )
 
+// fixtureRestClient returns the recorded responses of a task metadata endpoint.
+type fixtureRestClient struct {
+	taskStats    string
+	taskMetadata string
+}
+
+func (f fixtureRestClient) EndpointResponse() ([]byte, []byte, error) {
+	taskStats, err := ioutil.ReadFile(f.taskStats)
+	if err != nil {
+		return nil, nil, err
+	}
+	taskMetadata, err := ioutil.ReadFile(f.taskMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return taskStats, taskMetadata, nil
+}
+
 func TestMetricSampleFile(t *testing.T) {
 	data, err := ioutil.ReadFile("../testdata/task_stats.json")
 	require.NoError(t, err)
@@ -111,3 +131,101 @@ func TestMetricData(t *testing.T) {
 	md := MetricsData(cstats, tm, logger)
 	require.Less(t, 0, len(md))
 }
+
+func cgroupV2Stats(t *testing.T) (map[string]*ContainerStats, TaskMetadata) {
+	provider := NewStatsProvider(fixtureRestClient{
+		taskStats:    "../testdata/task_stats_cgroupv2.json",
+		taskMetadata: "../testdata/task_metadata_cgroupv2.json",
+	})
+	stats, metadata, err := provider.GetStats()
+	require.NoError(t, err)
+	return stats, metadata
+}
+
+func TestMetricsDataCgroupV2(t *testing.T) {
+	stats, metadata := cgroupV2Stats(t)
+	mds := MetricsData(stats, metadata, zap.NewNop())
+	require.Len(t, mds, 3)
+
+	app := metricValues(mds[0])
+	assert.EqualValues(t, 104857600, app["container.memory.usage"])
+	assert.EqualValues(t, 0, app["container.memory.usage.max"])
+	// the inactive file memory is not part of the working set
+	assert.EqualValues(t, 80, app["container.memory.utilized"])
+	assert.EqualValues(t, 2, app["container.cpu.cores"])
+	assert.InDelta(t, 0.2, app["container.cpu.usage.vcpu"], 1e-9)
+	assert.EqualValues(t, 1300, app["container.storage.read_bytes"])
+	assert.EqualValues(t, 2000, app["container.storage.write_bytes"])
+	assert.EqualValues(t, 1000, app["container.storage.device.read_bytes{device=259:0}"])
+	assert.EqualValues(t, 2000, app["container.storage.device.write_bytes{device=259:0}"])
+	assert.EqualValues(t, 300, app["container.storage.device.read_bytes{device=259:1}"])
+	assert.EqualValues(t, 0, app["container.storage.device.write_bytes{device=259:1}"])
+	assert.EqualValues(t, 2, app["container.network.io.usage.rx_errors"])
+	assert.EqualValues(t, 1, app["container.network.io.usage.rx_dropped"])
+	assert.EqualValues(t, 1, app["container.network.io.usage.tx_errors"])
+	assert.EqualValues(t, 3, app["container.network.io.usage.tx_dropped"])
+
+	task := metricValues(mds[2])
+	assert.EqualValues(t, 120, task["ecs.task.memory.utilized"])
+	assert.EqualValues(t, 1024, task["ecs.task.memory.reserved"])
+	assert.InDelta(t, 0.25, task["ecs.task.cpu.usage.vcpu"], 1e-9)
+	assert.InDelta(t, 0.5, task["ecs.task.cpu.reserved"], 1e-9)
+	assert.InDelta(t, 50, task["ecs.task.cpu.utilized"], 1e-9)
+	assert.EqualValues(t, 1800, task["ecs.task.storage.read_bytes"])
+	assert.EqualValues(t, 2100, task["ecs.task.storage.write_bytes"])
+	assert.EqualValues(t, 1500, task["ecs.task.storage.device.read_bytes{device=259:0}"])
+	assert.EqualValues(t, 2100, task["ecs.task.storage.device.write_bytes{device=259:0}"])
+	assert.EqualValues(t, 300, task["ecs.task.storage.device.read_bytes{device=259:1}"])
+	assert.EqualValues(t, 3, task["ecs.task.network.io.usage.rx_errors"])
+	assert.EqualValues(t, 1, task["ecs.task.network.io.usage.rx_dropped"])
+	assert.EqualValues(t, 1, task["ecs.task.network.io.usage.tx_errors"])
+	assert.EqualValues(t, 5, task["ecs.task.network.io.usage.tx_dropped"])
+}
+
+func TestMetricsDataWithoutCPULimit(t *testing.T) {
+	stats, metadata := cgroupV2Stats(t)
+	metadata.Limits.CPU = nil
+	for i := range metadata.Containers {
+		metadata.Containers[i].Limits.CPU = nil
+	}
+	mds := MetricsData(stats, metadata, zap.NewNop())
+	require.Len(t, mds, 3)
+
+	// the task can use the 2 CPUs of the instance
+	task := metricValues(mds[2])
+	assert.InDelta(t, 0, task["ecs.task.cpu.reserved"], 1e-9)
+	assert.InDelta(t, 12.5, task["ecs.task.cpu.utilized"], 1e-9)
+}
+
+// metricValues returns the values of the metrics by name, followed by the
+// labels of their data points.
+func metricValues(md pdata.Metrics) map[string]interface{} {
+	values := make(map[string]interface{})
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		metrics := ilms.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			m := metrics.At(j)
+			switch m.DataType() {
+			case pdata.MetricDataTypeIntGauge:
+				addIntValues(values, m.Name(), m.IntGauge().DataPoints())
+			case pdata.MetricDataTypeIntSum:
+				addIntValues(values, m.Name(), m.IntSum().DataPoints())
+			case pdata.MetricDataTypeDoubleGauge:
+				values[m.Name()] = m.DoubleGauge().DataPoints().At(0).Value()
+			}
+		}
+	}
+	return values
+}
+
+func addIntValues(values map[string]interface{}, name string, dps pdata.IntDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		key := name
+		dps.At(i).LabelsMap().Range(func(k, v string) bool {
+			key += "{" + k + "=" + v + "}"
+			return true
+		})
+		values[key] = dps.At(i).Value()
+	}
+}
@@ -15,6 +15,8 @@
 package awsecscontainermetrics
 
 import (
+	"sort"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -56,6 +58,10 @@ func convertToOTLPMetrics(prefix string, m ECSMetrics, r pdata.Resource, timesta
 	appendIntSum(prefix+AttributeStorageRead, UnitBytes, int64(m.StorageReadBytes), timestamp, ilms.AppendEmpty())
 	appendIntSum(prefix+AttributeStorageWrite, UnitBytes, int64(m.StorageWriteBytes), timestamp, ilms.AppendEmpty())
 
+	if len(m.StorageDevices) > 0 {
+		appendStorageDeviceUsage(prefix, m.StorageDevices, timestamp, ilms.AppendEmpty())
+	}
+
 	return md
 }
 
@@ -98,10 +104,34 @@ func appendDoubleGauge(metricName string, unit string, value float64, ts pdata.T
 	dataPoint.SetTimestamp(ts)
 }
 
-func appendIntDataPoint(dataPoints pdata.IntDataPointSlice, value int64, ts pdata.Timestamp) {
+// appendStorageDeviceUsage appends the bytes read and written with a data point by block device
+func appendStorageDeviceUsage(prefix string, devices map[string]StorageDeviceUsage, ts pdata.Timestamp, ilm pdata.InstrumentationLibraryMetrics) {
+	names := make([]string, 0, len(devices))
+	for device := range devices {
+		names = append(names, device)
+	}
+	sort.Strings(names)
+
+	read := appendMetric(ilm, prefix+AttributeStorageDeviceRead, UnitBytes)
+	read.SetDataType(pdata.MetricDataTypeIntSum)
+	read.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	write := appendMetric(ilm, prefix+AttributeStorageDeviceWrite, UnitBytes)
+	write.SetDataType(pdata.MetricDataTypeIntSum)
+	write.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+	for _, device := range names {
+		usage := devices[device]
+		appendIntDataPoint(read.IntSum().DataPoints(), int64(usage.ReadBytes), ts).LabelsMap().Insert(LabelDevice, device)
+		appendIntDataPoint(write.IntSum().DataPoints(), int64(usage.WriteBytes), ts).LabelsMap().Insert(LabelDevice, device)
+	}
+}
+
+func appendIntDataPoint(dataPoints pdata.IntDataPointSlice, value int64, ts pdata.Timestamp) pdata.IntDataPoint {
 	dataPoint := dataPoints.AppendEmpty()
 	dataPoint.SetValue(value)
 	dataPoint.SetTimestamp(ts)
+	return dataPoint
 }
 
 func appendMetric(ilm pdata.InstrumentationLibraryMetrics, name, unit string) pdata.Metric {
@@ -93,10 +93,11 @@ func TestLoadConfig(t *testing.T) {
 					Insecure:   true,
 					ServerName: "something",
 				},
-				Region:      "us-west-1",
-				RoleARN:     "arn:aws:iam::123456789012:role/awesome_role",
-				AWSEndpoint: "https://another.aws.endpoint.com",
-				LocalMode:   true,
+				Region:            "us-west-1",
+				RoleARN:           "arn:aws:iam::123456789012:role/awesome_role",
+				AWSEndpoint:       "https://another.aws.endpoint.com",
+				LocalMode:         true,
+				SamplingRulesFile: "/etc/xray/sampling_rules.json",
 			},
 		},
 		r2)
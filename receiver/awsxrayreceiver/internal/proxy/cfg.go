@@ -49,6 +49,11 @@ type Config struct {
 	// will be called or not. Set to `true` to skip EC2 instance
 	// metadata check.
 	LocalMode bool `mapstructure:"local_mode"`
+
+	// SamplingRulesFile is the path of a local sampling rules file of the
+	// X-Ray SDKs. When set, the local TCP server serves the sampling rules
+	// and targets from this file instead of forwarding the calls to AWS.
+	SamplingRulesFile string `mapstructure:"sampling_rules_file"`
 }
 
 func DefaultConfig() *Config {
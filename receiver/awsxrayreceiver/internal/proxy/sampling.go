@@ -0,0 +1,346 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	getSamplingRulesPath   = "/GetSamplingRules"
+	getSamplingTargetsPath = "/SamplingTargets"
+
+	defaultRuleName     = "Default"
+	defaultRulePriority = 10000
+
+	// targetInterval is the interval in seconds at which the SDKs are asked
+	// to report their statistics, and targetTTL the validity of the
+	// reservoir quotas. The clients that did not report their statistics for
+	// more than two intervals don't take a share of the reservoirs anymore.
+	targetInterval = 10
+	targetTTL      = 60 * time.Second
+	clientTimeout  = 2 * targetInterval * time.Second
+)
+
+// localSamplingRules is the local sampling rules file format of the X-Ray
+// SDKs, see https://docs.aws.amazon.com/xray/latest/devguide/xray-sdk-go-configuration.html#xray-sdk-go-configuration-sampling
+type localSamplingRules struct {
+	Version int                  `json:"version"`
+	Rules   []*localSamplingRule `json:"rules"`
+	Default *localSamplingRule   `json:"default"`
+}
+
+type localSamplingRule struct {
+	Description string  `json:"description"`
+	Host        string  `json:"host"`
+	ServiceName string  `json:"service_name"`
+	HTTPMethod  string  `json:"http_method"`
+	URLPath     string  `json:"url_path"`
+	FixedTarget int64   `json:"fixed_target"`
+	Rate        float64 `json:"rate"`
+}
+
+// samplingRule is the X-Ray API representation of a sampling rule.
+type samplingRule struct {
+	RuleName      string            `json:"RuleName"`
+	RuleARN       string            `json:"RuleARN"`
+	ResourceARN   string            `json:"ResourceARN"`
+	Priority      int64             `json:"Priority"`
+	FixedRate     float64           `json:"FixedRate"`
+	ReservoirSize int64             `json:"ReservoirSize"`
+	ServiceName   string            `json:"ServiceName"`
+	ServiceType   string            `json:"ServiceType"`
+	Host          string            `json:"Host"`
+	HTTPMethod    string            `json:"HTTPMethod"`
+	URLPath       string            `json:"URLPath"`
+	Version       int64             `json:"Version"`
+	Attributes    map[string]string `json:"Attributes"`
+}
+
+type samplingRuleRecord struct {
+	CreatedAt    float64       `json:"CreatedAt"`
+	ModifiedAt   float64       `json:"ModifiedAt"`
+	SamplingRule *samplingRule `json:"SamplingRule"`
+}
+
+type getSamplingRulesOutput struct {
+	SamplingRuleRecords []*samplingRuleRecord `json:"SamplingRuleRecords"`
+}
+
+type samplingStatisticsDocument struct {
+	RuleName     string  `json:"RuleName"`
+	ClientID     string  `json:"ClientID"`
+	Timestamp    float64 `json:"Timestamp"`
+	RequestCount int64   `json:"RequestCount"`
+	SampledCount int64   `json:"SampledCount"`
+	BorrowCount  int64   `json:"BorrowCount"`
+}
+
+type getSamplingTargetsInput struct {
+	SamplingStatisticsDocuments []*samplingStatisticsDocument `json:"SamplingStatisticsDocuments"`
+}
+
+type samplingTargetDocument struct {
+	RuleName          string  `json:"RuleName"`
+	FixedRate         float64 `json:"FixedRate"`
+	ReservoirQuota    int64   `json:"ReservoirQuota"`
+	ReservoirQuotaTTL float64 `json:"ReservoirQuotaTTL"`
+	Interval          int64   `json:"Interval"`
+}
+
+type unprocessedStatistics struct {
+	RuleName  string `json:"RuleName"`
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+}
+
+type getSamplingTargetsOutput struct {
+	LastRuleModification    float64                   `json:"LastRuleModification"`
+	SamplingTargetDocuments []*samplingTargetDocument `json:"SamplingTargetDocuments"`
+	UnprocessedStatistics   []*unprocessedStatistics  `json:"UnprocessedStatistics"`
+}
+
+// localSampler serves the sampling rules and targets of the X-Ray API from a
+// local sampling rules file instead of forwarding them to AWS. The reservoir
+// size of a rule is the number of traces sampled per second across all the
+// clients, it is shared between the clients reporting statistics for it.
+type localSampler struct {
+	logger   *zap.Logger
+	rules    []*samplingRule
+	modified time.Time
+	now      func() time.Time
+
+	mu sync.Mutex
+	// clients holds the time of the last statistics reported by each
+	// client, by rule name.
+	clients map[string]map[string]time.Time
+}
+
+var _ http.Handler = (*localSampler)(nil)
+
+func newLocalSampler(path string, logger *zap.Logger) (*localSampler, error) {
+	rules, err := loadSamplingRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return &localSampler{
+		logger:   logger,
+		rules:    rules,
+		modified: time.Now(),
+		now:      time.Now,
+		clients:  make(map[string]map[string]time.Time),
+	}, nil
+}
+
+// loadSamplingRules reads a local sampling rules file and converts its rules
+// to the X-Ray API ones, with the priorities of their order in the file.
+func loadSamplingRules(path string) ([]*samplingRule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the sampling rules file: %w", err)
+	}
+
+	var local localSamplingRules
+	if err = json.Unmarshal(data, &local); err != nil {
+		return nil, fmt.Errorf("unable to parse the sampling rules file: %w", err)
+	}
+	if local.Version != 1 && local.Version != 2 {
+		return nil, fmt.Errorf("unsupported sampling rules version %d", local.Version)
+	}
+	if local.Default == nil {
+		return nil, errors.New("the sampling rules file has no default rule")
+	}
+
+	rules := make([]*samplingRule, 0, len(local.Rules)+1)
+	for i, r := range local.Rules {
+		if err = validateLocalRule(r); err != nil {
+			return nil, fmt.Errorf("invalid sampling rule %d: %w", i+1, err)
+		}
+		host := r.Host
+		// the version 1 rules match the host with the service name
+		if local.Version == 1 {
+			host = r.ServiceName
+		}
+		rules = append(rules, newSamplingRule(fmt.Sprintf("Local-%d", i+1), int64(i+1), r, host, r.HTTPMethod, r.URLPath))
+	}
+	if err = validateLocalRule(local.Default); err != nil {
+		return nil, fmt.Errorf("invalid default sampling rule: %w", err)
+	}
+	rules = append(rules, newSamplingRule(defaultRuleName, defaultRulePriority, local.Default, "*", "*", "*"))
+	return rules, nil
+}
+
+func validateLocalRule(r *localSamplingRule) error {
+	if r == nil {
+		return errors.New("empty rule")
+	}
+	if r.FixedTarget < 0 {
+		return errors.New("fixed_target must be positive")
+	}
+	if r.Rate < 0 || r.Rate > 1 {
+		return errors.New("rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func newSamplingRule(name string, priority int64, r *localSamplingRule, host, method, path string) *samplingRule {
+	return &samplingRule{
+		RuleName:      name,
+		RuleARN:       "arn:aws:xray:local:000000000000:sampling-rule/" + name,
+		ResourceARN:   "*",
+		Priority:      priority,
+		FixedRate:     r.Rate,
+		ReservoirSize: r.FixedTarget,
+		ServiceName:   "*",
+		ServiceType:   "*",
+		Host:          wildcard(host),
+		HTTPMethod:    wildcard(method),
+		URLPath:       wildcard(path),
+		Version:       1,
+		Attributes:    map[string]string{},
+	}
+}
+
+func wildcard(s string) string {
+	if s == "" {
+		return "*"
+	}
+	return s
+}
+
+func (s *localSampler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.logger.Debug("Received request on X-Ray receiver local sampling server", zap.String("URL", req.URL.String()))
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var out interface{}
+	switch req.URL.Path {
+	case getSamplingRulesPath:
+		out = s.getSamplingRules()
+	case getSamplingTargetsPath:
+		var in getSamplingTargetsInput
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		out = s.getSamplingTargets(&in)
+	default:
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		s.logger.Error("Unable to write the sampling response", zap.Error(err))
+	}
+}
+
+func (s *localSampler) getSamplingRules() *getSamplingRulesOutput {
+	modified := epochSeconds(s.modified)
+	records := make([]*samplingRuleRecord, 0, len(s.rules))
+	for _, r := range s.rules {
+		records = append(records, &samplingRuleRecord{CreatedAt: modified, ModifiedAt: modified, SamplingRule: r})
+	}
+	return &getSamplingRulesOutput{SamplingRuleRecords: records}
+}
+
+func (s *localSampler) getSamplingTargets(in *getSamplingTargetsInput) *getSamplingTargetsOutput {
+	now := s.now()
+	out := &getSamplingTargetsOutput{
+		LastRuleModification:    epochSeconds(s.modified),
+		SamplingTargetDocuments: []*samplingTargetDocument{},
+		UnprocessedStatistics:   []*unprocessedStatistics{},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range in.SamplingStatisticsDocuments {
+		rule := s.rule(doc.RuleName)
+		if rule == nil {
+			out.UnprocessedStatistics = append(out.UnprocessedStatistics, &unprocessedStatistics{
+				RuleName:  doc.RuleName,
+				ErrorCode: "400",
+				Message:   "Unknown rule",
+			})
+			continue
+		}
+
+		out.SamplingTargetDocuments = append(out.SamplingTargetDocuments, &samplingTargetDocument{
+			RuleName:          rule.RuleName,
+			FixedRate:         rule.FixedRate,
+			ReservoirQuota:    s.reservoirQuota(rule, doc.ClientID, now),
+			ReservoirQuotaTTL: epochSeconds(now.Add(targetTTL)),
+			Interval:          targetInterval,
+		})
+	}
+	return out
+}
+
+// reservoirQuota records the statistics of a client and returns its share of
+// the reservoir of a rule. The remainder of the division of the reservoir
+// between the active clients is given to the first ones by client ID, so that
+// the quotas add up to the reservoir size.
+func (s *localSampler) reservoirQuota(rule *samplingRule, clientID string, now time.Time) int64 {
+	clients, ok := s.clients[rule.RuleName]
+	if !ok {
+		clients = make(map[string]time.Time)
+		s.clients[rule.RuleName] = clients
+	}
+	clients[clientID] = now
+
+	active := make([]string, 0, len(clients))
+	for id, seen := range clients {
+		if now.Sub(seen) > clientTimeout {
+			delete(clients, id)
+			continue
+		}
+		active = append(active, id)
+	}
+	sort.Strings(active)
+
+	n := int64(len(active))
+	quota := rule.ReservoirSize / n
+	if int64(sort.SearchStrings(active, clientID)) < rule.ReservoirSize%n {
+		quota++
+	}
+	return quota
+}
+
+func (s *localSampler) rule(name string) *samplingRule {
+	for _, r := range s.rules {
+		if r.RuleName == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func epochSeconds(t time.Time) float64 {
+	return math.Floor(float64(t.UnixNano())/1e6) / 1e3
+}
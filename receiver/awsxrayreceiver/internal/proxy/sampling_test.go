@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/xray"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+const samplingRulesFile = "testdata/sampling_rules.json"
+
+// newXRayClient returns an X-Ray SDK client calling the local server at addr.
+func newXRayClient(t *testing.T, addr string) *xray.XRay {
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String("http://" + addr),
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	require.NoError(t, err)
+	return xray.New(sess)
+}
+
+func startLocalSamplingServer(t *testing.T) string {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	cfg := DefaultConfig()
+	tcpAddr := testutil.GetAvailableLocalAddress(t)
+	cfg.TCPAddr.Endpoint = tcpAddr
+	cfg.SamplingRulesFile = samplingRulesFile
+	srv, err := NewServer(cfg, zap.NewNop())
+	require.NoError(t, err, "NewServer should succeed without AWS configuration")
+	go srv.ListenAndServe()
+	t.Cleanup(func() { srv.Close() })
+
+	require.Eventuallyf(t, func() bool {
+		_, err := net.DialTimeout("tcp", tcpAddr, time.Second)
+		return err == nil
+	}, 10*time.Second, 5*time.Millisecond, "port should eventually be accessible")
+	return tcpAddr
+}
+
+func TestLocalSamplingRules(t *testing.T) {
+	client := newXRayClient(t, startLocalSamplingServer(t))
+
+	out, err := client.GetSamplingRules(&xray.GetSamplingRulesInput{})
+	require.NoError(t, err)
+	require.Len(t, out.SamplingRuleRecords, 3)
+
+	moves := out.SamplingRuleRecords[0].SamplingRule
+	assert.Equal(t, "Local-1", *moves.RuleName)
+	assert.EqualValues(t, 1, *moves.Priority)
+	assert.Equal(t, 0.05, *moves.FixedRate)
+	assert.EqualValues(t, 3, *moves.ReservoirSize)
+	assert.Equal(t, "*", *moves.Host)
+	assert.Equal(t, "*", *moves.ServiceName)
+	assert.Equal(t, "/api/move/*", *moves.URLPath)
+	assert.NoError(t, moves.Validate())
+
+	health := out.SamplingRuleRecords[1].SamplingRule
+	assert.Equal(t, "example.com", *health.Host)
+	assert.Equal(t, "GET", *health.HTTPMethod)
+
+	def := out.SamplingRuleRecords[2].SamplingRule
+	assert.Equal(t, defaultRuleName, *def.RuleName)
+	assert.EqualValues(t, defaultRulePriority, *def.Priority)
+	assert.Equal(t, 0.1, *def.FixedRate)
+	assert.EqualValues(t, 1, *def.ReservoirSize)
+	assert.Equal(t, "*", *def.URLPath)
+	assert.False(t, out.SamplingRuleRecords[2].ModifiedAt.IsZero())
+}
+
+func TestLocalSamplingTargets(t *testing.T) {
+	client := newXRayClient(t, startLocalSamplingServer(t))
+
+	out, err := client.GetSamplingTargets(&xray.GetSamplingTargetsInput{
+		SamplingStatisticsDocuments: []*xray.SamplingStatisticsDocument{
+			statistics("Local-1", "0123456789abcdef01234567"),
+			statistics("Unknown", "0123456789abcdef01234567"),
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, out.SamplingTargetDocuments, 1)
+	target := out.SamplingTargetDocuments[0]
+	assert.Equal(t, "Local-1", *target.RuleName)
+	assert.Equal(t, 0.05, *target.FixedRate)
+	assert.EqualValues(t, 3, *target.ReservoirQuota)
+	assert.EqualValues(t, targetInterval, *target.Interval)
+	assert.True(t, target.ReservoirQuotaTTL.After(time.Now()))
+
+	require.Len(t, out.UnprocessedStatistics, 1)
+	assert.Equal(t, "Unknown", *out.UnprocessedStatistics[0].RuleName)
+}
+
+func TestLocalSamplingReservoirShared(t *testing.T) {
+	sampler, err := newLocalSampler(samplingRulesFile, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Unix(1600000000, 0)
+	sampler.now = func() time.Time { return now }
+
+	quota := func(clientID string) int64 {
+		out := sampler.getSamplingTargets(&getSamplingTargetsInput{
+			SamplingStatisticsDocuments: []*samplingStatisticsDocument{{RuleName: "Local-1", ClientID: clientID}},
+		})
+		require.Len(t, out.SamplingTargetDocuments, 1)
+		return out.SamplingTargetDocuments[0].ReservoirQuota
+	}
+
+	assert.EqualValues(t, 3, quota("a"))
+	// the reservoir of 3 is split between the two clients
+	assert.EqualValues(t, 1, quota("b"))
+	assert.EqualValues(t, 2, quota("a"))
+
+	// b stopped reporting its statistics
+	now = now.Add(clientTimeout + time.Second)
+	assert.EqualValues(t, 3, quota("a"))
+}
+
+func TestLocalSamplerInvalidRequests(t *testing.T) {
+	sampler, err := newLocalSampler(samplingRulesFile, zap.NewNop())
+	require.NoError(t, err)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{method: http.MethodGet, path: getSamplingRulesPath, status: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: "/TraceSegments", status: http.StatusNotFound},
+		{method: http.MethodPost, path: getSamplingTargetsPath, body: "{", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			sampler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body))))
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestLoadSamplingRulesVersion1(t *testing.T) {
+	path := writeSamplingRules(t, `{"version": 1, "rules": [{"service_name": "example.com", "http_method": "*", "url_path": "*", "fixed_target": 2, "rate": 0.5}], "default": {"fixed_target": 1, "rate": 0.1}}`)
+	rules, err := loadSamplingRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "example.com", rules[0].Host)
+}
+
+func TestLoadSamplingRulesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{name: "invalid json", rules: "{", err: "unable to parse the sampling rules file"},
+		{name: "unsupported version", rules: `{"version": 3, "default": {"rate": 0.1}}`, err: "unsupported sampling rules version 3"},
+		{name: "no default", rules: `{"version": 2}`, err: "the sampling rules file has no default rule"},
+		{name: "invalid rate", rules: `{"version": 2, "rules": [{"rate": 2}], "default": {"rate": 0.1}}`, err: "invalid sampling rule 1: rate must be between 0 and 1"},
+		{name: "invalid fixed target", rules: `{"version": 2, "default": {"fixed_target": -1}}`, err: "invalid default sampling rule: fixed_target must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSamplingRules(writeSamplingRules(t, tt.rules))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+
+	_, err := loadSamplingRules(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestNewServerInvalidSamplingRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SamplingRulesFile = writeSamplingRules(t, "{")
+	_, err := NewServer(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func statistics(rule, clientID string) *xray.SamplingStatisticsDocument {
+	return &xray.SamplingStatisticsDocument{
+		RuleName:     aws.String(rule),
+		ClientID:     aws.String(clientID),
+		Timestamp:    aws.Time(time.Now()),
+		RequestCount: aws.Int64(10),
+		SampledCount: aws.Int64(2),
+		BorrowCount:  aws.Int64(0),
+	}
+}
+
+func writeSamplingRules(t *testing.T, rules string) string {
+	path := filepath.Join(t.TempDir(), "sampling_rules.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(rules), 0600))
+	return path
+}
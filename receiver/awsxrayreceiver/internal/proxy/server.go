@@ -45,12 +45,24 @@ type Server interface {
 }
 
 // NewServer returns a local TCP server that proxies requests to AWS
-// backend using the given credentials.
+// backend using the given credentials, or that serves the sampling rules
+// of the configured local sampling rules file.
 func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
 	_, err := net.ResolveTCPAddr("tcp", cfg.Endpoint)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.SamplingRulesFile != "" {
+		logger.Debug("Using local sampling rules", zap.String("file", cfg.SamplingRulesFile))
+		sampler, err := newLocalSampler(cfg.SamplingRulesFile, logger)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Server{
+			Addr:    cfg.Endpoint,
+			Handler: sampler,
+		}, nil
+	}
 	if cfg.ProxyAddress != "" {
 		logger.Debug("Using remote proxy", zap.String("address", cfg.ProxyAddress))
 	}
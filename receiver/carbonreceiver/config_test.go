@@ -42,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 4)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, factory.CreateDefaultConfig(), r0)
@@ -94,4 +94,27 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 		r2)
+
+	r3 := cfg.Receivers[config.NewIDWithName(typeStr, "pickle")].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "pickle")),
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "localhost:2004",
+				Transport: "tcp",
+			},
+			TCPIdleTimeout: 30 * time.Second,
+			Parser: &protocol.Config{
+				Type: "pickle",
+				Config: &protocol.PickleConfig{
+					Rules: []*protocol.RegexRule{
+						{
+							Regexp: `(?P<key_just>test)\.(?P<key_match>.*)`,
+						},
+					},
+					MaxMessageSize: 2097152,
+				},
+			},
+		},
+		r3)
 }
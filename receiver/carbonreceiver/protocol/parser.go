@@ -15,6 +15,8 @@
 package protocol
 
 import (
+	"bufio"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -38,6 +40,25 @@ type Parser interface {
 	Parse(line string) (*metricspb.Metric, error)
 }
 
+// StreamParser is implemented by parsers of protocols whose messages are not
+// newline delimited, e.g.: the pickle protocol. Transports that support stream
+// parsers read one message at a time with ReadMessage and pass it to
+// ParseMessage, instead of calling Parse for each line.
+type StreamParser interface {
+	Parser
+
+	// ReadMessage reads the next complete message from the stream. An error
+	// other than one coming from the reader means that the framing of the
+	// stream was lost and the connection should be closed.
+	ReadMessage(r *bufio.Reader) ([]byte, error)
+
+	// ParseMessage transforms a message returned by ReadMessage in the
+	// collector metric format. Metrics that were successfully parsed are
+	// returned even if an error is also returned for other metrics in the
+	// same message.
+	ParseMessage(msg []byte) ([]*metricspb.Metric, error)
+}
+
 // Below a few helper functions useful to different parsers.
 func buildMetricForSinglePoint(
 	metricName string,
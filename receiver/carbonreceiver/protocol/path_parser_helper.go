@@ -118,36 +118,59 @@ func (pph *PathParserHelper) Parse(line string) (*metricspb.Metric, error) {
 		return nil, fmt.Errorf("invalid carbon metric time [%s]: %v", line, err)
 	}
 
-	var metricType metricspb.MetricDescriptor_Type
 	point := metricspb.Point{
 		Timestamp: convertUnixSec(unixTime),
 	}
 	intVal, err := strconv.ParseInt(valueStr, 10, 64)
 	if err == nil {
-		if parsedPath.MetricType == CumulativeMetricType {
-			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
-		} else {
-			metricType = metricspb.MetricDescriptor_GAUGE_INT64
-		}
 		point.Value = &metricspb.Point_Int64Value{Int64Value: intVal}
 	} else {
 		dblVal, err := strconv.ParseFloat(valueStr, 64)
 		if err != nil {
 			return nil, fmt.Errorf("invalid carbon metric value [%s]: %v", line, err)
 		}
-		if parsedPath.MetricType == CumulativeMetricType {
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
+	}
+
+	return buildMetricForParsedPath(&parsedPath, &point), nil
+}
+
+// ParsePoint is the counterpart of Parse for protocols, like pickle, that
+// deliver the <metric_path>, <metric_value> and <metric_timestamp> already
+// decoded. The point must have its value and timestamp set.
+func (pph *PathParserHelper) ParsePoint(path string, point *metricspb.Point) (*metricspb.Metric, error) {
+	parsedPath := ParsedPath{}
+	if err := pph.pathParser.ParsePath(path, &parsedPath); err != nil {
+		return nil, fmt.Errorf("invalid carbon metric path [%s]: %v", path, err)
+	}
+
+	return buildMetricForParsedPath(&parsedPath, point), nil
+}
+
+// buildMetricForParsedPath selects the metric type according to the type of
+// the point value and the TargetMetricType requested by the PathParser.
+func buildMetricForParsedPath(parsedPath *ParsedPath, point *metricspb.Point) *metricspb.Metric {
+	var metricType metricspb.MetricDescriptor_Type
+	cumulative := parsedPath.MetricType == CumulativeMetricType
+	switch point.Value.(type) {
+	case *metricspb.Point_Int64Value:
+		if cumulative {
+			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+		} else {
+			metricType = metricspb.MetricDescriptor_GAUGE_INT64
+		}
+	default:
+		if cumulative {
 			metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
 		} else {
 			metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
 		}
-		point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
 	}
 
-	metric := buildMetricForSinglePoint(
+	return buildMetricForSinglePoint(
 		parsedPath.MetricName,
 		metricType,
 		parsedPath.LabelKeys,
 		parsedPath.LabelValues,
-		&point)
-	return metric, nil
+		point)
 }
@@ -0,0 +1,563 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Pickle opcodes understood by the decoder. Only opcodes that build plain
+// data (numbers, strings, lists and tuples) are supported, any opcode that
+// could import a global or construct an arbitrary object is rejected. See
+// https://github.com/python/cpython/blob/master/Lib/pickletools.py for the
+// description of each opcode.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opPopMark        = '1'
+	opDup            = '2'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opAppends        = 'e'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyList      = ']'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+
+	opProto       = 0x80
+	opTuple1      = 0x85
+	opTuple2      = 0x86
+	opTuple3      = 0x87
+	opNewTrue     = 0x88
+	opNewFalse    = 0x89
+	opLong1       = 0x8a
+	opLong4       = 0x8b
+	opShortBinUni = 0x8c
+	opBinUnicode8 = 0x8d
+	opBinBytes8   = 0x8e
+	opMemoize     = 0x94
+	opFrame       = 0x95
+)
+
+// maxPickleProtocol is the highest pickle protocol version accepted.
+const maxPickleProtocol = 5
+
+var errPickleTruncated = errors.New("truncated pickle data")
+
+// pickleList is used for lists so APPEND(S) on a memoized list is visible
+// through every reference to it, like in Python.
+type pickleList struct {
+	items []interface{}
+}
+
+// pickleTuple is an immutable sequence.
+type pickleTuple []interface{}
+
+// pickleMark is pushed on the stack by the MARK opcode.
+type pickleMark struct{}
+
+// pickleDecoder decodes the safe subset of the Python pickle format used by
+// Carbon clients. Decoded values are int64, float64, string (both for str and
+// bytes), bool, nil, *pickleList and pickleTuple.
+type pickleDecoder struct {
+	data  []byte
+	pos   int
+	stack []interface{}
+	memo  map[int]interface{}
+}
+
+func unpickle(data []byte) (interface{}, error) {
+	d := &pickleDecoder{
+		data: data,
+		memo: make(map[int]interface{}),
+	}
+	return d.decode()
+}
+
+func (d *pickleDecoder) decode() (interface{}, error) {
+	for {
+		op, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opStop:
+			if len(d.stack) != 1 {
+				return nil, fmt.Errorf("invalid pickle stack size %d at STOP", len(d.stack))
+			}
+			return d.stack[0], nil
+
+		case opProto:
+			proto, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if proto > maxPickleProtocol {
+				return nil, fmt.Errorf("unsupported pickle protocol %d", proto)
+			}
+		case opFrame:
+			// Frames are only a hint for buffering, the whole message is in memory.
+			if _, err = d.read(8); err != nil {
+				return nil, err
+			}
+
+		case opMark:
+			d.push(pickleMark{})
+		case opPop:
+			if _, err = d.pop(); err != nil {
+				return nil, err
+			}
+		case opPopMark:
+			if _, err = d.popMark(); err != nil {
+				return nil, err
+			}
+		case opDup:
+			top, err := d.peek()
+			if err != nil {
+				return nil, err
+			}
+			d.push(top)
+
+		case opNone:
+			d.push(nil)
+		case opNewTrue:
+			d.push(true)
+		case opNewFalse:
+			d.push(false)
+
+		case opInt:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			// Protocol 0 encodes booleans as "I01" and "I00".
+			switch line {
+			case "01":
+				d.push(true)
+				continue
+			case "00":
+				d.push(false)
+				continue
+			}
+			v, err := strconv.ParseInt(line, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pickle INT %q: %v", line, err)
+			}
+			d.push(v)
+		case opLong:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			n, ok := new(big.Int).SetString(strings.TrimSuffix(line, "L"), 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid pickle LONG %q", line)
+			}
+			d.push(bigIntValue(n))
+		case opBinInt:
+			b, err := d.read(4)
+			if err != nil {
+				return nil, err
+			}
+			d.push(int64(int32(binary.LittleEndian.Uint32(b))))
+		case opBinInt1:
+			b, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			d.push(int64(b))
+		case opBinInt2:
+			b, err := d.read(2)
+			if err != nil {
+				return nil, err
+			}
+			d.push(int64(binary.LittleEndian.Uint16(b)))
+		case opLong1, opLong4:
+			var n int
+			if op == opLong1 {
+				b, err := d.readByte()
+				if err != nil {
+					return nil, err
+				}
+				n = int(b)
+			} else {
+				if n, err = d.readLength(4); err != nil {
+					return nil, err
+				}
+			}
+			b, err := d.read(n)
+			if err != nil {
+				return nil, err
+			}
+			d.push(decodeLong(b))
+
+		case opFloat:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			v, err := strconv.ParseFloat(line, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pickle FLOAT %q: %v", line, err)
+			}
+			d.push(v)
+		case opBinFloat:
+			b, err := d.read(8)
+			if err != nil {
+				return nil, err
+			}
+			d.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+
+		case opString:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			v, err := unquotePickleString(line)
+			if err != nil {
+				return nil, err
+			}
+			d.push(v)
+		case opUnicode:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			d.push(line)
+		case opShortBinString, opShortBinBytes, opShortBinUni:
+			n, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.pushString(int(n)); err != nil {
+				return nil, err
+			}
+		case opBinString, opBinBytes, opBinUnicode:
+			n, err := d.readLength(4)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.pushString(n); err != nil {
+				return nil, err
+			}
+		case opBinUnicode8, opBinBytes8:
+			n, err := d.readLength(8)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.pushString(n); err != nil {
+				return nil, err
+			}
+
+		case opEmptyList:
+			d.push(&pickleList{})
+		case opList:
+			items, err := d.popMark()
+			if err != nil {
+				return nil, err
+			}
+			d.push(&pickleList{items: items})
+		case opAppend:
+			v, err := d.pop()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.appendToList(v); err != nil {
+				return nil, err
+			}
+		case opAppends:
+			items, err := d.popMark()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.appendToList(items...); err != nil {
+				return nil, err
+			}
+
+		case opEmptyTuple:
+			d.push(pickleTuple{})
+		case opTuple:
+			items, err := d.popMark()
+			if err != nil {
+				return nil, err
+			}
+			d.push(pickleTuple(items))
+		case opTuple1, opTuple2, opTuple3:
+			n := int(op-opTuple1) + 1
+			if len(d.stack) < n {
+				return nil, errors.New("pickle stack underflow")
+			}
+			items := make(pickleTuple, n)
+			copy(items, d.stack[len(d.stack)-n:])
+			d.stack = d.stack[:len(d.stack)-n]
+			d.push(items)
+
+		case opPut:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pickle PUT index %q: %v", line, err)
+			}
+			if err = d.memoize(idx); err != nil {
+				return nil, err
+			}
+		case opBinPut:
+			idx, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.memoize(int(idx)); err != nil {
+				return nil, err
+			}
+		case opLongBinPut:
+			idx, err := d.readLength(4)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.memoize(idx); err != nil {
+				return nil, err
+			}
+		case opMemoize:
+			if err = d.memoize(len(d.memo)); err != nil {
+				return nil, err
+			}
+		case opGet:
+			line, err := d.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pickle GET index %q: %v", line, err)
+			}
+			if err = d.pushMemo(idx); err != nil {
+				return nil, err
+			}
+		case opBinGet:
+			idx, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.pushMemo(int(idx)); err != nil {
+				return nil, err
+			}
+		case opLongBinGet:
+			idx, err := d.readLength(4)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.pushMemo(idx); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported pickle opcode 0x%02x at offset %d", op, d.pos-1)
+		}
+	}
+}
+
+func (d *pickleDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errPickleTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *pickleDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errPickleTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readLength reads a little-endian unsigned length of size 4 or 8 and checks
+// that it does not exceed the data left to be decoded.
+func (d *pickleDecoder) readLength(size int) (int, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	if size == 4 {
+		n = uint64(binary.LittleEndian.Uint32(b))
+	} else {
+		n = binary.LittleEndian.Uint64(b)
+	}
+	if n > uint64(len(d.data)) {
+		return 0, errPickleTruncated
+	}
+	return int(n), nil
+}
+
+func (d *pickleDecoder) readLine() (string, error) {
+	idx := bytes.IndexByte(d.data[d.pos:], '\n')
+	if idx < 0 {
+		return "", errPickleTruncated
+	}
+	line := string(d.data[d.pos : d.pos+idx])
+	d.pos += idx + 1
+	return line, nil
+}
+
+func (d *pickleDecoder) pushString(n int) error {
+	b, err := d.read(n)
+	if err != nil {
+		return err
+	}
+	d.push(string(b))
+	return nil
+}
+
+func (d *pickleDecoder) push(v interface{}) {
+	d.stack = append(d.stack, v)
+}
+
+func (d *pickleDecoder) peek() (interface{}, error) {
+	if len(d.stack) == 0 {
+		return nil, errors.New("pickle stack underflow")
+	}
+	return d.stack[len(d.stack)-1], nil
+}
+
+func (d *pickleDecoder) pop() (interface{}, error) {
+	v, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	return v, nil
+}
+
+// popMark pops and returns all the items pushed after the topmost mark.
+func (d *pickleDecoder) popMark() ([]interface{}, error) {
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		if _, ok := d.stack[i].(pickleMark); ok {
+			items := make([]interface{}, len(d.stack)-i-1)
+			copy(items, d.stack[i+1:])
+			d.stack = d.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, errors.New("pickle mark not found")
+}
+
+func (d *pickleDecoder) appendToList(items ...interface{}) error {
+	top, err := d.peek()
+	if err != nil {
+		return err
+	}
+	list, ok := top.(*pickleList)
+	if !ok {
+		return fmt.Errorf("pickle APPEND on unsupported type %T", top)
+	}
+	list.items = append(list.items, items...)
+	return nil
+}
+
+func (d *pickleDecoder) memoize(idx int) error {
+	top, err := d.peek()
+	if err != nil {
+		return err
+	}
+	d.memo[idx] = top
+	return nil
+}
+
+func (d *pickleDecoder) pushMemo(idx int) error {
+	v, ok := d.memo[idx]
+	if !ok {
+		return fmt.Errorf("pickle memo key %d not found", idx)
+	}
+	d.push(v)
+	return nil
+}
+
+// decodeLong decodes the little-endian two's complement integer used by the
+// LONG1 and LONG4 opcodes.
+func decodeLong(b []byte) interface{} {
+	if len(b) == 0 {
+		return int64(0)
+	}
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	n := new(big.Int).SetBytes(be)
+	if b[len(b)-1]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return bigIntValue(n)
+}
+
+// bigIntValue returns an int64 when the value fits it or a float64 otherwise.
+func bigIntValue(n *big.Int) interface{} {
+	if n.IsInt64() {
+		return n.Int64()
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return f
+}
+
+// unquotePickleString handles the quoted repr used by the protocol 0 STRING opcode.
+func unquotePickleString(s string) (string, error) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		if s[0] == '\'' {
+			// strconv.Unquote only accepts single quotes around single runes.
+			s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+		}
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid pickle STRING %q: %v", s, err)
+		}
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid pickle STRING %q: missing quotes", s)
+}
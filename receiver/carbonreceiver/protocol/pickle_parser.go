@@ -0,0 +1,218 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	// pickleHeaderSize is the size of the big-endian length that precedes
+	// each pickled message.
+	pickleHeaderSize = 4
+
+	// pickleMaxMessageSizeDefault is the same limit used by Carbon itself.
+	pickleMaxMessageSizeDefault = 1 << 20
+)
+
+var errPickleLineParsing = errors.New(
+	"pickle parser requires a stream transport, it can not parse lines")
+
+// PickleConfig holds the configuration for the pickle parser, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+//
+// Each message is a 4 bytes big-endian length followed by a pickled list of
+// tuples in the following format:
+//
+// 	[(<metric_path>, (<metric_timestamp>, <metric_value>)), ...]
+//
+// The <metric_path> of each tuple is handled exactly like by the "plaintext"
+// parser, unless Rules are specified, in which case it is handled like by the
+// "regex" parser. Only the pickle opcodes that build plain data are accepted,
+// messages trying to construct arbitrary objects are rejected.
+type PickleConfig struct {
+	// Rules contains optional regular expression rules to be applied to the
+	// metric paths, see RegexParserConfig for details.
+	Rules []*RegexRule `mapstructure:"rules"`
+
+	// MetricNameSeparator is used when joining the name prefix of each
+	// individual rule and the respective named captures that start with the
+	// prefix "name_", see RegexParserConfig for details.
+	MetricNameSeparator string `mapstructure:"name_separator"`
+
+	// MaxMessageSize is the maximum size in bytes of a single pickled message,
+	// larger messages cause the connection to be closed. The default is 1MiB.
+	MaxMessageSize int `mapstructure:"max_message_size"`
+}
+
+var _ (ParserConfig) = (*PickleConfig)(nil)
+
+// BuildParser creates a new Parser instance that receives pickled Carbon data.
+func (pc *PickleConfig) BuildParser() (Parser, error) {
+	if pc == nil {
+		return nil, errors.New("nil receiver on PickleConfig.BuildParser")
+	}
+
+	if pc.MaxMessageSize < 0 {
+		return nil, fmt.Errorf("invalid max_message_size: %d", pc.MaxMessageSize)
+	}
+
+	var pathParser PathParser = &PlaintextPathParser{}
+	if len(pc.Rules) > 0 {
+		if err := compileRegexRules(pc.Rules); err != nil {
+			return nil, err
+		}
+		pathParser = &regexPathParser{
+			rules:               pc.Rules,
+			metricNameSeparator: pc.MetricNameSeparator,
+		}
+	}
+
+	maxMessageSize := pc.MaxMessageSize
+	if maxMessageSize == 0 {
+		maxMessageSize = pickleMaxMessageSizeDefault
+	}
+
+	return &pickleParser{
+		pph:            &PathParserHelper{pathParser: pathParser},
+		maxMessageSize: maxMessageSize,
+	}, nil
+}
+
+type pickleParser struct {
+	pph            *PathParserHelper
+	maxMessageSize int
+}
+
+var _ (StreamParser) = (*pickleParser)(nil)
+
+// Parse is not supported by the pickle parser since the protocol is binary.
+func (pp *pickleParser) Parse(string) (*metricspb.Metric, error) {
+	return nil, errPickleLineParsing
+}
+
+// ReadMessage reads a length prefixed pickled message.
+func (pp *pickleParser) ReadMessage(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, pickleHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > uint32(pp.maxMessageSize) {
+		return nil, fmt.Errorf(
+			"pickle message size %d exceeds the maximum of %d bytes", size, pp.maxMessageSize)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ParseMessage decodes a pickled message into the collector metric format.
+func (pp *pickleParser) ParseMessage(msg []byte) ([]*metricspb.Metric, error) {
+	root, err := unpickle(msg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pickle message: %v", err)
+	}
+
+	samples, ok := pickleSequence(root)
+	if !ok {
+		return nil, fmt.Errorf("invalid pickle message: expected a list got %T", root)
+	}
+
+	var errs []error
+	metrics := make([]*metricspb.Metric, 0, len(samples))
+	for _, sample := range samples {
+		metric, err := pp.parseSample(sample)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, consumererror.Combine(errs)
+}
+
+// parseSample converts a single (<metric_path>, (<metric_timestamp>, <metric_value>)) tuple.
+func (pp *pickleParser) parseSample(sample interface{}) (*metricspb.Metric, error) {
+	parts, ok := pickleSequence(sample)
+	if !ok || len(parts) != 2 {
+		return nil, fmt.Errorf("invalid carbon pickle sample [%v]", sample)
+	}
+
+	path, ok := parts[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid carbon pickle metric path [%v]", parts[0])
+	}
+
+	datapoint, ok := pickleSequence(parts[1])
+	if !ok || len(datapoint) != 2 {
+		return nil, fmt.Errorf("invalid carbon pickle datapoint for [%s]: %v", path, parts[1])
+	}
+
+	var unixTime int64
+	switch ts := datapoint[0].(type) {
+	case int64:
+		unixTime = ts
+	case float64:
+		if math.IsNaN(ts) || math.IsInf(ts, 0) {
+			return nil, fmt.Errorf("invalid carbon pickle metric time for [%s]: %v", path, ts)
+		}
+		unixTime = int64(ts)
+	default:
+		return nil, fmt.Errorf("invalid carbon pickle metric time for [%s]: %v", path, datapoint[0])
+	}
+
+	point := metricspb.Point{
+		Timestamp: convertUnixSec(unixTime),
+	}
+	switch v := datapoint[1].(type) {
+	case int64:
+		point.Value = &metricspb.Point_Int64Value{Int64Value: v}
+	case float64:
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: v}
+	default:
+		return nil, fmt.Errorf("invalid carbon pickle metric value for [%s]: %v", path, datapoint[1])
+	}
+
+	return pp.pph.ParsePoint(path, &point)
+}
+
+// pickleSequence returns the items of a decoded pickle list or tuple.
+func pickleSequence(v interface{}) ([]interface{}, bool) {
+	switch seq := v.(type) {
+	case *pickleList:
+		return seq.items, true
+	case pickleTuple:
+		return seq, true
+	}
+	return nil, false
+}
+
+func pickleDefaultConfig() ParserConfig {
+	return &PickleConfig{}
+}
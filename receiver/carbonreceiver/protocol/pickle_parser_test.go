@@ -0,0 +1,183 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The payloads below were generated with Python's pickle.dumps for:
+//
+// 	[("test.metric;key=value", (1582230020, 1)), ("test.double", (1582230020.5, 2.5))]
+const (
+	pickleProtocol0 = "(lp0\n(Vtest.metric;key=value\np1\n(I1582230020\nI1\ntp2\ntp3\na(Vtest.double\np4\n(F1582230020.5\nF2.5\ntp5\ntp6\na."
+	pickleProtocol2 = "\x80\x02]q\x00(X\x15\x00\x00\x00test.metric;key=valueq\x01J\x04\xeaN^K\x01\x86q\x02\x86q\x03X\x0b\x00\x00\x00test.doubleq\x04GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86q\x05\x86q\x06e."
+	pickleProtocol4 = "\x80\x04\x95L\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x15test.metric;key=value\x94J\x04\xeaN^K\x01\x86\x94\x86\x94\x8c\x0btest.double\x94GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94e."
+)
+
+func Test_pickleParser_ParseMessage(t *testing.T) {
+	p, err := (&PickleConfig{}).BuildParser()
+	require.NoError(t, err)
+	sp, ok := p.(StreamParser)
+	require.True(t, ok)
+
+	want := []*metricspb.Metric{
+		buildMetric(
+			metricspb.MetricDescriptor_GAUGE_INT64,
+			"test.metric",
+			[]string{"key"},
+			[]string{"value"},
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{Seconds: 1582230020},
+				Value:     &metricspb.Point_Int64Value{Int64Value: 1},
+			},
+		),
+		buildMetric(
+			metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			"test.double",
+			nil,
+			nil,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{Seconds: 1582230020},
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: 2.5},
+			},
+		),
+	}
+
+	tests := []struct {
+		name string
+		msg  string
+	}{
+		{name: "protocol_0", msg: pickleProtocol0},
+		{name: "protocol_2", msg: pickleProtocol2},
+		{name: "protocol_4", msg: pickleProtocol4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sp.ParseMessage([]byte(tt.msg))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func Test_pickleParser_ParseMessageErrors(t *testing.T) {
+	p, err := (&PickleConfig{}).BuildParser()
+	require.NoError(t, err)
+	sp := p.(StreamParser)
+
+	tests := []struct {
+		name        string
+		msg         string
+		wantMetrics int
+	}{
+		{
+			// pickle.dumps(os.system, protocol=2)
+			name: "global_rejected",
+			msg:  "\x80\x02cposix\nsystem\nq\x00.",
+		},
+		{
+			name: "reduce_rejected",
+			msg:  "(S'ls'\ntR.",
+		},
+		{
+			name: "truncated",
+			msg:  pickleProtocol2[:20],
+		},
+		{
+			name: "not_a_list",
+			msg:  "I1\n.",
+		},
+		{
+			// [("ok", (1, 1)), ("bad", "x")] with protocol 0 and str paths
+			name:        "partial_success",
+			msg:         "(lp0\n(S'ok'\n(I1\nI1\nttp1\na(S'bad'\nS'x'\ntp2\na.",
+			wantMetrics: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sp.ParseMessage([]byte(tt.msg))
+			assert.Error(t, err)
+			assert.Len(t, got, tt.wantMetrics)
+		})
+	}
+}
+
+func Test_pickleParser_Rules(t *testing.T) {
+	p, err := (&PickleConfig{
+		Rules: []*RegexRule{
+			{Regexp: `(?P<key_svc>[^.]+)\.(?P<name_0>.*)`, MetricType: "cumulative"},
+		},
+	}).BuildParser()
+	require.NoError(t, err)
+
+	got, err := p.(StreamParser).ParseMessage([]byte(pickleProtocol2))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "metric;key=value", got[0].MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, got[0].MetricDescriptor.Type)
+	assert.Equal(t, "svc", got[0].MetricDescriptor.LabelKeys[0].Key)
+	assert.Equal(t, "test", got[0].Timeseries[0].LabelValues[0].Value)
+}
+
+func Test_pickleParser_ReadMessage(t *testing.T) {
+	p, err := (&PickleConfig{MaxMessageSize: 128}).BuildParser()
+	require.NoError(t, err)
+	sp := p.(StreamParser)
+
+	var buf bytes.Buffer
+	for _, msg := range []string{pickleProtocol2, pickleProtocol4} {
+		header := make([]byte, 4)
+		binary.BigEndian.PutUint32(header, uint32(len(msg)))
+		buf.Write(header)
+		buf.WriteString(msg)
+	}
+	buf.Write([]byte{0, 0, 1, 0})
+
+	r := bufio.NewReader(&buf)
+	msg, err := sp.ReadMessage(r)
+	require.NoError(t, err)
+	assert.Equal(t, pickleProtocol2, string(msg))
+
+	msg, err = sp.ReadMessage(r)
+	require.NoError(t, err)
+	assert.Equal(t, pickleProtocol4, string(msg))
+
+	_, err = sp.ReadMessage(r)
+	assert.EqualError(t, err, "pickle message size 256 exceeds the maximum of 128 bytes")
+
+	_, err = sp.ReadMessage(bufio.NewReader(&bytes.Buffer{}))
+	assert.Equal(t, io.EOF, err)
+}
+
+func Test_pickleParser_Parse(t *testing.T) {
+	p, err := (&PickleConfig{}).BuildParser()
+	require.NoError(t, err)
+	_, err = p.Parse("test.metric 1 1582230020")
+	assert.Equal(t, errPickleLineParsing, err)
+
+	_, err = (&PickleConfig{MaxMessageSize: -1}).BuildParser()
+	assert.Error(t, err)
+}
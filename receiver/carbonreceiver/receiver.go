@@ -34,7 +34,7 @@ var (
 	errEmptyEndpoint = errors.New("empty endpoint")
 )
 
-// carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol.
+// carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", and pickle protocols.
 // see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol.
 type carbonReceiver struct {
 	sync.Mutex
@@ -77,6 +77,11 @@ func New(
 		return nil, err
 	}
 
+	if _, ok := parser.(protocol.StreamParser); ok && strings.ToLower(config.Transport) == "udp" {
+		return nil, fmt.Errorf(
+			"parser %q for receiver %v requires the tcp transport", config.Parser.Type, config.ID())
+	}
+
 	// This should be the last one built, or if any other error is raised after
 	// it, the server should be closed.
 	server, err := buildTransportServer(config)
@@ -129,6 +129,24 @@ func Test_carbonreceiver_New(t *testing.T) {
 				nextConsumer: consumertest.NewNop(),
 			},
 		},
+		{
+			name: "pickle_parser_udp",
+			args: args{
+				config: Config{
+					ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "localhost:2004",
+						Transport: "udp",
+					},
+					Parser: &protocol.Config{
+						Type:   "pickle",
+						Config: &protocol.PickleConfig{},
+					},
+				},
+				nextConsumer: consumertest.NewNop(),
+			},
+			wantErr: errors.New("parser \"pickle\" for receiver carbon requires the tcp transport"),
+		},
 		{
 			name: "negative_tcp_idle_timeout",
 			args: args{
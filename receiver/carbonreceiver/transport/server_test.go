@@ -15,6 +15,7 @@
 package transport
 
 import (
+	"encoding/binary"
 	"net"
 	"runtime"
 	"strconv"
@@ -111,3 +112,45 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		})
 	}
 }
+
+func Test_TCPServer_ListenAndServe_Pickle(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewTCPServer(addr, 1*time.Second)
+	require.NoError(t, err)
+
+	mc := new(consumertest.MetricsSink)
+	p, err := (&protocol.PickleConfig{}).BuildParser()
+	require.NoError(t, err)
+	mr := NewMockReporter(1)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	runtime.Gosched()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	// Python 2 pickle.dumps([("test.metric", (1582230020, 1))], protocol=2)
+	msg := []byte("\x80\x02]q\x00U\x0btest.metricq\x01J\x04\xeaN^K\x01\x86q\x02\x86q\x03a.")
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(msg)))
+	_, err = conn.Write(append(header, msg...))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	require.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+
+	mdd := mc.AllMetrics()
+	require.Len(t, mdd, 1)
+	_, _, metrics := internaldata.ResourceMetricsToOC(mdd[0].ResourceMetrics().At(0))
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "test.metric", metrics[0].GetMetricDescriptor().GetName())
+}
@@ -146,6 +146,7 @@ func (t *tcpServer) handleConnection(
 	defer conn.Close()
 	var span *trace.Span
 	reader := bufio.NewReader(conn)
+	streamParser, isStream := p.(protocol.StreamParser)
 	for {
 		if span != nil {
 			span.End()
@@ -160,31 +161,49 @@ func (t *tcpServer) handleConnection(
 			return
 		}
 
-		// reader.ReadBytes call below will block until either:
+		// The read calls below will block until either:
 		//
-		// * a '\n' char is read
+		// * a '\n' char is read, or a complete message for stream parsers
 		// * the connection is closed (either by client or server)
 		// * an idle timeout happens (see call to conn.SetDeadline above)
 		//
 		// Notice that it is possible for the function to return with error at
 		// the same time that it returns data (typically the error is io.EOF in
 		// this case).
-		bytes, err := reader.ReadBytes((byte)('\n'))
+		var bytes []byte
+		var err error
+		if isStream {
+			bytes, err = streamParser.ReadMessage(reader)
+		} else {
+			bytes, err = reader.ReadBytes((byte)('\n'))
+		}
 
 		// It is possible to have new data in bytes and err to be io.EOF
 		ctx := t.reporter.OnDataReceived(context.Background())
-		var numReceivedMetricPoints int
-		line := strings.TrimSpace(string(bytes))
-		if line != "" {
-			numReceivedMetricPoints++
+		var metrics []*metricspb.Metric
+		var parseErr error
+		if isStream {
+			if len(bytes) > 0 {
+				metrics, parseErr = streamParser.ParseMessage(bytes)
+			}
+		} else if line := strings.TrimSpace(string(bytes)); line != "" {
 			var metric *metricspb.Metric
-			metric, err = p.Parse(line)
-			if err != nil {
-				t.reporter.OnTranslationError(ctx, err)
+			metric, parseErr = p.Parse(line)
+			if metric != nil {
+				metrics = append(metrics, metric)
+			}
+		}
+
+		if parseErr != nil {
+			t.reporter.OnTranslationError(ctx, parseErr)
+			if len(metrics) == 0 {
 				continue
 			}
+		}
 
-			err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, []*metricspb.Metric{metric}))
+		if len(metrics) > 0 {
+			numReceivedMetricPoints := len(metrics)
+			err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, metrics))
 			t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
 			if err != nil {
 				// The protocol doesn't account for returning errors.
@@ -216,5 +235,13 @@ func (t *tcpServer) handleConnection(
 			span.End()
 			return
 		}
+
+		if err != nil && isStream {
+			// The framing of the stream was lost, nothing else can be read
+			// from this connection.
+			t.reporter.OnTranslationError(ctx, err)
+			span.End()
+			return
+		}
 	}
 }
@@ -16,6 +16,7 @@ package dockerstatsreceiver
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -23,10 +24,24 @@ import (
 
 var _ config.Receiver = (*Config)(nil)
 
+// The container runtimes whose API can be queried.
+const (
+	dockerRuntime     = "docker"
+	containerdRuntime = "containerd"
+)
+
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"`
 	// The URL of the docker server.  Default is "unix:///var/run/docker.sock"
 	Endpoint string `mapstructure:"endpoint"`
+
+	// The API of the container runtime served at the endpoint, "docker" (which
+	// includes Docker-compatible APIs like Podman's) or "containerd".  Default is "docker"
+	Runtime string `mapstructure:"runtime"`
+
+	// The containerd namespace whose containers are monitored, e.g. "k8s.io" for
+	// the containers of Kubernetes.  Default is "default"
+	ContainerdNamespace string `mapstructure:"containerd_namespace"`
 	// The time between each collection event.  Default is 10s.
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
 
@@ -53,6 +68,10 @@ type Config struct {
 
 	// Whether to report all CPU metrics.  Default is false
 	ProvidePerCoreCPUMetrics bool `mapstructure:"provide_per_core_cpu_metrics"`
+
+	// Whether to report the state, health, restart count and exit code of all the
+	// containers, including the ones that aren't running.  Default is false
+	ProvideContainerStateMetrics bool `mapstructure:"provide_container_state_metrics"`
 }
 
 func (config Config) Validate() error {
@@ -62,5 +81,17 @@ func (config Config) Validate() error {
 	if config.CollectionInterval == 0 {
 		return errors.New("config.CollectionInterval must be specified")
 	}
+	switch config.Runtime {
+	case "", dockerRuntime:
+	case containerdRuntime:
+		if config.ContainerdNamespace == "" {
+			return errors.New("config.ContainerdNamespace must be specified")
+		}
+		if config.ProvideContainerStateMetrics {
+			return errors.New("config.ProvideContainerStateMetrics isn't supported by the containerd runtime")
+		}
+	default:
+		return fmt.Errorf("config.Runtime must be %q or %q, not %q", dockerRuntime, containerdRuntime, config.Runtime)
+	}
 	return nil
 }
@@ -38,7 +38,7 @@ func TestLoadConfig(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
-	assert.Equal(t, 2, len(cfg.Receivers))
+	assert.Equal(t, 3, len(cfg.Receivers))
 
 	defaultConfig := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, factory.CreateDefaultConfig(), defaultConfig)
@@ -46,6 +46,8 @@ func TestLoadConfig(t *testing.T) {
 	dcfg := defaultConfig.(*Config)
 	assert.Equal(t, "docker_stats", dcfg.ID().String())
 	assert.Equal(t, "unix:///var/run/docker.sock", dcfg.Endpoint)
+	assert.Equal(t, "docker", dcfg.Runtime)
+	assert.Equal(t, "default", dcfg.ContainerdNamespace)
 	assert.Equal(t, 10*time.Second, dcfg.CollectionInterval)
 	assert.Equal(t, 5*time.Second, dcfg.Timeout)
 
@@ -54,6 +56,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.Nil(t, dcfg.EnvVarsToMetricLabels)
 
 	assert.False(t, dcfg.ProvidePerCoreCPUMetrics)
+	assert.False(t, dcfg.ProvideContainerStateMetrics)
 
 	ascfg := cfg.Receivers[config.NewIDWithName(typeStr, "allsettings")].(*Config)
 	assert.Equal(t, "docker_stats/allsettings", ascfg.ID().String())
@@ -77,4 +80,25 @@ func TestLoadConfig(t *testing.T) {
 	}, ascfg.EnvVarsToMetricLabels)
 
 	assert.True(t, ascfg.ProvidePerCoreCPUMetrics)
+	assert.True(t, ascfg.ProvideContainerStateMetrics)
+
+	ccfg := cfg.Receivers[config.NewIDWithName(typeStr, "containerd")].(*Config)
+	assert.Equal(t, "unix:///run/containerd/containerd.sock", ccfg.Endpoint)
+	assert.Equal(t, "containerd", ccfg.Runtime)
+	assert.Equal(t, "k8s.io", ccfg.ContainerdNamespace)
+	assert.NoError(t, ccfg.Validate())
+}
+
+func TestValidateRuntime(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Runtime = "cri-o"
+	assert.EqualError(t, cfg.Validate(), `config.Runtime must be "docker" or "containerd", not "cri-o"`)
+
+	cfg.Runtime = containerdRuntime
+	cfg.ContainerdNamespace = ""
+	assert.EqualError(t, cfg.Validate(), "config.ContainerdNamespace must be specified")
+
+	cfg.ContainerdNamespace = "k8s.io"
+	cfg.ProvideContainerStateMetrics = true
+	assert.EqualError(t, cfg.Validate(), "config.ProvideContainerStateMetrics isn't supported by the containerd runtime")
 }
@@ -0,0 +1,569 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	cgroupsv1 "github.com/containerd/cgroups/stats/v1"
+	cgroupsv2 "github.com/containerd/cgroups/v2/stats"
+	containersapi "github.com/containerd/containerd/api/services/containers/v1"
+	tasksapi "github.com/containerd/containerd/api/services/tasks/v1"
+	ctypes "github.com/containerd/containerd/api/types"
+	ctask "github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/typeurl"
+	dtypes "github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The clock ticks per second of the cpu times of /proc/stat, which is 100 on
+// all the architectures supported by Docker.
+const clockTicksPerSecond = 100
+
+// containerdClient provides the core metric gathering functionality from containerd.
+// It describes the containers of a namespace with running tasks with the same
+// dtypes.ContainerJSON as the Docker Daemon (using their id as name, since containerd
+// containers have none), and converts the cgroup v1 or v2 metrics of their tasks to
+// dtypes.StatsJSON.  containerd doesn't report network stats.
+type containerdClient struct {
+	conn                 *grpc.ClientConn
+	containerService     containersapi.ContainersClient
+	taskService          tasksapi.TasksClient
+	config               *Config
+	containers           map[string]DockerContainer
+	previousCPUStats     map[string]dtypes.CPUStats
+	containersLock       sync.Mutex
+	excludedImageMatcher *StringMatcher
+	procStatPath         string
+	logger               *zap.Logger
+}
+
+func newContainerdClient(config *Config, logger *zap.Logger) (*containerdClient, error) {
+	conn, err := grpc.Dial(config.Endpoint, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("could not create containerd client: %w", err)
+	}
+
+	excludedImageMatcher, err := NewStringMatcher(config.ExcludedImages)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not determine containerd client excluded images: %w", err)
+	}
+
+	cc := &containerdClient{
+		conn:                 conn,
+		containerService:     containersapi.NewContainersClient(conn),
+		taskService:          tasksapi.NewTasksClient(conn),
+		config:               config,
+		logger:               logger,
+		containers:           make(map[string]DockerContainer),
+		previousCPUStats:     make(map[string]dtypes.CPUStats),
+		containersLock:       sync.Mutex{},
+		excludedImageMatcher: excludedImageMatcher,
+		procStatPath:         "/proc/stat",
+	}
+
+	return cc, nil
+}
+
+// Provides a slice of DockerContainers to use for individual FetchContainerStats calls.
+func (cc *containerdClient) Containers() []DockerContainer {
+	cc.containersLock.Lock()
+	defer cc.containersLock.Unlock()
+	containers := make([]DockerContainer, 0, len(cc.containers))
+	for _, container := range cc.containers {
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// ContainerStates isn't supported by containerd, whose containers don't have a
+// Docker container state.
+func (cc *containerdClient) ContainerStates() []DockerContainer {
+	return nil
+}
+
+// LoadContainerList replaces the monitored containers with the containers of the
+// namespace whose task is running.
+func (cc *containerdClient) LoadContainerList(ctx context.Context) error {
+	ctx = namespaces.WithNamespace(ctx, cc.config.ContainerdNamespace)
+
+	listCtx, cancel := context.WithTimeout(ctx, cc.config.Timeout)
+	containerList, err := cc.containerService.List(listCtx, &containersapi.ListContainersRequest{})
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	containers := make(map[string]DockerContainer, len(containerList.Containers))
+	for i := range containerList.Containers {
+		container := &containerList.Containers[i]
+		if cc.shouldBeExcluded(container.Image) {
+			cc.logger.Debug(
+				"Not monitoring container per ExcludedImages",
+				zap.String("image", container.Image),
+				zap.String("id", container.ID),
+			)
+			continue
+		}
+
+		running, err := cc.taskIsRunning(ctx, container.ID)
+		if err != nil {
+			return err
+		}
+		if !running {
+			cc.logger.Debug("containerd task not running.  Will not persist.", zap.String("id", container.ID))
+			continue
+		}
+
+		containerJSON, err := toContainerJSON(container)
+		if err != nil {
+			cc.logger.Error(
+				"Could not decode the spec of container",
+				zap.String("id", container.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		containers[container.ID] = DockerContainer{
+			ContainerJSON: containerJSON,
+			EnvMap:        containerEnvToMap(containerJSON.Config.Env),
+		}
+	}
+
+	cc.containersLock.Lock()
+	defer cc.containersLock.Unlock()
+	cc.containers = containers
+	for cid := range cc.previousCPUStats {
+		if _, ok := containers[cid]; !ok {
+			delete(cc.previousCPUStats, cid)
+		}
+	}
+	return nil
+}
+
+func (cc *containerdClient) taskIsRunning(ctx context.Context, cid string) (bool, error) {
+	getCtx, cancel := context.WithTimeout(ctx, cc.config.Timeout)
+	task, err := cc.taskService.Get(getCtx, &tasksapi.GetRequest{ContainerID: cid})
+	defer cancel()
+	if err != nil {
+		// The containers which were never started or whose task was deleted have no task
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return task.Process != nil && task.Process.Status == ctask.StatusRunning, nil
+}
+
+// ociSpec is the part of the OCI runtime spec of a container with its hostname
+// and environment variables.
+type ociSpec struct {
+	Hostname string `json:"hostname"`
+	Process  *struct {
+		Env []string `json:"env"`
+	} `json:"process"`
+}
+
+func toContainerJSON(container *containersapi.Container) (*dtypes.ContainerJSON, error) {
+	var spec ociSpec
+	if container.Spec != nil {
+		if err := json.Unmarshal(container.Spec.Value, &spec); err != nil {
+			return nil, err
+		}
+	}
+
+	var env []string
+	if spec.Process != nil {
+		env = spec.Process.Env
+	}
+
+	return &dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:   container.ID,
+			Name: container.ID,
+			State: &dtypes.ContainerState{
+				Status:  "running",
+				Running: true,
+			},
+		},
+		Config: &dcontainer.Config{
+			Hostname: spec.Hostname,
+			Image:    container.Image,
+			Labels:   container.Labels,
+			Env:      env,
+		},
+	}, nil
+}
+
+// ContainerEventLoop reloads the containers on every collection interval, since
+// containerd tasks are started and stopped independently from their container.
+func (cc *containerdClient) ContainerEventLoop(ctx context.Context) {
+	ticker := time.NewTicker(cc.config.CollectionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := cc.LoadContainerList(ctx); err != nil && ctx.Err() == nil {
+				cc.logger.Error("Could not reload containerd containers", zap.Error(err))
+			}
+		}
+	}
+}
+
+// FetchContainerStatsAndConvertToMetrics will query the metrics of the task of the
+// container and convert them to metrics, since this is intended to be run in a goroutine.
+func (cc *containerdClient) FetchContainerStatsAndConvertToMetrics(
+	ctx context.Context,
+	container DockerContainer,
+) (*agentmetricspb.ExportMetricsServiceRequest, error) {
+	cc.logger.Debug("Fetching container stats.", zap.String("id", container.ID))
+	ctx = namespaces.WithNamespace(ctx, cc.config.ContainerdNamespace)
+	statsCtx, cancel := context.WithTimeout(ctx, cc.config.Timeout)
+	metricsResponse, err := cc.taskService.Metrics(statsCtx, &tasksapi.MetricsRequest{
+		Filters: []string{"id==" + container.ID},
+	})
+	defer cancel()
+	if err != nil {
+		cc.logger.Warn(
+			"Could not fetch containerd task metrics for container",
+			zap.String("id", container.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if len(metricsResponse.Metrics) == 0 {
+		cc.logger.Debug(
+			"containerd reported no task for the container. Will no longer monitor.",
+			zap.String("id", container.ID),
+		)
+		cc.removeContainer(container.ID)
+		return nil, fmt.Errorf("no task metrics for container %s", container.ID)
+	}
+
+	statsJSON, err := taskMetricsToStatsJSON(metricsResponse.Metrics[0])
+	if err != nil {
+		cc.logger.Error(
+			"Could not parse containerd task metrics for container id",
+			zap.String("id", container.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// The system usage and online cpus are read like the Docker Daemon does, so that
+	// cpu.percent is computed the same way
+	statsJSON.CPUStats.SystemUsage, statsJSON.CPUStats.OnlineCPUs, err = readProcStat(cc.procStatPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.containersLock.Lock()
+	previous, ok := cc.previousCPUStats[container.ID]
+	if !ok {
+		// There's no percentage until the next collection
+		previous = statsJSON.CPUStats
+	}
+	cc.previousCPUStats[container.ID] = statsJSON.CPUStats
+	cc.containersLock.Unlock()
+	statsJSON.PreCPUStats = previous
+
+	md, err := ContainerStatsToMetrics(statsJSON, &container, cc.config)
+	if err != nil {
+		cc.logger.Error(
+			"Could not convert containerd task metrics for container id",
+			zap.String("id", container.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return md, nil
+}
+
+// taskMetricsToStatsJSON converts the cgroup v1 or v2 metrics of a task to the stats
+// reported by the Docker Daemon for the same cgroup.
+func taskMetricsToStatsJSON(metric *ctypes.Metric) (*dtypes.StatsJSON, error) {
+	if metric.Data == nil {
+		return nil, fmt.Errorf("no data in the metrics of task %s", metric.ID)
+	}
+	data, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	statsJSON := &dtypes.StatsJSON{}
+	statsJSON.ID = metric.ID
+	statsJSON.Read = metric.Timestamp
+	switch m := data.(type) {
+	case *cgroupsv1.Metrics:
+		cgroupV1Stats(m, statsJSON)
+	case *cgroupsv2.Metrics:
+		cgroupV2Stats(m, statsJSON)
+	default:
+		return nil, fmt.Errorf("unsupported metrics type %s of task %s", metric.Data.TypeUrl, metric.ID)
+	}
+	return statsJSON, nil
+}
+
+func cgroupV1Stats(m *cgroupsv1.Metrics, statsJSON *dtypes.StatsJSON) {
+	if m.CPU != nil {
+		if usage := m.CPU.Usage; usage != nil {
+			statsJSON.CPUStats.CPUUsage = dtypes.CPUUsage{
+				TotalUsage:        usage.Total,
+				PercpuUsage:       usage.PerCPU,
+				UsageInKernelmode: usage.Kernel,
+				UsageInUsermode:   usage.User,
+			}
+		}
+		if throttling := m.CPU.Throttling; throttling != nil {
+			statsJSON.CPUStats.ThrottlingData = dtypes.ThrottlingData{
+				Periods:          throttling.Periods,
+				ThrottledPeriods: throttling.ThrottledPeriods,
+				ThrottledTime:    throttling.ThrottledTime,
+			}
+		}
+	}
+
+	if mem := m.Memory; mem != nil {
+		statsJSON.MemoryStats.Stats = map[string]uint64{
+			"cache":                     mem.Cache,
+			"rss":                       mem.RSS,
+			"rss_huge":                  mem.RSSHuge,
+			"mapped_file":               mem.MappedFile,
+			"dirty":                     mem.Dirty,
+			"writeback":                 mem.Writeback,
+			"pgpgin":                    mem.PgPgIn,
+			"pgpgout":                   mem.PgPgOut,
+			"pgfault":                   mem.PgFault,
+			"pgmajfault":                mem.PgMajFault,
+			"inactive_anon":             mem.InactiveAnon,
+			"active_anon":               mem.ActiveAnon,
+			"inactive_file":             mem.InactiveFile,
+			"active_file":               mem.ActiveFile,
+			"unevictable":               mem.Unevictable,
+			"hierarchical_memory_limit": mem.HierarchicalMemoryLimit,
+			"hierarchical_memsw_limit":  mem.HierarchicalSwapLimit,
+			"total_cache":               mem.TotalCache,
+			"total_rss":                 mem.TotalRSS,
+			"total_rss_huge":            mem.TotalRSSHuge,
+			"total_mapped_file":         mem.TotalMappedFile,
+			"total_dirty":               mem.TotalDirty,
+			"total_writeback":           mem.TotalWriteback,
+			"total_pgpgin":              mem.TotalPgPgIn,
+			"total_pgpgout":             mem.TotalPgPgOut,
+			"total_pgfault":             mem.TotalPgFault,
+			"total_pgmajfault":          mem.TotalPgMajFault,
+			"total_inactive_anon":       mem.TotalInactiveAnon,
+			"total_active_anon":         mem.TotalActiveAnon,
+			"total_inactive_file":       mem.TotalInactiveFile,
+			"total_active_file":         mem.TotalActiveFile,
+			"total_unevictable":         mem.TotalUnevictable,
+		}
+		if usage := mem.Usage; usage != nil {
+			statsJSON.MemoryStats.Usage = usage.Usage
+			statsJSON.MemoryStats.MaxUsage = usage.Max
+			statsJSON.MemoryStats.Failcnt = usage.Failcnt
+			statsJSON.MemoryStats.Limit = usage.Limit
+		}
+	}
+
+	if blkio := m.Blkio; blkio != nil {
+		statsJSON.BlkioStats = dtypes.BlkioStats{
+			IoServiceBytesRecursive: blkioEntries(blkio.IoServiceBytesRecursive),
+			IoServicedRecursive:     blkioEntries(blkio.IoServicedRecursive),
+			IoQueuedRecursive:       blkioEntries(blkio.IoQueuedRecursive),
+			IoServiceTimeRecursive:  blkioEntries(blkio.IoServiceTimeRecursive),
+			IoWaitTimeRecursive:     blkioEntries(blkio.IoWaitTimeRecursive),
+			IoMergedRecursive:       blkioEntries(blkio.IoMergedRecursive),
+			IoTimeRecursive:         blkioEntries(blkio.IoTimeRecursive),
+			SectorsRecursive:        blkioEntries(blkio.SectorsRecursive),
+		}
+	}
+}
+
+func blkioEntries(entries []*cgroupsv1.BlkIOEntry) []dtypes.BlkioStatEntry {
+	if entries == nil {
+		return nil
+	}
+	out := make([]dtypes.BlkioStatEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, dtypes.BlkioStatEntry{
+			Major: entry.Major,
+			Minor: entry.Minor,
+			Op:    entry.Op,
+			Value: entry.Value,
+		})
+	}
+	return out
+}
+
+// cgroupV2Stats converts the metrics of a cgroup v2 task like the Docker Daemon does,
+// i.e. with the cpu times in ns, the memory.stat entries as memory stats and the io.stat
+// entries as the read and write blkio operations.
+func cgroupV2Stats(m *cgroupsv2.Metrics, statsJSON *dtypes.StatsJSON) {
+	if cpu := m.CPU; cpu != nil {
+		statsJSON.CPUStats.CPUUsage = dtypes.CPUUsage{
+			TotalUsage:        cpu.UsageUsec * 1000,
+			UsageInKernelmode: cpu.SystemUsec * 1000,
+			UsageInUsermode:   cpu.UserUsec * 1000,
+		}
+		statsJSON.CPUStats.ThrottlingData = dtypes.ThrottlingData{
+			Periods:          cpu.NrPeriods,
+			ThrottledPeriods: cpu.NrThrottled,
+			ThrottledTime:    cpu.ThrottledUsec * 1000,
+		}
+	}
+
+	if mem := m.Memory; mem != nil {
+		statsJSON.MemoryStats = dtypes.MemoryStats{
+			Usage: mem.Usage,
+			Limit: mem.UsageLimit,
+			Stats: map[string]uint64{
+				"anon":                   mem.Anon,
+				"file":                   mem.File,
+				"kernel_stack":           mem.KernelStack,
+				"slab":                   mem.Slab,
+				"sock":                   mem.Sock,
+				"shmem":                  mem.Shmem,
+				"file_mapped":            mem.FileMapped,
+				"file_dirty":             mem.FileDirty,
+				"file_writeback":         mem.FileWriteback,
+				"anon_thp":               mem.AnonThp,
+				"inactive_anon":          mem.InactiveAnon,
+				"active_anon":            mem.ActiveAnon,
+				"inactive_file":          mem.InactiveFile,
+				"active_file":            mem.ActiveFile,
+				"unevictable":            mem.Unevictable,
+				"slab_reclaimable":       mem.SlabReclaimable,
+				"slab_unreclaimable":     mem.SlabUnreclaimable,
+				"pgfault":                mem.Pgfault,
+				"pgmajfault":             mem.Pgmajfault,
+				"workingset_refault":     mem.WorkingsetRefault,
+				"workingset_activate":    mem.WorkingsetActivate,
+				"workingset_nodereclaim": mem.WorkingsetNodereclaim,
+				"pgrefill":               mem.Pgrefill,
+				"pgscan":                 mem.Pgscan,
+				"pgsteal":                mem.Pgsteal,
+				"pgactivate":             mem.Pgactivate,
+				"pgdeactivate":           mem.Pgdeactivate,
+				"pglazyfree":             mem.Pglazyfree,
+				"pglazyfreed":            mem.Pglazyfreed,
+				"thp_fault_alloc":        mem.ThpFaultAlloc,
+				"thp_collapse_alloc":     mem.ThpCollapseAlloc,
+			},
+		}
+	}
+
+	if m.Io != nil {
+		var serviceBytes, serviced []dtypes.BlkioStatEntry
+		for _, entry := range m.Io.Usage {
+			serviceBytes = append(serviceBytes,
+				dtypes.BlkioStatEntry{Major: entry.Major, Minor: entry.Minor, Op: "read", Value: entry.Rbytes},
+				dtypes.BlkioStatEntry{Major: entry.Major, Minor: entry.Minor, Op: "write", Value: entry.Wbytes},
+			)
+			serviced = append(serviced,
+				dtypes.BlkioStatEntry{Major: entry.Major, Minor: entry.Minor, Op: "read", Value: entry.Rios},
+				dtypes.BlkioStatEntry{Major: entry.Major, Minor: entry.Minor, Op: "write", Value: entry.Wios},
+			)
+		}
+		statsJSON.BlkioStats = dtypes.BlkioStats{
+			IoServiceBytesRecursive: serviceBytes,
+			IoServicedRecursive:     serviced,
+		}
+	}
+}
+
+// readProcStat returns the cpu time of the host in ns and its number of online cpus
+// from the "cpu" and "cpuN" lines of /proc/stat.
+func readProcStat(path string) (uint64, uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var (
+		systemUsage uint64
+		onlineCPUs  uint32
+		found       bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			onlineCPUs++
+			continue
+		}
+		// user, nice, system, idle, iowait, irq and softirq
+		if len(fields) < 8 {
+			return 0, 0, fmt.Errorf("invalid cpu line in %s: %q", path, scanner.Text())
+		}
+		var ticks uint64
+		for _, field := range fields[1:8] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid cpu line in %s: %w", path, err)
+			}
+			ticks += v
+		}
+		systemUsage = ticks * uint64(time.Second) / clockTicksPerSecond
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return 0, 0, fmt.Errorf("no cpu line in %s", path)
+	}
+	return systemUsage, onlineCPUs, nil
+}
+
+func (cc *containerdClient) removeContainer(cid string) {
+	cc.containersLock.Lock()
+	defer cc.containersLock.Unlock()
+	delete(cc.containers, cid)
+	delete(cc.previousCPUStats, cid)
+	cc.logger.Debug("Removed container from stores.", zap.String("id", cid))
+}
+
+func (cc *containerdClient) shouldBeExcluded(image string) bool {
+	return cc.excludedImageMatcher != nil && cc.excludedImageMatcher.Matches(image)
+}
+
+// Close closes the connection to containerd.
+func (cc *containerdClient) Close() error {
+	return cc.conn.Close()
+}
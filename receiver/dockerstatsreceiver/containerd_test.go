@@ -0,0 +1,325 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	cgroupsv1 "github.com/containerd/cgroups/stats/v1"
+	cgroupsv2 "github.com/containerd/cgroups/v2/stats"
+	containersapi "github.com/containerd/containerd/api/services/containers/v1"
+	tasksapi "github.com/containerd/containerd/api/services/tasks/v1"
+	ctypes "github.com/containerd/containerd/api/types"
+	ctask "github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/typeurl"
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeContainerd serves the containers of a namespace and the status and metrics of
+// their tasks.
+type fakeContainerd struct {
+	namespace  string
+	containers []containersapi.Container
+	tasks      map[string]ctask.Status
+
+	mu      sync.Mutex
+	metrics map[string]*ctypes.Metric
+}
+
+type fakeContainerService struct {
+	containersapi.UnimplementedContainersServer
+	*fakeContainerd
+}
+
+type fakeTaskService struct {
+	tasksapi.UnimplementedTasksServer
+	*fakeContainerd
+}
+
+func (fc *fakeContainerd) start(t *testing.T) (*grpc.Server, string) {
+	listener, addr := tmpSock(t)
+	srv := grpc.NewServer()
+	containersapi.RegisterContainersServer(srv, &fakeContainerService{fakeContainerd: fc})
+	tasksapi.RegisterTasksServer(srv, &fakeTaskService{fakeContainerd: fc})
+	go srv.Serve(listener)
+	return srv, addr
+}
+
+func (fc *fakeContainerService) List(ctx context.Context, _ *containersapi.ListContainersRequest) (*containersapi.ListContainersResponse, error) {
+	if ns, _ := namespaces.Namespace(ctx); ns != fc.namespace {
+		return &containersapi.ListContainersResponse{}, nil
+	}
+	return &containersapi.ListContainersResponse{Containers: fc.containers}, nil
+}
+
+func (fc *fakeTaskService) Get(_ context.Context, req *tasksapi.GetRequest) (*tasksapi.GetResponse, error) {
+	taskStatus, ok := fc.tasks[req.ContainerID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no running task found: task %s not found", req.ContainerID)
+	}
+	return &tasksapi.GetResponse{Process: &ctask.Process{ID: req.ContainerID, Status: taskStatus}}, nil
+}
+
+func (fc *fakeTaskService) Metrics(_ context.Context, req *tasksapi.MetricsRequest) (*tasksapi.MetricsResponse, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	var resp tasksapi.MetricsResponse
+	for _, filter := range req.Filters {
+		if metric, ok := fc.metrics[strings.TrimPrefix(filter, "id==")]; ok {
+			resp.Metrics = append(resp.Metrics, metric)
+		}
+	}
+	return &resp, nil
+}
+
+func (fc *fakeContainerd) deleteTask(cid string) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	delete(fc.metrics, cid)
+}
+
+func taskMetricFromFile(t *testing.T, cid string, file string, metrics interface{}) *ctypes.Metric {
+	metricsRaw, err := ioutil.ReadFile(path.Join(".", "testdata", file))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(metricsRaw, metrics))
+
+	data, err := typeurl.MarshalAny(metrics)
+	require.NoError(t, err)
+	return &ctypes.Metric{
+		Timestamp: time.Unix(1622541601, 0),
+		ID:        cid,
+		Data:      data,
+	}
+}
+
+func containerdConfig(endpoint string) *Config {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Endpoint = "unix://" + endpoint
+	config.Runtime = containerdRuntime
+	config.ContainerdNamespace = "k8s.io"
+	return config
+}
+
+func findMetric(t *testing.T, metrics []*metricspb.Metric, name string) *metricspb.Metric {
+	for _, m := range metrics {
+		if m.MetricDescriptor.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("no %s metric", name)
+	return nil
+}
+
+func TestContainerdClient(t *testing.T) {
+	const cid = "a2596076ca048f02bcd16a8acd12a7ea2d3bc430d1cde095357239dd3925a4c3"
+	fc := &fakeContainerd{
+		namespace: "k8s.io",
+		containers: []containersapi.Container{
+			{
+				ID:     cid,
+				Image:  "docker.io/library/redis:6.2",
+				Labels: map[string]string{"io.kubernetes.container.name": "redis"},
+				Spec: &gogotypes.Any{
+					TypeUrl: "types.containerd.io/opencontainers/runtime-spec/1/Spec",
+					Value:   []byte(`{"ociVersion":"1.0.2","hostname":"redis-0","process":{"env":["PATH=/usr/bin","REDIS_VERSION=6.2.3"]}}`),
+				},
+			},
+			{ID: "stopped", Image: "docker.io/library/redis:6.2"},
+			{ID: "created", Image: "docker.io/library/redis:6.2"},
+			{ID: "excluded", Image: "docker.io/library/excluded:1.0"},
+		},
+		tasks: map[string]ctask.Status{
+			cid:        ctask.StatusRunning,
+			"stopped":  ctask.StatusStopped,
+			"excluded": ctask.StatusRunning,
+		},
+		metrics: map[string]*ctypes.Metric{
+			cid: taskMetricFromFile(t, cid, "containerd_metrics_cgroupv2.json", &cgroupsv2.Metrics{}),
+		},
+	}
+	srv, addr := fc.start(t)
+	defer srv.Stop()
+	defer os.Remove(addr)
+
+	config := containerdConfig(addr)
+	config.ExcludedImages = []string{"*excluded*"}
+	config.EnvVarsToMetricLabels = map[string]string{"REDIS_VERSION": "redis_version"}
+	config.ContainerLabelsToMetricLabels = map[string]string{"io.kubernetes.container.name": "container_spec_name"}
+	cc, err := newContainerdClient(config, zap.NewNop())
+	require.NoError(t, err)
+	defer cc.Close()
+	cc.procStatPath = path.Join(".", "testdata", "proc_stat")
+
+	require.NoError(t, cc.LoadContainerList(context.Background()))
+	containers := cc.Containers()
+	require.Len(t, containers, 1)
+	assert.Equal(t, cid, containers[0].ID)
+	assert.True(t, containers[0].State.Running)
+	assert.Equal(t, "redis-0", containers[0].Config.Hostname)
+	assert.Equal(t, map[string]string{"PATH": "/usr/bin", "REDIS_VERSION": "6.2.3"}, containers[0].EnvMap)
+	assert.Empty(t, cc.ContainerStates())
+
+	md, err := cc.FetchContainerStatsAndConvertToMetrics(context.Background(), containers[0])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"container.hostname":   "redis-0",
+		"container.id":         cid,
+		"container.image.name": "docker.io/library/redis:6.2",
+		"container.name":       cid,
+		"container_spec_name":  "redis",
+		"redis_version":        "6.2.3",
+	}, md.Resource.Labels)
+	assert.Equal(t, int64(603779290000000), findMetric(t, md.Metrics, "container.cpu.usage.system").Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, int64(43620000), findMetric(t, md.Metrics, "container.cpu.usage.total").Timeseries[0].Points[0].GetInt64Value())
+	// There's no previous sample on the first fetch
+	assert.Equal(t, float64(0), findMetric(t, md.Metrics, "container.cpu.percent").Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, int64(16652890112), findMetric(t, md.Metrics, "container.memory.usage.limit").Timeseries[0].Points[0].GetInt64Value())
+
+	fc.deleteTask(cid)
+	md, err = cc.FetchContainerStatsAndConvertToMetrics(context.Background(), containers[0])
+	assert.Nil(t, md)
+	require.Error(t, err)
+	assert.Equal(t, "no task metrics for container "+cid, err.Error())
+	assert.Empty(t, cc.Containers())
+}
+
+func TestContainerdReceiver(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the host cpu time is read from /proc/stat")
+	}
+	fc := &fakeContainerd{
+		namespace:  "k8s.io",
+		containers: []containersapi.Container{{ID: "redis", Image: "docker.io/library/redis:6.2"}},
+		tasks:      map[string]ctask.Status{"redis": ctask.StatusRunning},
+		metrics: map[string]*ctypes.Metric{
+			"redis": taskMetricFromFile(t, "redis", "containerd_metrics_cgroupv1.json", &cgroupsv1.Metrics{}),
+		},
+	}
+	srv, addr := fc.start(t)
+	defer srv.Stop()
+	defer os.Remove(addr)
+
+	config := containerdConfig(addr)
+	config.CollectionInterval = 10 * time.Millisecond
+	sink := new(consumertest.MetricsSink)
+	receiver, err := NewReceiver(context.Background(), zap.NewNop(), config, sink)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	rm := sink.AllMetrics()[0].ResourceMetrics().At(0)
+	name, _ := rm.Resource().Attributes().Get("container.name")
+	assert.Equal(t, "redis", name.StringVal())
+}
+
+func TestContainerdClientOtherNamespace(t *testing.T) {
+	fc := &fakeContainerd{
+		namespace:  "default",
+		containers: []containersapi.Container{{ID: "redis", Image: "docker.io/library/redis:6.2"}},
+		tasks:      map[string]ctask.Status{"redis": ctask.StatusRunning},
+	}
+	srv, addr := fc.start(t)
+	defer srv.Stop()
+	defer os.Remove(addr)
+
+	cc, err := newContainerdClient(containerdConfig(addr), zap.NewNop())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	require.NoError(t, cc.LoadContainerList(context.Background()))
+	assert.Empty(t, cc.Containers())
+}
+
+func TestTaskMetricsToStatsJSON(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		metricsFile string
+		metrics     interface{}
+		dockerFile  string
+		// Whether the cpu times are reported in µs
+		microseconds bool
+	}{
+		{
+			name:        "cgroup v1",
+			metricsFile: "containerd_metrics_cgroupv1.json",
+			metrics:     &cgroupsv1.Metrics{},
+			dockerFile:  "stats.json",
+		},
+		{
+			name:         "cgroup v2",
+			metricsFile:  "containerd_metrics_cgroupv2.json",
+			metrics:      &cgroupsv2.Metrics{},
+			dockerFile:   "stats_cgroupv2.json",
+			microseconds: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stats, err := taskMetricsToStatsJSON(taskMetricFromFile(t, "my-container", test.metricsFile, test.metrics))
+			require.NoError(t, err)
+
+			// The stats are the ones reported by the Docker Daemon for the same cgroup
+			expected := statsJSONFromFile(t, test.dockerFile)
+			expectedCPUUsage := expected.CPUStats.CPUUsage
+			if test.microseconds {
+				expectedCPUUsage.TotalUsage -= expectedCPUUsage.TotalUsage % 1000
+			}
+			assert.Equal(t, expectedCPUUsage, stats.CPUStats.CPUUsage)
+			assert.Equal(t, expected.CPUStats.ThrottlingData, stats.CPUStats.ThrottlingData)
+			assert.Equal(t, expected.MemoryStats, stats.MemoryStats)
+			assert.Equal(t, expected.BlkioStats.IoServiceBytesRecursive, stats.BlkioStats.IoServiceBytesRecursive)
+			assert.Equal(t, expected.BlkioStats.IoServicedRecursive, stats.BlkioStats.IoServicedRecursive)
+		})
+	}
+
+	_, err := taskMetricsToStatsJSON(&ctypes.Metric{ID: "my-container", Data: &gogotypes.Any{TypeUrl: "io.containerd.cgroups.v1.PidsStat"}})
+	require.Error(t, err)
+	assert.Equal(t, "unsupported metrics type io.containerd.cgroups.v1.PidsStat of task my-container", err.Error())
+}
+
+func TestReadProcStat(t *testing.T) {
+	systemUsage, onlineCPUs, err := readProcStat(path.Join(".", "testdata", "proc_stat"))
+	require.NoError(t, err)
+	// The sum of the user, nice, system, idle, iowait, irq and softirq clock ticks
+	assert.Equal(t, uint64(603779290000000), systemUsage)
+	assert.Equal(t, uint32(4), onlineCPUs)
+
+	_, _, err = readProcStat(path.Join(".", "testdata", "stats.json"))
+	require.Error(t, err)
+}
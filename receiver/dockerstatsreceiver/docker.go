@@ -25,14 +25,15 @@ import (
 
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	dtypes "github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	devents "github.com/docker/docker/api/types/events"
 	dfilters "github.com/docker/docker/api/types/filters"
 	docker "github.com/docker/docker/client"
 	"go.uber.org/zap"
 )
 
 const (
-	dockerAPIVersion = "v1.22"
-	userAgent        = "OpenTelemetry-Collector Docker Stats Receiver/v0.0.1"
+	userAgent = "OpenTelemetry-Collector Docker Stats Receiver/v0.0.1"
 )
 
 // dockerClient provides the core metric gathering functionality from the Docker Daemon.
@@ -43,15 +44,18 @@ type dockerClient struct {
 	client               *docker.Client
 	config               *Config
 	containers           map[string]DockerContainer
+	states               map[string]DockerContainer
 	containersLock       sync.Mutex
 	excludedImageMatcher *StringMatcher
 	logger               *zap.Logger
 }
 
 func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error) {
+	// The API version is negotiated with the daemon on the first request, since recent
+	// Docker Engines and Podman's Docker-compatible API don't serve the oldest versions.
 	client, err := docker.NewClientWithOpts(
 		docker.WithHost(config.Endpoint),
-		docker.WithVersion(dockerAPIVersion),
+		docker.WithAPIVersionNegotiation(),
 		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
 	)
 	if err != nil {
@@ -68,6 +72,7 @@ func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error)
 		config:               config,
 		logger:               logger,
 		containers:           make(map[string]DockerContainer),
+		states:               make(map[string]DockerContainer),
 		containersLock:       sync.Mutex{},
 		excludedImageMatcher: excludedImageMatcher,
 	}
@@ -86,15 +91,33 @@ func (dc *dockerClient) Containers() []DockerContainer {
 	return containers
 }
 
+// Provides a slice of all the DockerContainers, running or not, to use for
+// ContainerStateToMetrics calls when container state metrics are provided.
+func (dc *dockerClient) ContainerStates() []DockerContainer {
+	dc.containersLock.Lock()
+	defer dc.containersLock.Unlock()
+	containers := make([]DockerContainer, 0, len(dc.states))
+	for _, container := range dc.states {
+		containers = append(containers, container)
+	}
+	return containers
+}
+
 // LoadContainerList will load the initial running container maps for
 // inspection and establishing which containers warrant stat gathering calls
-// by the receiver.
+// by the receiver.  All the containers are loaded when container state
+// metrics are provided.
 func (dc *dockerClient) LoadContainerList(ctx context.Context) error {
 	// Build initial container maps before starting loop
-	filters := dfilters.NewArgs()
-	filters.Add("status", "running")
 	options := dtypes.ContainerListOptions{
-		Filters: filters,
+		All: true,
+	}
+	if !dc.config.ProvideContainerStateMetrics {
+		filters := dfilters.NewArgs()
+		filters.Add("status", "running")
+		options = dtypes.ContainerListOptions{
+			Filters: filters,
+		}
 	}
 
 	listCtx, cancel := context.WithTimeout(ctx, dc.config.Timeout)
@@ -204,7 +227,31 @@ func (dc *dockerClient) ContainerEventLoop(ctx context.Context) {
 		{Key: "event", Value: "start"},
 		{Key: "event", Value: "unpause"},
 		{Key: "event", Value: "update"},
+		{Key: "event", Value: "health_status"},
 	}...)
+
+	dc.WatchEvents(ctx, filters, func(event devents.Message) {
+		switch event.Action {
+		case "destroy":
+			dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
+			dc.removeContainer(event.ID)
+		default:
+			dc.logger.Debug(
+				"Docker container update:",
+				zap.String("id", event.ID),
+				zap.String("action", event.Action),
+			)
+
+			if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
+				dc.persistContainer(container)
+			}
+		}
+	})
+}
+
+// WatchEvents calls handle with every event of the Docker Daemon matching the filters,
+// resuming the event stream after errors, until the context is done.
+func (dc *dockerClient) WatchEvents(ctx context.Context, filters dfilters.Args, handle func(devents.Message)) {
 	lastTime := time.Now()
 
 EVENT_LOOP:
@@ -220,21 +267,7 @@ EVENT_LOOP:
 			case <-ctx.Done():
 				return
 			case event := <-eventCh:
-				switch event.Action {
-				case "destroy":
-					dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
-					dc.removeContainer(event.ID)
-				default:
-					dc.logger.Debug(
-						"Docker container update:",
-						zap.String("id", event.ID),
-						zap.String("action", event.Action),
-					)
-
-					if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
-						dc.persistContainer(container)
-					}
-				}
+				handle(event)
 
 				if event.TimeNano > lastTime.UnixNano() {
 					lastTime = time.Unix(0, event.TimeNano)
@@ -272,7 +305,19 @@ func (dc *dockerClient) inspectedContainerIsOfInterest(ctx context.Context, cid
 			zap.String("id", cid),
 			zap.Error(err),
 		)
-	} else if !dc.shouldBeExcluded(container.Config.Image) {
+		return nil, false
+	}
+
+	// Docker-compatible APIs may omit the sections that aren't implemented by their runtime
+	if container.ContainerJSONBase == nil || container.State == nil {
+		dc.logger.Debug("Incomplete container inspection.  Will not monitor.", zap.String("id", cid))
+		return nil, false
+	}
+	if container.Config == nil {
+		container.Config = &dcontainer.Config{}
+	}
+
+	if !dc.shouldBeExcluded(container.Config.Image) {
 		return &container, true
 	}
 	return nil, false
@@ -284,28 +329,40 @@ func (dc *dockerClient) persistContainer(containerJSON *dtypes.ContainerJSON) {
 	}
 
 	cid := containerJSON.ID
+	container := DockerContainer{
+		ContainerJSON: containerJSON,
+		EnvMap:        containerEnvToMap(containerJSON.Config.Env),
+	}
+
+	dc.containersLock.Lock()
+	defer dc.containersLock.Unlock()
+	if dc.config.ProvideContainerStateMetrics {
+		dc.states[cid] = container
+	}
+
 	if !containerJSON.State.Running || containerJSON.State.Paused {
 		dc.logger.Debug("Docker container not running.  Will not persist.", zap.String("id", cid))
-		dc.removeContainer(cid)
+		delete(dc.containers, cid)
 		return
 	}
 
 	dc.logger.Debug("Monitoring Docker container", zap.String("id", cid))
-	dc.containersLock.Lock()
-	defer dc.containersLock.Unlock()
-	dc.containers[cid] = DockerContainer{
-		ContainerJSON: containerJSON,
-		EnvMap:        containerEnvToMap(containerJSON.Config.Env),
-	}
+	dc.containers[cid] = container
 }
 
 func (dc *dockerClient) removeContainer(cid string) {
 	dc.containersLock.Lock()
 	defer dc.containersLock.Unlock()
 	delete(dc.containers, cid)
+	delete(dc.states, cid)
 	dc.logger.Debug("Removed container from stores.", zap.String("id", cid))
 }
 
+// Close closes the connections to the Docker Daemon.
+func (dc *dockerClient) Close() error {
+	return dc.client.Close()
+}
+
 func (dc *dockerClient) shouldBeExcluded(image string) bool {
 	return dc.excludedImageMatcher != nil && dc.excludedImageMatcher.Matches(image)
 }
@@ -0,0 +1,179 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	devents "github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+var _ component.LogsReceiver = (*EventsReceiver)(nil)
+
+// EventsReceiver emits the lifecycle events of the containers from the Docker
+// Daemon events stream as log records.
+type EventsReceiver struct {
+	config       *Config
+	logger       *zap.Logger
+	nextConsumer consumer.Logs
+	client       *dockerClient
+	obsCtx       context.Context
+	cancel       context.CancelFunc
+	transport    string
+	obsrecv      *obsreport.Receiver
+}
+
+func NewEventsReceiver(
+	_ context.Context,
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("config.Endpoint must be specified")
+	}
+	if config.Runtime == containerdRuntime {
+		return nil, errors.New("container events aren't supported by the containerd runtime")
+	}
+
+	parsed, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine receiver transport: %w", err)
+	}
+
+	receiver := EventsReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+		logger:       logger,
+		transport:    parsed.Scheme,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: config.ID(), Transport: parsed.Scheme}),
+	}
+
+	return &receiver, nil
+}
+
+func (r *EventsReceiver) Start(ctx context.Context, _ component.Host) error {
+	var err error
+	r.client, err = newDockerClient(r.config, r.logger)
+	if err != nil {
+		return err
+	}
+
+	r.obsCtx = obsreport.ReceiverContext(ctx, r.config.ID(), r.transport)
+
+	var eventsCtx context.Context
+	eventsCtx, r.cancel = context.WithCancel(context.Background())
+	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "oom"},
+		{Key: "event", Value: "kill"},
+		{Key: "event", Value: "health_status"},
+	}...)
+	go r.client.WatchEvents(eventsCtx, filters, func(event devents.Message) {
+		r.consumeEvent(eventsCtx, event)
+	})
+
+	return nil
+}
+
+func (r *EventsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *EventsReceiver) consumeEvent(ctx context.Context, event devents.Message) {
+	image := event.Actor.Attributes["image"]
+	if r.client.shouldBeExcluded(image) {
+		return
+	}
+
+	c := r.obsrecv.StartLogsReceiveOp(r.obsCtx)
+	err := r.nextConsumer.ConsumeLogs(ctx, EventToLogs(event, r.config))
+	r.obsrecv.EndLogsReceiveOp(c, typeStr, 1, err)
+}
+
+// EventToLogs converts a container event to a log record whose body is the event action
+// (e.g. "die" or "health_status"), with the container as resource.
+func EventToLogs(event devents.Message, config *Config) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+
+	resource := rl.Resource().Attributes()
+	resource.InsertString(conventions.AttributeContainerID, event.Actor.ID)
+	resource.InsertString(conventions.AttributeContainerImage, event.Actor.Attributes["image"])
+	resource.InsertString(conventions.AttributeContainerName, event.Actor.Attributes["name"])
+	// Container labels are part of the event attributes
+	for k, label := range config.ContainerLabelsToMetricLabels {
+		if v := event.Actor.Attributes[k]; v != "" {
+			resource.UpsertString(label, v)
+		}
+	}
+
+	lr := rl.InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetName(fmt.Sprintf("%s%s", metricPrefix, "event"))
+	if event.TimeNano != 0 {
+		lr.SetTimestamp(pdata.TimestampFromTime(time.Unix(0, event.TimeNano)))
+	} else {
+		lr.SetTimestamp(pdata.TimestampFromTime(time.Unix(event.Time, 0)))
+	}
+
+	// The action of health status events includes the status, e.g. "health_status: healthy"
+	action := event.Action
+	severity := pdata.SeverityNumberINFO
+	attributes := lr.Attributes()
+	if strings.HasPrefix(action, "health_status:") {
+		status := strings.TrimSpace(strings.TrimPrefix(action, "health_status:"))
+		action = "health_status"
+		attributes.InsertString("health_status", status)
+		if status == "unhealthy" {
+			severity = pdata.SeverityNumberWARN
+		}
+	}
+	attributes.InsertString("action", action)
+
+	switch action {
+	case "die":
+		exitCode := event.Actor.Attributes["exitCode"]
+		attributes.InsertString("exit_code", exitCode)
+		if exitCode != "0" {
+			severity = pdata.SeverityNumberWARN
+		}
+	case "kill":
+		attributes.InsertString("signal", event.Actor.Attributes["signal"])
+	case "oom":
+		severity = pdata.SeverityNumberERROR
+	}
+
+	lr.SetSeverityNumber(severity)
+	lr.SetSeverityText(strings.TrimPrefix(severity.String(), "SEVERITY_NUMBER_"))
+	lr.Body().SetStringVal(action)
+
+	return ld
+}
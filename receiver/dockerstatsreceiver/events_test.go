@@ -0,0 +1,212 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+// TODO review if tests should succeed on Windows
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// fakeDocker serves the containers, their stats and the events of a Docker Daemon.
+type fakeDocker struct {
+	containers map[string]*dtypes.ContainerJSON
+	events     []devents.Message
+}
+
+func (fd *fakeDocker) start(t *testing.T) *httptest.Server {
+	stats, err := ioutil.ReadFile(path.Join(".", "testdata", "stats.json"))
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case len(parts) == 2 && parts[1] == "events":
+			fd.streamEvents(t, w, r)
+		case len(parts) == 3 && parts[1] == "containers" && parts[2] == "json":
+			var list []dtypes.Container
+			for _, c := range fd.containers {
+				if r.URL.Query().Get("all") != "1" && !c.State.Running {
+					continue
+				}
+				list = append(list, dtypes.Container{ID: c.ID, Image: c.Config.Image, State: c.State.Status})
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(list))
+		case len(parts) == 4 && parts[1] == "containers":
+			c, ok := fd.containers[parts[2]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			switch parts[3] {
+			case "json":
+				assert.NoError(t, json.NewEncoder(w).Encode(c))
+			case "stats":
+				_, err := w.Write(stats)
+				assert.NoError(t, err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// streamEvents writes the events and keeps the stream open, like the Docker Daemon does.
+func (fd *fakeDocker) streamEvents(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	for _, event := range fd.events {
+		assert.NoError(t, json.NewEncoder(w).Encode(event))
+	}
+	w.(http.Flusher).Flush()
+	<-r.Context().Done()
+}
+
+func TestEventsReceiver(t *testing.T) {
+	now := time.Now()
+	fd := &fakeDocker{
+		events: []devents.Message{
+			{
+				Type: "container", Action: "oom", TimeNano: now.UnixNano(),
+				Actor: devents.Actor{ID: "a2596076ca04", Attributes: map[string]string{"image": "myImage", "name": "my-container-name"}},
+			},
+			{
+				Type: "container", Action: "kill", TimeNano: now.UnixNano(),
+				Actor: devents.Actor{ID: "a2596076ca04", Attributes: map[string]string{"image": "myImage", "name": "my-container-name", "signal": "9"}},
+			},
+			{
+				Type: "container", Action: "die", TimeNano: now.UnixNano(),
+				Actor: devents.Actor{ID: "a2596076ca04", Attributes: map[string]string{"image": "myImage", "name": "my-container-name", "exitCode": "137", "my.specified.docker.label": "my_specified_docker_label_value"}},
+			},
+			{
+				Type: "container", Action: "health_status: unhealthy", TimeNano: now.UnixNano(),
+				Actor: devents.Actor{ID: "a2596076ca04", Attributes: map[string]string{"image": "myImage", "name": "my-container-name"}},
+			},
+			{
+				Type: "container", Action: "die", TimeNano: now.UnixNano(),
+				Actor: devents.Actor{ID: "b6f6e3a4b2c1", Attributes: map[string]string{"image": "undesired-container", "name": "excluded", "exitCode": "0"}},
+			},
+		},
+	}
+	srv := fd.start(t)
+	defer srv.Close()
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Endpoint = srv.URL
+	config.ExcludedImages = []string{"undesired-container"}
+	config.ContainerLabelsToMetricLabels = map[string]string{"my.specified.docker.label": "my_label"}
+
+	sink := new(consumertest.LogsSink)
+	receiver, err := NewFactory().CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, config, sink)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, receiver.Shutdown(context.Background())) }()
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() >= 4
+	}, 5*time.Second, 10*time.Millisecond)
+	// the event of the excluded image is ignored
+	assert.Equal(t, 4, sink.LogRecordsCount())
+
+	var records []pdata.LogRecord
+	var resources []pdata.Resource
+	for _, ld := range sink.AllLogs() {
+		rl := ld.ResourceLogs().At(0)
+		resources = append(resources, rl.Resource())
+		records = append(records, rl.InstrumentationLibraryLogs().At(0).Logs().At(0))
+	}
+
+	assert.Equal(t, 4, resources[2].Attributes().Len())
+	assertAttribute(t, resources[2].Attributes(), "container.id", "a2596076ca04")
+	assertAttribute(t, resources[2].Attributes(), "container.image.name", "myImage")
+	assertAttribute(t, resources[2].Attributes(), "container.name", "my-container-name")
+	assertAttribute(t, resources[2].Attributes(), "my_label", "my_specified_docker_label_value")
+
+	oom := records[0]
+	assert.Equal(t, "container.event", oom.Name())
+	assert.Equal(t, "oom", oom.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberERROR, oom.SeverityNumber())
+	assert.Equal(t, "ERROR", oom.SeverityText())
+	assert.Equal(t, pdata.TimestampFromTime(now), oom.Timestamp())
+
+	kill := records[1]
+	assert.Equal(t, "kill", kill.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO, kill.SeverityNumber())
+	assertAttribute(t, kill.Attributes(), "signal", "9")
+
+	die := records[2]
+	assert.Equal(t, "die", die.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, die.SeverityNumber())
+	assertAttribute(t, die.Attributes(), "action", "die")
+	assertAttribute(t, die.Attributes(), "exit_code", "137")
+
+	health := records[3]
+	assert.Equal(t, "health_status", health.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, health.SeverityNumber())
+	assertAttribute(t, health.Attributes(), "health_status", "unhealthy")
+}
+
+func TestEventToLogs(t *testing.T) {
+	ld := EventToLogs(devents.Message{
+		Type: "container", Action: "health_status: healthy", Time: 1600000000,
+		Actor: devents.Actor{ID: "a2596076ca04", Attributes: map[string]string{"image": "myImage", "name": "my-container-name"}},
+	}, &Config{})
+
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "health_status", lr.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+	assert.Equal(t, pdata.TimestampFromTime(time.Unix(1600000000, 0)), lr.Timestamp())
+	assertAttribute(t, lr.Attributes(), "health_status", "healthy")
+	assertAttribute(t, lr.Attributes(), "action", "health_status")
+}
+
+func TestEventsReceiverErrors(t *testing.T) {
+	_, err := NewEventsReceiver(context.Background(), zap.NewNop(), &Config{}, consumertest.NewNop())
+	require.Error(t, err)
+	assert.Equal(t, "config.Endpoint must be specified", err.Error())
+
+	_, err = NewEventsReceiver(context.Background(), zap.NewNop(), &Config{Endpoint: "unix:///run/containerd/containerd.sock", Runtime: containerdRuntime}, consumertest.NewNop())
+	require.Error(t, err)
+	assert.Equal(t, "container events aren't supported by the containerd runtime", err.Error())
+
+	receiver, err := NewEventsReceiver(context.Background(), zap.NewNop(), &Config{Endpoint: "..not/a/valid/endpoint"}, consumertest.NewNop())
+	require.NoError(t, err)
+	require.Error(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func assertAttribute(t *testing.T, attributes pdata.AttributeMap, key string, value string) {
+	v, ok := attributes.Get(key)
+	require.True(t, ok, "missing attribute %s", key)
+	assert.Equal(t, value, v.StringVal())
+}
@@ -32,15 +32,18 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
 	return &Config{
-		ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
-		Endpoint:           "unix:///var/run/docker.sock",
-		CollectionInterval: 10 * time.Second,
-		Timeout:            5 * time.Second,
+		ReceiverSettings:    config.NewReceiverSettings(config.NewID(typeStr)),
+		Endpoint:            "unix:///var/run/docker.sock",
+		Runtime:             dockerRuntime,
+		ContainerdNamespace: "default",
+		CollectionInterval:  10 * time.Second,
+		Timeout:             5 * time.Second,
 	}
 }
 
@@ -59,3 +62,19 @@ func createMetricsReceiver(
 
 	return dsr, nil
 }
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	config config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	dockerConfig := config.(*Config)
+
+	der, err := NewEventsReceiver(ctx, params.Logger, dockerConfig, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	return der, nil
+}
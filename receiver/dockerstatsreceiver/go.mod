@@ -4,14 +4,19 @@ go 1.15
 
 require (
 	github.com/census-instrumentation/opencensus-proto v0.3.0
+	github.com/containerd/cgroups v1.0.1
+	github.com/containerd/containerd v1.5.2
+	github.com/containerd/typeurl v1.0.2
 	github.com/docker/docker v20.10.6+incompatible
 	github.com/gobwas/glob v0.2.3
+	github.com/gogo/protobuf v1.3.2
 	github.com/golang/protobuf v1.5.2
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.7.0
 	go.opentelemetry.io/collector v0.27.1-0.20210524201935-86ea0a131fb2
 	go.uber.org/zap v1.16.0
+	google.golang.org/grpc v1.38.0
 	google.golang.org/protobuf v1.26.0
 )
 
@@ -192,9 +192,10 @@ func TestRemovedContainerRemovesRecordsIntegration(t *testing.T) {
 	desiredAmount := func(numDesired int) func() bool {
 		return func() bool {
 			// We need the lock to prevent data race warnings for test activity
-			r.client.containersLock.Lock()
-			defer r.client.containersLock.Unlock()
-			return len(r.client.containers) == numDesired
+			client := r.client.(*dockerClient)
+			client.containersLock.Lock()
+			defer client.containersLock.Unlock()
+			return len(client.containers) == numDesired
 		}
 	}
 
@@ -209,7 +210,7 @@ func TestRemovedContainerRemovesRecordsIntegration(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, fmt.Sprintf("Error response from daemon: No such container: %s", containers[0].ID), err.Error())
 
-	ctr, ok := r.client.inspectedContainerIsOfInterest(ctx, containers[0].ID)
+	ctr, ok := r.client.(*dockerClient).inspectedContainerIsOfInterest(ctx, containers[0].ID)
 	assert.False(t, ok)
 	assert.Nil(t, ctr)
 
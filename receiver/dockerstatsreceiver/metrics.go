@@ -59,6 +59,65 @@ func ContainerStatsToMetrics(
 		return nil, nil
 	}
 
+	return containerMetrics(metrics, container, config), nil
+}
+
+// The container states and health statuses reported by the inspect api, as
+// described in https://docs.docker.com/engine/api/v1.41/#operation/ContainerInspect
+var (
+	containerStates         = []string{"created", "running", "paused", "restarting", "removing", "exited", "dead"}
+	containerHealthStatuses = []string{"starting", "healthy", "unhealthy"}
+)
+
+// ContainerStateToMetrics converts the state of a container, running or not, to
+// lifecycle metrics: its state and health status (as 1 for the current one and 0 for
+// the others), restart count and, when it isn't running, exit code.
+func ContainerStateToMetrics(
+	container *DockerContainer,
+	config *Config,
+) *agentmetricspb.ExportMetricsServiceRequest {
+	if container.State == nil {
+		return nil
+	}
+
+	now := timestamppb.New(time.Now())
+	state := container.State
+
+	var metrics []*metricspb.Metric
+	metrics = append(metrics, statusGauge("state", "state", state.Status, containerStates, now))
+	if state.Health != nil {
+		metrics = append(metrics, statusGauge("health", "status", state.Health.Status, containerHealthStatuses, now))
+	}
+	metrics = append(metrics, Cumulative("restarts", []int64{int64(container.RestartCount)}, now, "1", nil, nil))
+
+	if !state.Running {
+		var oomKilled int64
+		if state.OOMKilled {
+			oomKilled = 1
+		}
+		metrics = append(metrics, []*metricspb.Metric{
+			Gauge("exit_code", []int64{int64(state.ExitCode)}, now, "1", nil, nil),
+			Gauge("oom_killed", []int64{oomKilled}, now, "1", nil, nil),
+		}...)
+	}
+
+	return containerMetrics(metrics, container, config)
+}
+
+// statusGauge returns a gauge with a time series per status, 1 for the current one and 0 for the others.
+func statusGauge(name string, labelKey string, current string, statuses []string, ts *timestamp.Timestamp) *metricspb.Metric {
+	vals := make([]int64, len(statuses))
+	labelValues := make([][]string, len(statuses))
+	for i, status := range statuses {
+		if status == current {
+			vals[i] = 1
+		}
+		labelValues[i] = []string{status}
+	}
+	return Gauge(name, vals, ts, "1", []string{labelKey}, labelValues)
+}
+
+func containerMetrics(metrics []*metricspb.Metric, container *DockerContainer, config *Config) *agentmetricspb.ExportMetricsServiceRequest {
 	md := &agentmetricspb.ExportMetricsServiceRequest{
 		Metrics: metrics,
 		Resource: &resourcepb.Resource{
@@ -74,7 +133,7 @@ func ContainerStatsToMetrics(
 
 	updateConfiguredResourceLabels(md, container, config)
 
-	return md, nil
+	return md
 }
 
 func updateConfiguredResourceLabels(md *agentmetricspb.ExportMetricsServiceRequest, container *DockerContainer, config *Config) {
@@ -156,7 +215,8 @@ func cpuMetrics(
 
 	metrics = append(metrics, GaugeF("cpu.percent", []float64{calculateCPUPercent(previousCPUStats, cpuStats)}, ts, "1", nil, nil))
 
-	if !providePerCoreMetrics {
+	// Per core usage isn't reported on cgroup v2 hosts
+	if !providePerCoreMetrics || len(cpuStats.CPUUsage.PercpuUsage) == 0 {
 		return metrics
 	}
 
@@ -210,6 +270,28 @@ var memoryStatsThatAreCumulative = map[string]bool{
 	"total_pgmajfault": true,
 	"total_pgpgin":     true,
 	"total_pgpgout":    true,
+	// cgroup v2 only
+	"pgactivate":             true,
+	"pgdeactivate":           true,
+	"pglazyfree":             true,
+	"pglazyfreed":            true,
+	"pgrefill":               true,
+	"pgscan":                 true,
+	"pgsteal":                true,
+	"thp_collapse_alloc":     true,
+	"thp_fault_alloc":        true,
+	"workingset_activate":    true,
+	"workingset_nodereclaim": true,
+	"workingset_refault":     true,
+}
+
+// isCgroupV2 returns whether the memory stats are from a cgroup v2 host, whose memory.stat
+// has no hierarchical "total_" stats and accounts the page cache as "file".
+// https://www.kernel.org/doc/Documentation/admin-guide/cgroup-v2.rst
+func isCgroupV2(memoryStats *dtypes.MemoryStats) bool {
+	_, v1 := memoryStats.Stats["total_inactive_file"]
+	_, v2 := memoryStats.Stats["inactive_file"]
+	return v2 && !v1
 }
 
 func memoryMetrics(
@@ -218,7 +300,14 @@ func memoryMetrics(
 ) []*metricspb.Metric {
 	var metrics []*metricspb.Metric
 
-	totalUsage := int64(memoryStats.Usage - memoryStats.Stats["total_cache"])
+	// The usage is reported without the page cache, like the docker stats command does,
+	// which is the inactive file memory on cgroup v2 hosts
+	totalCache, cache := memoryStats.Stats["total_cache"], memoryStats.Stats["cache"]
+	if isCgroupV2(memoryStats) {
+		totalCache, cache = memoryStats.Stats["inactive_file"], memoryStats.Stats["inactive_file"]
+	}
+
+	totalUsage := int64(memoryStats.Usage - totalCache)
 	metrics = append(metrics, []*metricspb.Metric{
 		Gauge("memory.usage.limit", []int64{int64(memoryStats.Limit)}, ts, "By", nil, nil),
 		Gauge("memory.usage.total", []int64{totalUsage}, ts, "By", nil, nil),
@@ -228,13 +317,14 @@ func memoryMetrics(
 	if float64(memoryStats.Limit) == 0 {
 		pctUsed = 0
 	} else {
-		pctUsed = 100.0 * (float64(memoryStats.Usage) - float64(memoryStats.Stats["cache"])) / float64(memoryStats.Limit)
+		pctUsed = 100.0 * (float64(memoryStats.Usage) - float64(cache)) / float64(memoryStats.Limit)
 	}
 
-	metrics = append(metrics, []*metricspb.Metric{
-		GaugeF("memory.percent", []float64{pctUsed}, ts, "1", nil, nil),
-		Gauge("memory.usage.max", []int64{int64(memoryStats.MaxUsage)}, ts, "By", nil, nil),
-	}...)
+	metrics = append(metrics, GaugeF("memory.percent", []float64{pctUsed}, ts, "1", nil, nil))
+	// The max usage isn't recorded on cgroup v2 hosts, nor reported by Podman
+	if memoryStats.MaxUsage != 0 {
+		metrics = append(metrics, Gauge("memory.usage.max", []int64{int64(memoryStats.MaxUsage)}, ts, "By", nil, nil))
+	}
 
 	// Sorted iteration for reproducibility, largely for testing
 	sortedNames := make([]string, 0, len(memoryStats.Stats))
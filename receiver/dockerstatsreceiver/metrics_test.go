@@ -26,6 +26,7 @@ import (
 	dtypes "github.com/docker/docker/api/types"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type Metric struct {
@@ -235,13 +236,16 @@ func TestZeroValueStats(t *testing.T) {
 		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
-		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 	}
 	assertMetricsDataEqual(t, metrics, nil, md)
 }
 
 func statsJSON(t *testing.T) *dtypes.StatsJSON {
-	statsRaw, err := ioutil.ReadFile(path.Join(".", "testdata", "stats.json"))
+	return statsJSONFromFile(t, "stats.json")
+}
+
+func statsJSONFromFile(t *testing.T, file string) *dtypes.StatsJSON {
+	statsRaw, err := ioutil.ReadFile(path.Join(".", "testdata", file))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -271,6 +275,21 @@ func containerJSON(t *testing.T) *DockerContainer {
 	}
 }
 
+// exitedContainer returns the test container after it was OOM killed, restarted
+// twice and became unhealthy.
+func exitedContainer(t *testing.T) *DockerContainer {
+	container := containerJSON(t)
+	container.ID = "b6f6e3a4b2c1"
+	container.Name = "/my-exited-container"
+	container.RestartCount = 2
+	container.State.Status = "exited"
+	container.State.Running = false
+	container.State.ExitCode = 137
+	container.State.OOMKilled = true
+	container.State.Health = &dtypes.Health{Status: "unhealthy"}
+	return container
+}
+
 func TestStatsToDefaultMetrics(t *testing.T) {
 	stats := statsJSON(t)
 	containers := containerJSON(t)
@@ -380,6 +399,112 @@ func TestStatsToAllMetrics(t *testing.T) {
 	assertMetricsDataEqual(t, metrics, nil, md)
 }
 
+func TestCgroupV2StatsToMetrics(t *testing.T) {
+	stats := statsJSONFromFile(t, "stats_cgroupv2.json")
+	containers := containerJSON(t)
+	// per core usage isn't reported on cgroup v2 hosts
+	config := &Config{
+		ProvidePerCoreCPUMetrics: true,
+	}
+
+	md, err := ContainerStatsToMetrics(stats, containers, config)
+	assert.Nil(t, err)
+	assert.NotNil(t, md)
+
+	metrics := []Metric{
+		{name: "container.blockio.io_service_bytes_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 2183168}}},
+		{name: "container.blockio.io_service_bytes_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 8192}}},
+		{name: "container.blockio.io_serviced_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 53}}},
+		{name: "container.blockio.io_serviced_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 2}}},
+		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 1215862930000000}}},
+		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 43620018}}},
+		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 11497000}}},
+		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 32123000}}},
+		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 10}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 2}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 3100000}}},
+		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.22100000000000003}}},
+		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 16652890112}}},
+		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 8589312}}},
+		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.05157850644682138}}},
+		{name: "container.memory.active_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 4096}}},
+		{name: "container.memory.active_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 2297856}}},
+		{name: "container.memory.anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 5652480}}},
+		{name: "container.memory.anon_thp", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 4595712}}},
+		{name: "container.memory.file_dirty", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.file_mapped", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 3698688}}},
+		{name: "container.memory.file_writeback", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.inactive_anon", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 5648384}}},
+		{name: "container.memory.inactive_file", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 2297856}}},
+		{name: "container.memory.kernel_stack", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 49152}}},
+		{name: "container.memory.pgactivate", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 561}}},
+		{name: "container.memory.pgdeactivate", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.pgfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 2805}}},
+		{name: "container.memory.pglazyfree", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.pglazyfreed", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.pgmajfault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 35}}},
+		{name: "container.memory.pgrefill", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.pgscan", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.pgsteal", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.shmem", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.slab", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 485520}}},
+		{name: "container.memory.slab_reclaimable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 309048}}},
+		{name: "container.memory.slab_unreclaimable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 176472}}},
+		{name: "container.memory.sock", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.thp_collapse_alloc", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.thp_fault_alloc", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.unevictable", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.workingset_activate", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.workingset_nodereclaim", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.workingset_refault", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.network.io.usage.rx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 1538}}},
+		{name: "container.network.io.usage.tx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 19}}},
+		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+	}
+	assertMetricsDataEqual(t, metrics, nil, md)
+}
+
+func TestPodmanStatsToMetrics(t *testing.T) {
+	stats := statsJSONFromFile(t, "stats_podman.json")
+	containers := containerJSON(t)
+	config := &Config{}
+
+	md, err := ContainerStatsToMetrics(stats, containers, config)
+	assert.Nil(t, err)
+	assert.NotNil(t, md)
+
+	metrics := []Metric{
+		{name: "container.blockio.io_service_bytes_recursive.read", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 1015808}}},
+		{name: "container.blockio.io_service_bytes_recursive.write", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"device_major", "device_minor"}, values: []Value{{labelValues: []string{"259", "0"}, value: 0}}},
+		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 8245500000000}}},
+		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 25316000}}},
+		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 7909000}}},
+		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 17407000}}},
+		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.1}}},
+		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 16652890112}}},
+		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 2539520}}},
+		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0.015249725320471749}}},
+		{name: "container.network.io.usage.rx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 2048}}},
+		{name: "container.network.io.usage.tx_bytes", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "By", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 1032}}},
+		{name: "container.network.io.usage.rx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.rx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 24}}},
+		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
+		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 12}}},
+	}
+	assertMetricsDataEqual(t, metrics, nil, md)
+}
+
 func TestEnvVarToMetricLabels(t *testing.T) {
 	stats := statsJSON(t)
 	containers := containerJSON(t)
@@ -423,3 +548,58 @@ func TestContainerLabelToMetricLabels(t *testing.T) {
 
 	assertMetricsDataEqual(t, defaultMetrics(), expectedLabels, md)
 }
+
+func TestRunningContainerStateToMetrics(t *testing.T) {
+	md := ContainerStateToMetrics(containerJSON(t), &Config{})
+	require.NotNil(t, md)
+
+	metrics := []Metric{
+		{name: "container.state", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: []string{"state"}, values: []Value{
+			{labelValues: []string{"created"}, value: 0},
+			{labelValues: []string{"running"}, value: 1},
+			{labelValues: []string{"paused"}, value: 0},
+			{labelValues: []string{"restarting"}, value: 0},
+			{labelValues: []string{"removing"}, value: 0},
+			{labelValues: []string{"exited"}, value: 0},
+			{labelValues: []string{"dead"}, value: 0},
+		}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+	}
+	assertMetricsDataEqual(t, metrics, nil, md)
+}
+
+func TestExitedContainerStateToMetrics(t *testing.T) {
+	config := &Config{
+		ContainerLabelsToMetricLabels: map[string]string{
+			"my.specified.docker.label": "my.docker.to.metric.label",
+		},
+	}
+	md := ContainerStateToMetrics(exitedContainer(t), config)
+	require.NotNil(t, md)
+
+	metrics := []Metric{
+		{name: "container.state", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: []string{"state"}, values: []Value{
+			{labelValues: []string{"created"}, value: 0},
+			{labelValues: []string{"running"}, value: 0},
+			{labelValues: []string{"paused"}, value: 0},
+			{labelValues: []string{"restarting"}, value: 0},
+			{labelValues: []string{"removing"}, value: 0},
+			{labelValues: []string{"exited"}, value: 1},
+			{labelValues: []string{"dead"}, value: 0},
+		}},
+		{name: "container.health", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: []string{"status"}, values: []Value{
+			{labelValues: []string{"starting"}, value: 0},
+			{labelValues: []string{"healthy"}, value: 0},
+			{labelValues: []string{"unhealthy"}, value: 1},
+		}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 2}}},
+		{name: "container.exit_code", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 137}}},
+		{name: "container.oom_killed", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 1}}},
+	}
+	expectedLabels := map[string]string{
+		"container.id":              "b6f6e3a4b2c1",
+		"container.name":            "my-exited-container",
+		"my.docker.to.metric.label": "my_specified_docker_label_value",
+	}
+	assertMetricsDataEqual(t, metrics, expectedLabels, md)
+}
@@ -33,11 +33,30 @@ import (
 var _ component.MetricsReceiver = (*Receiver)(nil)
 var _ interval.Runnable = (*Receiver)(nil)
 
+// containerClient lists the containers of a container runtime and fetches
+// their stats.
+type containerClient interface {
+	LoadContainerList(ctx context.Context) error
+	ContainerEventLoop(ctx context.Context)
+	Containers() []DockerContainer
+	ContainerStates() []DockerContainer
+	FetchContainerStatsAndConvertToMetrics(ctx context.Context, container DockerContainer) (*agentmetricspb.ExportMetricsServiceRequest, error)
+	Close() error
+}
+
+// newContainerClient creates the client of the configured container runtime.
+func newContainerClient(config *Config, logger *zap.Logger) (containerClient, error) {
+	if config.Runtime == containerdRuntime {
+		return newContainerdClient(config, logger)
+	}
+	return newDockerClient(config, logger)
+}
+
 type Receiver struct {
 	config            *Config
 	logger            *zap.Logger
 	nextConsumer      consumer.Metrics
-	client            *dockerClient
+	client            containerClient
 	runner            *interval.Runner
 	obsCtx            context.Context
 	runnerCtx         context.Context
@@ -76,7 +95,7 @@ func NewReceiver(
 
 func (r *Receiver) Start(ctx context.Context, host component.Host) error {
 	var err error
-	r.client, err = newDockerClient(r.config, r.logger)
+	r.client, err = newContainerClient(r.config, r.logger)
 	if err != nil {
 		return err
 	}
@@ -98,7 +117,7 @@ func (r *Receiver) Start(ctx context.Context, host component.Host) error {
 func (r *Receiver) Shutdown(ctx context.Context) error {
 	r.runnerCancel()
 	r.runner.Stop()
-	return nil
+	return r.client.Close()
 }
 
 func (r *Receiver) Setup() error {
@@ -158,6 +177,22 @@ func (r *Receiver) Run() error {
 		}
 	}
 
+	if r.config.ProvideContainerStateMetrics {
+		containers := r.client.ContainerStates()
+		for i := range containers {
+			state := ContainerStateToMetrics(&containers[i], r.config)
+			if state == nil {
+				continue
+			}
+			md := internaldata.OCToMetrics(state.Node, state.Resource, state.Metrics)
+			_, np := md.MetricAndDataPointCount()
+			numPoints += np
+			if err := r.nextConsumer.ConsumeMetrics(r.runnerCtx, md); err != nil {
+				lastErr = err
+			}
+		}
+	}
+
 	r.obsrecv.EndMetricsReceiveOp(c, typeStr, numPoints, lastErr)
 	return nil
 }
@@ -23,10 +23,12 @@ import (
 	"testing"
 	"time"
 
+	dtypes "github.com/docker/docker/api/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
 )
@@ -94,6 +96,70 @@ func TestErrorsInStart(t *testing.T) {
 	require.NoError(t, receiver.Shutdown(context.Background()))
 }
 
+func TestContainerStateMetrics(t *testing.T) {
+	running := containerJSON(t).ContainerJSON
+	exited := exitedContainer(t).ContainerJSON
+	fd := &fakeDocker{
+		containers: map[string]*dtypes.ContainerJSON{
+			running.ID: running,
+			exited.ID:  exited,
+		},
+	}
+	srv := fd.start(t)
+	defer srv.Close()
+
+	for _, test := range []struct {
+		name         string
+		provideState bool
+		wantStats    map[string]bool
+		wantStates   map[string]bool
+	}{
+		{
+			name:       "stats only",
+			wantStats:  map[string]bool{"my-container-name": true},
+			wantStates: map[string]bool{},
+		},
+		{
+			name:         "container state",
+			provideState: true,
+			wantStats:    map[string]bool{"my-container-name": true},
+			wantStates:   map[string]bool{"my-container-name": true, "my-exited-container": true},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			config := NewFactory().CreateDefaultConfig().(*Config)
+			config.Endpoint = srv.URL
+			config.CollectionInterval = 10 * time.Millisecond
+			config.ProvideContainerStateMetrics = test.provideState
+
+			sink := new(consumertest.MetricsSink)
+			receiver, err := NewReceiver(context.Background(), zap.NewNop(), config, sink)
+			require.NoError(t, err)
+			require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+
+			require.Eventually(t, func() bool {
+				return len(sink.AllMetrics()) >= 2*(len(test.wantStats)+len(test.wantStates))
+			}, 5*time.Second, 10*time.Millisecond)
+			require.NoError(t, receiver.Shutdown(context.Background()))
+
+			stats := map[string]bool{}
+			states := map[string]bool{}
+			for _, md := range sink.AllMetrics() {
+				rm := md.ResourceMetrics().At(0)
+				name, _ := rm.Resource().Attributes().Get("container.name")
+				first := rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name()
+				if first == "container.state" {
+					states[name.StringVal()] = true
+				} else {
+					stats[name.StringVal()] = true
+				}
+			}
+			assert.Equal(t, test.wantStats, stats)
+			assert.Equal(t, test.wantStates, states)
+		})
+	}
+}
+
 type errorWaitingHost struct {
 	component.Host
 	sync.Mutex
@@ -15,7 +15,14 @@
 package dotnetdiagnosticsreceiver
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
 )
 
 type Config struct {
@@ -28,14 +35,39 @@ type Config struct {
 	// process ID is used to generate the file glob "dotnet-diagnostic-%d-*-socket"
 	// to locate a file in TMPDIR (or "/tmp" if unset). If the file is found, it is
 	// used as a Unix domain socket (on Linux/Mac) to communicate with the dotnet
-	// process. For ease of use, this receiver is intended to be used with an
-	// observer and receiver creator for process discovery and receiver creation.
+	// process. If the process ID is not set, diagnostics are collected from all
+	// the dotnet processes whose socket file is found in TMPDIR, optionally
+	// filtered with ProcessName and CommandLine.
 	PID int `mapstructure:"pid"`
+	// ProcessName is an optional regular expression that the executable name of
+	// the dotnet processes must match for their diagnostics to be collected. The
+	// executable name is "dotnet" for the apps run with the dotnet command.
+	// Filtering the processes requires the proc filesystem (Linux only).
+	ProcessName string `mapstructure:"process_name"`
+	// CommandLine is an optional regular expression that the command line of the
+	// dotnet processes must match for their diagnostics to be collected, the
+	// arguments of the command line are separated with spaces. Filtering the
+	// processes requires the proc filesystem (Linux only).
+	CommandLine string `mapstructure:"command_line"`
+	// DiscoveryInterval is the interval at which TMPDIR is scanned for new dotnet
+	// processes, and at which the connections to the processes that restarted
+	// are reestablished. Defaults to 10 seconds.
+	DiscoveryInterval time.Duration `mapstructure:"discovery_interval"`
+	// ConnectTimeout bounds the handshake with a dotnet process, so that an
+	// unresponsive process or a stale socket file is retried on the next
+	// discovery. Defaults to 5 seconds.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
 	// A list of counters for the dotnet process to send to the collector. Defaults
 	// to ["System.Runtime", "Microsoft.AspNetCore.Hosting"]. Available counters can
 	// be displayed by the `dotnet-counters` tool:
 	// https://docs.microsoft.com/en-us/dotnet/core/diagnostics/dotnet-counters
 	Counters []string `mapstructure:"counters"`
+	// RuntimeEvents is an optional list of groups of .NET runtime events to request
+	// from the dotnet process: "gc" for the durations of the garbage collections
+	// and of their pauses, "threadpool" for the number of worker threads of the
+	// thread pool, and "exceptions" for the exceptions thrown, sent as logs. The
+	// metrics aggregated from the events are sent along with the counters.
+	RuntimeEvents []string `mapstructure:"runtime_events"`
 
 	// LocalDebugDir takes an optional directory name where stream data can be written for
 	// offline analysis and troubleshooting. If LocalDebugDir is empty, no stream data is
@@ -49,3 +81,48 @@ type Config struct {
 	// number of files in LocalDebugDir at the specified maximum.
 	MaxLocalDebugFiles int `mapstructure:"max_local_debug_files"`
 }
+
+// Validate checks the process ID, discovery interval, connect timeout and
+// runtime events, and that the process filters are valid regular expressions.
+func (cfg *Config) Validate() error {
+	if cfg.PID < 0 {
+		return fmt.Errorf("invalid pid %d", cfg.PID)
+	}
+	if cfg.DiscoveryInterval <= 0 {
+		return errors.New("discovery_interval must be positive")
+	}
+	if cfg.ConnectTimeout <= 0 {
+		return errors.New("connect_timeout must be positive")
+	}
+	if _, err := regexp.Compile(cfg.ProcessName); err != nil {
+		return fmt.Errorf("invalid process_name: %w", err)
+	}
+	if _, err := regexp.Compile(cfg.CommandLine); err != nil {
+		return fmt.Errorf("invalid command_line: %w", err)
+	}
+	if _, err := runtimeKeywords(cfg.RuntimeEvents); err != nil {
+		return err
+	}
+	return nil
+}
+
+// runtimeEventKeywords are the keywords of the groups of runtime events.
+var runtimeEventKeywords = map[string]dotnet.RuntimeKeywords{
+	"gc":         dotnet.RuntimeKeywordGC,
+	"exceptions": dotnet.RuntimeKeywordException,
+	"threadpool": dotnet.RuntimeKeywordThreading,
+}
+
+// runtimeKeywords returns the keywords of the passed in groups of runtime
+// events.
+func runtimeKeywords(names []string) (dotnet.RuntimeKeywords, error) {
+	var keywords dotnet.RuntimeKeywords
+	for _, name := range names {
+		k, ok := runtimeEventKeywords[name]
+		if !ok {
+			return 0, fmt.Errorf("invalid runtime_events %q, must be one of gc, exceptions and threadpool", name)
+		}
+		keywords |= k
+	}
+	return keywords, nil
+}
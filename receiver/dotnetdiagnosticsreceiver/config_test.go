@@ -42,4 +42,55 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, 1234, cfg.PID)
 	assert.Equal(t, 2*time.Second, cfg.CollectionInterval)
 	assert.Equal(t, []string{"Foo", "Bar"}, cfg.Counters)
+	assert.Equal(t, 10*time.Second, cfg.DiscoveryInterval)
+	assert.Equal(t, 5*time.Second, cfg.ConnectTimeout)
+
+	cfg = collectorCfg.Receivers[config.NewIDWithName(typeStr, "discovery")].(*Config)
+	assert.Equal(t, 0, cfg.PID)
+	assert.Equal(t, "^dotnet$", cfg.ProcessName)
+	assert.Equal(t, `MyApp\.dll`, cfg.CommandLine)
+	assert.Equal(t, 30*time.Second, cfg.DiscoveryInterval)
+	assert.Equal(t, 2*time.Second, cfg.ConnectTimeout)
+	assert.Equal(t, []string{"gc", "exceptions"}, cfg.RuntimeEvents)
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid := func() *Config {
+		return createDefaultConfig().(*Config)
+	}
+	require.NoError(t, valid().Validate())
+
+	cfg := valid()
+	cfg.PID = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = valid()
+	cfg.DiscoveryInterval = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = valid()
+	cfg.ConnectTimeout = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = valid()
+	cfg.ProcessName = "("
+	assert.Error(t, cfg.Validate())
+
+	cfg = valid()
+	cfg.CommandLine = "["
+	assert.Error(t, cfg.Validate())
+
+	cfg = valid()
+	cfg.RuntimeEvents = []string{"gc", "jit"}
+	assert.EqualError(t, cfg.Validate(), `invalid runtime_events "jit", must be one of gc, exceptions and threadpool`)
+}
+
+func TestRuntimeKeywords(t *testing.T) {
+	keywords, err := runtimeKeywords([]string{"gc", "exceptions", "threadpool"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 0x18001, keywords)
+
+	keywords, err = runtimeKeywords(nil)
+	require.NoError(t, err)
+	assert.Zero(t, keywords)
 }
@@ -0,0 +1,300 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnetdiagnosticsreceiver
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
+)
+
+// socketDialer opens a connection to the Unix domain socket file at the given
+// path.
+type socketDialer func(path string) (io.ReadWriter, error)
+
+// processReceiver discovers the dotnet processes from their diagnostics socket
+// files and runs a receiver for each of the processes matching the config. The
+// socket files are looked up periodically, so that the processes started after
+// the receiver, or restarted, are connected to. The receiver is shared by the
+// metrics and logs pipelines of a config, so it's started and shut down once
+// whatever the number of pipelines.
+type processReceiver struct {
+	cfg             *Config
+	runtimeKeywords dotnet.RuntimeKeywords
+	logger          *zap.Logger
+
+	tempDir     string
+	glob        network.GlobFunc
+	dial        socketDialer
+	readProcess processReader
+	nameRe      *regexp.Regexp
+	cmdRe       *regexp.Regexp
+
+	host         component.Host
+	mu           sync.Mutex
+	nextConsumer consumer.Metrics
+	nextLogs     consumer.Logs
+	targets      map[int]*receiver
+	// connecting holds the processes being connected to, so that they are
+	// skipped by the following discoveries until the connection succeeds or
+	// fails.
+	connecting   map[int]bool
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	startOnce    sync.Once
+	shutdownOnce sync.Once
+}
+
+func newProcessReceiver(
+	cfg *Config,
+	logger *zap.Logger,
+	glob network.GlobFunc,
+	dial socketDialer,
+	readProcess processReader,
+) (*processReceiver, error) {
+	nameRe, err := regexp.Compile(cfg.ProcessName)
+	if err != nil {
+		return nil, err
+	}
+	cmdRe, err := regexp.Compile(cfg.CommandLine)
+	if err != nil {
+		return nil, err
+	}
+	keywords, err := runtimeKeywords(cfg.RuntimeEvents)
+	if err != nil {
+		return nil, err
+	}
+	return &processReceiver{
+		cfg:             cfg,
+		runtimeKeywords: keywords,
+		logger:          logger,
+		tempDir:         os.TempDir(),
+		glob:            glob,
+		dial:            dial,
+		readProcess:     readProcess,
+		nameRe:          nameRe,
+		cmdRe:           cmdRe,
+		targets:         map[int]*receiver{},
+		connecting:      map[int]bool{},
+	}, nil
+}
+
+// registerMetricsConsumer sets the consumer of the metrics, the receiver
+// being shared by the metrics and logs pipelines.
+func (r *processReceiver) registerMetricsConsumer(mc consumer.Metrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextConsumer = mc
+}
+
+// registerLogsConsumer sets the consumer of the exceptions.
+func (r *processReceiver) registerLogsConsumer(lc consumer.Logs) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextLogs = lc
+}
+
+func (r *processReceiver) Start(_ context.Context, host component.Host) error {
+	r.startOnce.Do(func() {
+		r.start(host)
+	})
+	return nil
+}
+
+func (r *processReceiver) start(host component.Host) {
+	r.host = host
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	r.discover(ctx)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.cfg.DiscoveryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.discover(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// discover looks up the socket files of the dotnet processes and starts a
+// receiver for the matching processes which don't have one yet. Each process
+// is connected to in its own goroutine, so that an unresponsive process
+// doesn't hold up the others.
+func (r *processReceiver) discover(ctx context.Context) {
+	sockets, err := network.Discover(r.tempDir, r.glob)
+	if err != nil {
+		r.logger.Warn("failed to look up the dotnet diagnostics sockets", zap.Error(err))
+		return
+	}
+	for pid, path := range sockets {
+		if r.cfg.PID != 0 && pid != r.cfg.PID {
+			continue
+		}
+		if r.hasTarget(pid) {
+			continue
+		}
+		info, ok := r.matchProcess(pid)
+		if !ok {
+			continue
+		}
+		r.mu.Lock()
+		r.connecting[pid] = true
+		r.mu.Unlock()
+		r.wg.Add(1)
+		go func(path string) {
+			defer r.wg.Done()
+			r.startTarget(ctx, info, path)
+		}(path)
+	}
+}
+
+// matchProcess reads the information of the process and checks it against the
+// process filters. The information of a process that can't be read, e.g. in
+// another PID namespace, is only required when filtering the processes.
+func (r *processReceiver) matchProcess(pid int) (processInfo, bool) {
+	filtered := r.cfg.ProcessName != "" || r.cfg.CommandLine != ""
+	info, err := r.readProcess(pid)
+	if err != nil {
+		if filtered {
+			r.logger.Debug("failed to read the process info", zap.Int("pid", pid), zap.Error(err))
+			return processInfo{}, false
+		}
+		return processInfo{pid: pid}, true
+	}
+	if !r.nameRe.MatchString(info.name) || !r.cmdRe.MatchString(info.commandLine) {
+		r.logger.Debug("process does not match the filters", zap.Int("pid", pid))
+		return processInfo{}, false
+	}
+	return info, true
+}
+
+// startTarget connects to the process, the handshake being bounded by
+// ConnectTimeout, and then runs its receiver until the process exits or the
+// discovery is shut down.
+func (r *processReceiver) startTarget(ctx context.Context, info processInfo, path string) {
+	r.mu.Lock()
+	mc, lc := r.nextConsumer, r.nextLogs
+	r.mu.Unlock()
+	target := newReceiver(
+		mc,
+		lc,
+		func() (io.ReadWriter, error) {
+			return r.dial(path)
+		},
+		r.cfg.Counters,
+		r.runtimeKeywords,
+		int(math.Round(r.cfg.CollectionInterval.Seconds())),
+		info.resource(),
+		r.logger,
+		r.blobWriter(info.pid),
+	)
+	// the process may not be ready to accept connections yet, or the socket
+	// file may be stale, so failures are retried on the next discovery
+	connectCtx, cancel := context.WithTimeout(ctx, r.cfg.ConnectTimeout)
+	err := target.Start(connectCtx, r.host)
+	cancel()
+
+	r.mu.Lock()
+	delete(r.connecting, info.pid)
+	if err != nil {
+		r.mu.Unlock()
+		r.logger.Debug("failed to connect to the dotnet process", zap.Int("pid", info.pid), zap.Error(err))
+		return
+	}
+	if ctx.Err() != nil {
+		// the discovery was shut down while connecting
+		r.mu.Unlock()
+		_ = target.Shutdown(context.Background())
+		return
+	}
+	r.targets[info.pid] = target
+	r.mu.Unlock()
+	r.logger.Info("collecting the metrics of the dotnet process", zap.Int("pid", info.pid))
+
+	select {
+	case <-target.done:
+		// the process exited or restarted, release the target so that it is
+		// reconnected to on the next discovery
+		r.mu.Lock()
+		delete(r.targets, info.pid)
+		r.mu.Unlock()
+	case <-ctx.Done():
+	}
+}
+
+// hasTarget reports whether the process is connected to, or being connected
+// to.
+func (r *processReceiver) hasTarget(pid int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, found := r.targets[pid]
+	return found || r.connecting[pid]
+}
+
+// blobWriter returns the BlobWriter of a process. When discovering several
+// processes, their stream data is written to a subdirectory of LocalDebugDir
+// named after their process ID.
+func (r *processReceiver) blobWriter(pid int) network.BlobWriter {
+	dir := r.cfg.LocalDebugDir
+	if dir != "" && r.cfg.PID == 0 {
+		dir = filepath.Join(dir, strconv.Itoa(pid))
+	}
+	return network.NewBlobWriter(dir, r.cfg.MaxLocalDebugFiles, r.logger)
+}
+
+// Shutdown stops the receivers of the processes and forgets the receiver, so
+// that its config may be used again.
+func (r *processReceiver) Shutdown(ctx context.Context) error {
+	r.shutdownOnce.Do(func() {
+		r.shutdown(ctx)
+		removeReceiver(r.cfg)
+	})
+	return nil
+}
+
+func (r *processReceiver) shutdown(ctx context.Context) {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for pid, target := range r.targets {
+		if err := target.Shutdown(ctx); err != nil {
+			r.logger.Warn("failed to shut down the receiver", zap.Int("pid", pid), zap.Error(err))
+		}
+		delete(r.targets, pid)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnetdiagnosticsreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
+)
+
+var testProcesses = map[int]processInfo{
+	42: {pid: 42, name: "dotnet", commandLine: "dotnet MyApp.dll"},
+	43: {pid: 43, name: "dotnet", commandLine: "dotnet Other.dll"},
+	44: {pid: 44, name: "MyApp", commandLine: "./MyApp --urls http://*:5000"},
+}
+
+func TestProcessReceiver(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	d := newFakeDialer(t)
+	r := newTestProcessReceiver(t, &Config{}, sink, d)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	assert.ElementsMatch(t, []int{42, 43, 44}, d.waitDialed(3))
+	require.Eventually(t, func() bool {
+		return len(resourcePIDs(sink)) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	attrs := findResource(t, sink, 44).Attributes()
+	name, _ := attrs.Get(conventions.AttributeProcessExecutableName)
+	assert.Equal(t, "MyApp", name.StringVal())
+	cmd, _ := attrs.Get(conventions.AttributeProcessCommandLine)
+	assert.Equal(t, "./MyApp --urls http://*:5000", cmd.StringVal())
+}
+
+func TestProcessReceiver_Filters(t *testing.T) {
+	d := newFakeDialer(t)
+	cfg := &Config{ProcessName: "^dotnet$", CommandLine: `MyApp\.dll`}
+	r := newTestProcessReceiver(t, cfg, consumertest.NewNop(), d)
+	r.readProcess = func(pid int) (processInfo, error) {
+		if pid == 44 {
+			return processInfo{}, errors.New("no such process")
+		}
+		return testProcesses[pid], nil
+	}
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, []int{42}, d.waitDialed(1))
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []int{42}, d.dialedPIDs())
+}
+
+func TestProcessReceiver_PID(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	d := newFakeDialer(t)
+	r := newTestProcessReceiver(t, &Config{PID: 43}, sink, d)
+	// without filters, the metrics are collected even if the process info can't
+	// be read
+	r.readProcess = func(int) (processInfo, error) {
+		return processInfo{}, errors.New("no such process")
+	}
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	assert.Equal(t, []int{43}, d.waitDialed(1))
+	require.Eventually(t, func() bool {
+		return len(resourcePIDs(sink)) == 1
+	}, time.Second, 10*time.Millisecond)
+	rm := findResource(t, sink, 43)
+	assert.Equal(t, 1, rm.Attributes().Len())
+}
+
+func TestProcessReceiver_Reconnect(t *testing.T) {
+	d := newFakeDialer(t)
+	attempts := 0
+	d.newConn = func(pid int) (io.ReadWriter, error) {
+		attempts++
+		switch attempts {
+		case 1:
+			return nil, errors.New("connection refused")
+		case 2:
+			// the process exits after sending its first metrics
+			rw := d.blobReader()
+			rw.ErrOnRead(9)
+			return rw, nil
+		default:
+			return d.blobReader(), nil
+		}
+	}
+	r := newTestProcessReceiver(t, &Config{PID: 42}, consumertest.NewNop(), d)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	assert.Equal(t, []int{42, 42, 42}, d.waitDialed(3))
+	require.Eventually(t, func() bool {
+		return r.hasTarget(42)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestProcessReceiver_Unresponsive(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	d := newFakeDialer(t)
+	var mu sync.Mutex
+	var unresponsive []net.Conn
+	d.newConn = func(pid int) (io.ReadWriter, error) {
+		if pid != 42 {
+			return d.blobReader(), nil
+		}
+		// the process accepts the connection but never reads the request
+		client, server := net.Pipe()
+		mu.Lock()
+		unresponsive = append(unresponsive, server)
+		mu.Unlock()
+		return client, nil
+	}
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range unresponsive {
+			c.Close()
+		}
+	}()
+	r := newTestProcessReceiver(t, &Config{ConnectTimeout: 50 * time.Millisecond}, sink, d)
+
+	start := time.Now()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Less(t, int64(time.Since(start)), int64(r.cfg.ConnectTimeout))
+
+	// the other processes are connected to meanwhile
+	require.Eventually(t, func() bool {
+		pids := resourcePIDs(sink)
+		return pids[43] && pids[44]
+	}, time.Second, 10*time.Millisecond)
+
+	// the connection times out, and is retried on the next discoveries
+	require.Eventually(t, func() bool {
+		count := 0
+		for _, pid := range d.dialedPIDs() {
+			if pid == 42 {
+				count++
+			}
+		}
+		return count >= 2
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, resourcePIDs(sink)[42])
+
+	start = time.Now()
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestProcessReceiver_GlobError(t *testing.T) {
+	d := newFakeDialer(t)
+	r := newTestProcessReceiver(t, &Config{}, consumertest.NewNop(), d)
+	r.glob = func(string) ([]string, error) {
+		return nil, errors.New("bad pattern")
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Empty(t, d.dialedPIDs())
+}
+
+func TestProcessReceiver_BlobWriter(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestProcessReceiver(t, &Config{LocalDebugDir: dir, MaxLocalDebugFiles: 1}, consumertest.NewNop(), newFakeDialer(t))
+	require.NoError(t, r.blobWriter(42).Init())
+	assert.DirExists(t, filepath.Join(dir, "42"))
+
+	r.cfg.PID = 42
+	assert.NotNil(t, r.blobWriter(42))
+}
+
+func newTestProcessReceiver(t *testing.T, cfg *Config, mc consumer.Metrics, d *fakeDialer) *processReceiver {
+	cfg.CollectionInterval = time.Second
+	cfg.DiscoveryInterval = 10 * time.Millisecond
+	if cfg.ConnectTimeout == 0 {
+		cfg.ConnectTimeout = time.Second
+	}
+	r, err := newProcessReceiver(
+		cfg,
+		zap.NewNop(),
+		func(string) ([]string, error) {
+			var matches []string
+			for pid := range testProcesses {
+				matches = append(matches, filepath.Join("/tmp", fmt.Sprintf("dotnet-diagnostic-%d-1234-socket", pid)))
+			}
+			return matches, nil
+		},
+		d.dial,
+		func(pid int) (processInfo, error) {
+			return testProcesses[pid], nil
+		},
+	)
+	require.NoError(t, err)
+	r.registerMetricsConsumer(mc)
+	return r
+}
+
+// fakeDialer records the connections to the dotnet processes, and by default
+// returns connections replaying the testdata blobs.
+type fakeDialer struct {
+	t       *testing.T
+	data    [][]byte
+	newConn func(pid int) (io.ReadWriter, error)
+
+	mu     sync.Mutex
+	dialed []int
+}
+
+func newFakeDialer(t *testing.T) *fakeDialer {
+	data, err := network.ReadBlobData("testdata", 16)
+	require.NoError(t, err)
+	d := &fakeDialer{t: t, data: data}
+	d.newConn = func(int) (io.ReadWriter, error) {
+		return d.blobReader(), nil
+	}
+	return d
+}
+
+func (d *fakeDialer) blobReader() *network.BlobReader {
+	// each reader consumes its chunks, so they are copied
+	data := make([][]byte, len(d.data))
+	for i, p := range d.data {
+		data[i] = append([]byte(nil), p...)
+	}
+	return network.NewBlobReader(data)
+}
+
+func (d *fakeDialer) dial(path string) (io.ReadWriter, error) {
+	var pid int
+	_, err := fmt.Sscanf(filepath.Base(path), "dotnet-diagnostic-%d-", &pid)
+	require.NoError(d.t, err)
+	d.mu.Lock()
+	d.dialed = append(d.dialed, pid)
+	d.mu.Unlock()
+	return d.newConn(pid)
+}
+
+func (d *fakeDialer) dialedPIDs() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]int(nil), d.dialed...)
+}
+
+func (d *fakeDialer) waitDialed(n int) []int {
+	require.Eventually(d.t, func() bool {
+		return len(d.dialedPIDs()) >= n
+	}, time.Second, 10*time.Millisecond)
+	return d.dialedPIDs()[:n]
+}
+
+func resourcePIDs(sink *consumertest.MetricsSink) map[int64]bool {
+	pids := map[int64]bool{}
+	for _, md := range sink.AllMetrics() {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			pid, _ := rms.At(i).Resource().Attributes().Get(conventions.AttributeProcessID)
+			pids[pid.IntVal()] = true
+		}
+	}
+	return pids
+}
+
+func findResource(t *testing.T, sink *consumertest.MetricsSink, pid int64) pdata.Resource {
+	for _, md := range sink.AllMetrics() {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			res := rms.At(i).Resource()
+			if v, ok := res.Attributes().Get(conventions.AttributeProcessID); ok && v.IntVal() == pid {
+				return res
+			}
+		}
+	}
+	require.Failf(t, "resource not found", "pid %d", pid)
+	return pdata.Resource{}
+}
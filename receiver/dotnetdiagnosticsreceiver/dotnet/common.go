@@ -25,3 +25,12 @@ func endObject(r network.MultiReader) error {
 	const tagEndObject = 6
 	return r.AssertNextByteEquals(tagEndObject)
 }
+
+// seekTo moves the current position forward to pos, if it is not already there
+// or past it.
+func seekTo(r network.MultiReader, pos int) error {
+	if n := pos - r.Pos(); n > 0 {
+		return r.Seek(n)
+	}
+	return nil
+}
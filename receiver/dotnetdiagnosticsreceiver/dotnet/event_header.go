@@ -25,6 +25,9 @@ type eventHeader struct {
 	stackID           int32
 	payloadSize       int32
 	timestampDelta    int64
+	// timestamp is the QPC timestamp of the event, the deltas being relative to
+	// the previous event of the block
+	timestamp int64
 }
 
 type headerFlags byte
@@ -47,8 +50,8 @@ func (f headerFlags) isSet(other headerFlags) bool {
 
 // parseEventHeader is used by event parser (and by metadata parser for stream
 // alignment only) to get the metadata ID so that it can be correlated to the
-// extracted metadata. The rest of the extracted information is currently
-// unused.
+// extracted metadata, the payload size, and the timestamp and thread ID of the
+// runtime events. The rest of the extracted information is currently unused.
 func parseEventHeader(r network.MultiReader, h *eventHeader) (err error) {
 	// EventPipeEventHeader.ReadFromFormatV4
 	var b byte
@@ -102,6 +105,7 @@ func parseEventHeader(r network.MultiReader, h *eventHeader) (err error) {
 	if err != nil {
 		return
 	}
+	h.timestamp += h.timestampDelta
 
 	const guidSize = 16
 	if f.isSet(headerFlagActivityID) {
@@ -19,12 +19,14 @@ import (
 )
 
 // parseEventBlock parses an event block and returns a Metric slice containing
-// the raw representation of the metrics extracted from the event messages. It
-// uses the structure and names of the passed-in fieldMetadataMap (from
-// parseMetadataBlock) and the values extracted from the stream to build the
-// Metrics and their key-value pairs.
+// the raw representation of the metrics extracted from the event messages, and
+// an Event slice containing the runtime events. It uses the structure and
+// names of the passed-in fieldMetadataMap (from parseMetadataBlock) and the
+// values extracted from the stream to build the Metrics and Events and their
+// key-value pairs, and the passed-in traceClock (from parseTraceMessage) to
+// timestamp the Events.
 // https://github.com/Microsoft/perfview/blob/main/src/TraceEvent/EventPipe/EventPipeFormat.md#the-eventblock-object
-func parseEventBlock(r network.MultiReader, fm fieldMetadataMap) (metrics []Metric, err error) {
+func parseEventBlock(r network.MultiReader, fm fieldMetadataMap, clock traceClock) (metrics []Metric, events []Event, err error) {
 	var offset int32
 	err = r.Read(&offset)
 	if err != nil {
@@ -64,24 +66,40 @@ func parseEventBlock(r network.MultiReader, fm fieldMetadataMap) (metrics []Metr
 			return
 		}
 
+		payloadEnd := r.Pos() + int(header.payloadSize)
+
 		m := Metric{}
 		// here we correlate the metadata extracted from parseMetadataBlock to the events
 		// contained in this message
-		err = parseFieldValues(fm[int(header.metadataID)].fields, r, m)
+		md := fm[int(header.metadataID)]
+		err = parseFieldValues(md.fields, r, m)
 		if err != nil {
 			return
 		}
-		if len(m) > 0 {
+		if re, ok := lookupRuntimeEvent(md.header); ok {
+			events = append(events, Event{
+				Name:      re.name,
+				Timestamp: clock.time(header.timestamp),
+				ThreadID:  header.threadID,
+				Payload:   m,
+			})
+		} else if len(m) > 0 {
 			metrics = append(metrics, m)
 		}
+
+		// skip the fields that aren't decoded
+		err = seekTo(r, payloadEnd)
+		if err != nil {
+			return
+		}
 	}
 
 	return
 }
 
-// parseFieldValues recursively populates a Metric using metadata fields and a
-// MultiReader.
-func parseFieldValues(fields []field, r network.MultiReader, m Metric) error {
+// parseFieldValues recursively populates a Metric, or the payload of an Event,
+// using metadata fields and a MultiReader.
+func parseFieldValues(fields []field, r network.MultiReader, m map[string]interface{}) error {
 	for _, field := range fields {
 		// These are all of the types encountered during testing thus far.
 		// TODO look for use cases that require additional types
@@ -119,6 +137,34 @@ func parseFieldValues(fields []field, r network.MultiReader, m Metric) error {
 				return err
 			}
 			m[field.name] = v
+		case fieldTypeUInt16:
+			var v uint16
+			err := r.Read(&v)
+			if err != nil {
+				return err
+			}
+			m[field.name] = v
+		case fieldTypeUInt32:
+			var v uint32
+			err := r.Read(&v)
+			if err != nil {
+				return err
+			}
+			m[field.name] = v
+		case fieldTypeInt64:
+			var v int64
+			err := r.Read(&v)
+			if err != nil {
+				return err
+			}
+			m[field.name] = v
+		case fieldTypeUInt64:
+			var v uint64
+			err := r.Read(&v)
+			if err != nil {
+				return err
+			}
+			m[field.name] = v
 		}
 	}
 	return nil
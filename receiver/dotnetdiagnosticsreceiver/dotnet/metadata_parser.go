@@ -28,7 +28,9 @@ type fieldMetadata struct {
 	fields []field
 }
 
-// only metadataID is used
+// metadataID is used to correlate the events to their metadata, and the
+// provider name, event header ID (the event ID) and version to decode the
+// events of the runtime provider
 type metadataHeader struct {
 	metadataID    int32
 	providerName  string
@@ -52,7 +54,11 @@ type fieldType string
 const (
 	fieldTypeStruct fieldType = "Struct"
 	fieldTypeString fieldType = "String"
+	fieldTypeUInt16 fieldType = "UInt16"
 	fieldTypeInt32  fieldType = "Int32"
+	fieldTypeUInt32 fieldType = "UInt32"
+	fieldTypeInt64  fieldType = "Int64"
+	fieldTypeUInt64 fieldType = "UInt64"
 	fieldTypeSingle fieldType = "Single"
 	fieldTypeDouble fieldType = "Double"
 )
@@ -91,8 +97,11 @@ func parseMetadataBlock(r network.MultiReader, fmm fieldMetadataMap) error {
 		return err
 	}
 
+	// the header is shared by the events of the block because of the header
+	// compression
+	eh := eventHeader{}
 	for r.Pos() < endpos {
-		fm, err := parseFieldMetadata(r)
+		fm, err := parseFieldMetadata(r, &eh)
 		if err != nil {
 			return err
 		}
@@ -102,12 +111,12 @@ func parseMetadataBlock(r network.MultiReader, fmm fieldMetadataMap) error {
 	return nil
 }
 
-func parseFieldMetadata(r network.MultiReader) (m fieldMetadata, err error) {
-	ignored := eventHeader{}
-	err = parseEventHeader(r, &ignored)
+func parseFieldMetadata(r network.MultiReader, eh *eventHeader) (m fieldMetadata, err error) {
+	err = parseEventHeader(r, eh)
 	if err != nil {
 		return
 	}
+	payloadEnd := r.Pos() + int(eh.payloadSize)
 
 	m.header, err = parseMetadataHeader(r)
 	if err != nil {
@@ -119,6 +128,14 @@ func parseFieldMetadata(r network.MultiReader) (m fieldMetadata, err error) {
 		return
 	}
 
+	if len(m.fields) == 0 {
+		// the runtime events only come with the minimum metadata, so their
+		// fields are looked up from their ID and version
+		m.fields = runtimeEventFields(m.header)
+	}
+
+	// skip the parameter metadata of newer versions of the format, if any
+	err = seekTo(r, payloadEnd)
 	return
 }
 
@@ -181,7 +198,11 @@ func parseFields(r network.MultiReader) (fields []field, err error) {
 // from https://docs.microsoft.com/en-us/dotnet/api/system.typecode
 const (
 	typeCodeObject = 1
+	typeCodeUInt16 = 8
 	typeCodeInt32  = 9
+	typeCodeUInt32 = 10
+	typeCodeInt64  = 11
+	typeCodeUInt64 = 12
 	typeCodeSingle = 13
 	typeCodeDouble = 14
 	typeCodeString = 18
@@ -202,8 +223,16 @@ func parseField(r network.MultiReader) (f field, err error) {
 			return
 		}
 		f.fieldType = fieldTypeStruct
+	case typeCodeUInt16:
+		f.fieldType = fieldTypeUInt16
 	case typeCodeInt32:
 		f.fieldType = fieldTypeInt32
+	case typeCodeUInt32:
+		f.fieldType = fieldTypeUInt32
+	case typeCodeInt64:
+		f.fieldType = fieldTypeInt64
+	case typeCodeUInt64:
+		f.fieldType = fieldTypeUInt64
 	case typeCodeSingle:
 		f.fieldType = fieldTypeSingle
 	case typeCodeDouble:
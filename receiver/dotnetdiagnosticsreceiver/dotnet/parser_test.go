@@ -40,6 +40,7 @@ func TestParser(t *testing.T) {
 	p := NewParser(
 		rw,
 		func(metrics []Metric) {},
+		nil,
 		&network.NopBlobWriter{},
 		zap.NewNop(),
 	)
@@ -53,7 +54,7 @@ func TestParser_TestData(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
@@ -110,7 +111,7 @@ func TestParser_ParseAll_Error(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
@@ -131,7 +132,7 @@ func TestParser_ParseAll_Cancel(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
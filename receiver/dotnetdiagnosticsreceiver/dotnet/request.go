@@ -35,12 +35,19 @@ import (
 type RequestWriter struct {
 	w           io.Writer
 	intervalSec int
+	// runtimeKeywords indicate which runtime events to get, none if zero
+	runtimeKeywords RuntimeKeywords
 	// providerNames (aka event sources) indicate which counter groups to get metrics for. e.g. "System.Runtime"
 	providerNames []string
 }
 
-func NewRequestWriter(w io.Writer, intervalSec int, providerNames ...string) *RequestWriter {
-	return &RequestWriter{w: w, intervalSec: intervalSec, providerNames: providerNames}
+func NewRequestWriter(w io.Writer, intervalSec int, runtimeKeywords RuntimeKeywords, providerNames ...string) *RequestWriter {
+	return &RequestWriter{
+		w:               w,
+		intervalSec:     intervalSec,
+		runtimeKeywords: runtimeKeywords,
+		providerNames:   providerNames,
+	}
 }
 
 func (w *RequestWriter) SendRequest() error {
@@ -59,7 +66,7 @@ const eventPipeCommand = 2
 const collectTracing2CommandID = 3
 
 func (w *RequestWriter) createRequest() []byte {
-	cfgReq := newConfigRequest(w.intervalSec, w.providerNames...)
+	cfgReq := newConfigRequest(w.intervalSec, w.runtimeKeywords, w.providerNames...)
 	payload := cfgReq.serialize()
 	hdr := &requestHeader{
 		commandSet: eventPipeCommand,
@@ -102,12 +109,16 @@ type serializationFormat uint32
 
 const netTrace = 1
 
-func newConfigRequest(intervalSec int, providerNames ...string) configRequest {
+func newConfigRequest(intervalSec int, runtimeKeywords RuntimeKeywords, providerNames ...string) configRequest {
+	providers := createProviders(intervalSec, providerNames...)
+	if runtimeKeywords != 0 {
+		providers = append(providers, createRuntimeProvider(runtimeKeywords))
+	}
 	return configRequest{
 		circularBufferSizeInMB: 10,
 		format:                 netTrace,
 		requestRundown:         false,
-		providers:              createProviders(intervalSec, providerNames...),
+		providers:              providers,
 	}
 }
 
@@ -140,7 +151,10 @@ func createProviders(intervalSec int, names ...string) (ps []provider) {
 }
 
 // from https://docs.microsoft.com/en-us/dotnet/api/system.diagnostics.tracing.eventlevel
-const verboseEventLevel = 5
+const (
+	informationalEventLevel = 4
+	verboseEventLevel       = 5
+)
 
 func createProvider(name string, intervalSec int) provider {
 	s := strconv.Itoa(intervalSec)
@@ -152,6 +166,17 @@ func createProvider(name string, intervalSec int) provider {
 	}
 }
 
+// createRuntimeProvider creates the provider of the runtime events, at the
+// informational level which leaves out the high volume events such as the
+// allocation ticks.
+func createRuntimeProvider(keywords RuntimeKeywords) provider {
+	return provider{
+		name:       RuntimeProviderName,
+		eventLevel: informationalEventLevel,
+		keywords:   int64(keywords),
+	}
+}
+
 func (p provider) serialize(buf *bytes.Buffer) {
 	_ = binary.Write(buf, network.ByteOrder, p.keywords)
 	_ = binary.Write(buf, network.ByteOrder, p.eventLevel)
@@ -70,14 +70,26 @@ func TestRequestHeader_Serialize(t *testing.T) {
 }
 
 func TestSessionCfg(t *testing.T) {
-	req := newConfigRequest(42, "foo")
+	req := newConfigRequest(42, 0, "foo")
 	payload := req.serialize()
 	require.Equal(t, 95, len(payload))
 }
 
+func TestSessionCfg_RuntimeEvents(t *testing.T) {
+	req := newConfigRequest(42, RuntimeKeywordGC|RuntimeKeywordException, "foo")
+	require.Len(t, req.providers, 2)
+	p := req.providers[1]
+	assert.Equal(t, RuntimeProviderName, p.name)
+	assert.EqualValues(t, 0x8001, p.keywords)
+	assert.EqualValues(t, informationalEventLevel, p.eventLevel)
+	assert.Empty(t, p.args.String())
+	payload := req.serialize()
+	require.Equal(t, 181, len(payload))
+}
+
 func TestRequestWriter_Send(t *testing.T) {
 	rw := &network.FakeRW{WriteErrIdx: -1}
-	w := NewRequestWriter(rw, 0, "")
+	w := NewRequestWriter(rw, 0, 0, "")
 	err := w.SendRequest()
 	require.NoError(t, err)
 	require.Equal(t, 107, len(rw.Writes))
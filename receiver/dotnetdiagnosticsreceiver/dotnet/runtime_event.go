@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnet
+
+import "time"
+
+// RuntimeProviderName is the name of the provider of the .NET runtime events.
+const RuntimeProviderName = "Microsoft-Windows-DotNETRuntime"
+
+// RuntimeKeywords selects the groups of runtime events requested from the
+// dotnet process.
+// https://docs.microsoft.com/en-us/dotnet/fundamentals/diagnostics/runtime-events
+type RuntimeKeywords int64
+
+const (
+	RuntimeKeywordGC        RuntimeKeywords = 0x1
+	RuntimeKeywordException RuntimeKeywords = 0x8000
+	RuntimeKeywordThreading RuntimeKeywords = 0x10000
+)
+
+// Names of the decoded runtime events.
+const (
+	EventGCStart                          = "GCStart"
+	EventGCEnd                            = "GCEnd"
+	EventGCRestartEEEnd                   = "GCRestartEEEnd"
+	EventGCSuspendEEBegin                 = "GCSuspendEEBegin"
+	EventThreadPoolWorkerThreadStart      = "ThreadPoolWorkerThreadStart"
+	EventThreadPoolWorkerThreadStop       = "ThreadPoolWorkerThreadStop"
+	EventThreadPoolWorkerThreadAdjustment = "ThreadPoolWorkerThreadAdjustmentAdjustment"
+	EventExceptionThrown                  = "ExceptionThrown"
+)
+
+// runtimeEvent describes the leading fields of a runtime event, the fields
+// following them being skipped, from the minimum version of the event that
+// has them.
+type runtimeEvent struct {
+	name       string
+	minVersion int32
+	fields     []field
+}
+
+// runtimeEvents are the decoded runtime events by ID.
+// https://github.com/microsoft/perfview/blob/main/src/TraceEvent/Parsers/ClrTraceEventParser.cs
+var runtimeEvents = map[int32]runtimeEvent{
+	1: {name: EventGCStart, minVersion: 1, fields: []field{
+		{name: "Count", fieldType: fieldTypeUInt32},
+		{name: "Depth", fieldType: fieldTypeUInt32},
+		{name: "Reason", fieldType: fieldTypeUInt32},
+		{name: "Type", fieldType: fieldTypeUInt32},
+	}},
+	2: {name: EventGCEnd, minVersion: 1, fields: []field{
+		{name: "Count", fieldType: fieldTypeUInt32},
+		{name: "Depth", fieldType: fieldTypeUInt32},
+	}},
+	3: {name: EventGCRestartEEEnd},
+	9: {name: EventGCSuspendEEBegin, minVersion: 1, fields: []field{
+		{name: "Reason", fieldType: fieldTypeUInt32},
+		{name: "Count", fieldType: fieldTypeUInt32},
+	}},
+	50: {name: EventThreadPoolWorkerThreadStart, fields: []field{
+		{name: "ActiveWorkerThreadCount", fieldType: fieldTypeUInt32},
+		{name: "RetiredWorkerThreadCount", fieldType: fieldTypeUInt32},
+	}},
+	51: {name: EventThreadPoolWorkerThreadStop, fields: []field{
+		{name: "ActiveWorkerThreadCount", fieldType: fieldTypeUInt32},
+		{name: "RetiredWorkerThreadCount", fieldType: fieldTypeUInt32},
+	}},
+	55: {name: EventThreadPoolWorkerThreadAdjustment, fields: []field{
+		{name: "AverageThroughput", fieldType: fieldTypeDouble},
+		{name: "NewWorkerThreadCount", fieldType: fieldTypeUInt32},
+		{name: "Reason", fieldType: fieldTypeUInt32},
+	}},
+	80: {name: EventExceptionThrown, minVersion: 1, fields: []field{
+		{name: "ExceptionType", fieldType: fieldTypeString},
+		{name: "ExceptionMessage", fieldType: fieldTypeString},
+	}},
+}
+
+// lookupRuntimeEvent returns the description of the runtime event of the
+// given metadata, and false if the event isn't a decoded runtime event, in
+// which case it's parsed as a Metric.
+func lookupRuntimeEvent(h metadataHeader) (runtimeEvent, bool) {
+	if h.providerName != RuntimeProviderName {
+		return runtimeEvent{}, false
+	}
+	e, ok := runtimeEvents[h.eventHeaderID]
+	if !ok || h.version < e.minVersion {
+		return runtimeEvent{}, false
+	}
+	return e, true
+}
+
+// runtimeEventFields returns the fields of a runtime event, or nil if the
+// event isn't decoded.
+func runtimeEventFields(h metadataHeader) []field {
+	e, _ := lookupRuntimeEvent(h)
+	return e.fields
+}
+
+// Event is a runtime event extracted from the event parser, with its payload
+// fields by name.
+type Event struct {
+	Name      string
+	Timestamp time.Time
+	ThreadID  int64
+	Payload   map[string]interface{}
+}
+
+// EventsConsumer is a function that accepts a slice of Events. Parser has a
+// member events consumer function, used to send Events as they are parsed.
+type EventsConsumer func([]Event)
+
+const (
+	payloadKeyActiveWorkerThreadCount = "ActiveWorkerThreadCount"
+	payloadKeyCount                   = "Count"
+	payloadKeyDepth                   = "Depth"
+	payloadKeyExceptionMessage        = "ExceptionMessage"
+	payloadKeyExceptionType           = "ExceptionType"
+	payloadKeyNewWorkerThreadCount    = "NewWorkerThreadCount"
+	payloadKeyReason                  = "Reason"
+)
+
+// GCCount returns the sequence number of the GC of the GCStart, GCEnd and
+// GCSuspendEEBegin events.
+func (e Event) GCCount() uint32 {
+	return e.uint32Value(payloadKeyCount)
+}
+
+// GCDepth returns the generation collected by the GC of the GCStart and GCEnd
+// events.
+func (e Event) GCDepth() uint32 {
+	return e.uint32Value(payloadKeyDepth)
+}
+
+// Reason returns the reason of the GCStart, GCSuspendEEBegin and thread pool
+// adjustment events.
+func (e Event) Reason() uint32 {
+	return e.uint32Value(payloadKeyReason)
+}
+
+// ActiveWorkerThreadCount returns the number of worker threads of the thread
+// pool of the thread start and stop events.
+func (e Event) ActiveWorkerThreadCount() uint32 {
+	return e.uint32Value(payloadKeyActiveWorkerThreadCount)
+}
+
+// NewWorkerThreadCount returns the number of worker threads of the thread
+// pool after the adjustment events.
+func (e Event) NewWorkerThreadCount() uint32 {
+	return e.uint32Value(payloadKeyNewWorkerThreadCount)
+}
+
+// ExceptionType returns the type of the exception of the ExceptionThrown
+// events.
+func (e Event) ExceptionType() string {
+	s, _ := e.Payload[payloadKeyExceptionType].(string)
+	return s
+}
+
+// ExceptionMessage returns the message of the exception of the ExceptionThrown
+// events.
+func (e Event) ExceptionMessage() string {
+	s, _ := e.Payload[payloadKeyExceptionMessage].(string)
+	return s
+}
+
+func (e Event) uint32Value(key string) uint32 {
+	v, _ := e.Payload[key].(uint32)
+	return v
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
+)
+
+func TestRuntimeEvents(t *testing.T) {
+	var block bytes.Buffer
+	writeMetadataBlock(&block, []testMetadata{
+		{id: 1, provider: RuntimeProviderName, eventID: 1, version: 2},
+		{id: 2, provider: RuntimeProviderName, eventID: 80, version: 1},
+		// not decoded, version 0 of GCStart has different fields
+		{id: 3, provider: RuntimeProviderName, eventID: 1, version: 0},
+		{id: 4, provider: RuntimeProviderName, eventID: 10, version: 0},
+	})
+	writeEventBlock(&block, []testEvent{
+		// GCStart_V2: Count, Depth, Reason, Type, ClrInstanceID, ClientSequenceNumber
+		{metadataID: 1, threadID: 7, timestamp: 1500, payload: payload(uint32(3), uint32(1), uint32(0), uint32(0), uint16(1), uint64(0))},
+		// ExceptionThrown_V1: ExceptionType, ExceptionMessage, ExceptionEIPCodeThrow, ExceptionHRESULT, ExceptionFlags, ClrInstanceID
+		{metadataID: 2, threadID: 8, timestamp: 2000, payload: payload("System.InvalidOperationException", "Oops", uint64(0), uint32(0x80131509), uint16(16), uint16(1))},
+		{metadataID: 3, threadID: 7, timestamp: 2500, payload: payload(uint32(4), uint32(0))},
+		{metadataID: 4, threadID: 7, timestamp: 3000, payload: payload(uint32(42))},
+	})
+
+	r := network.NewMultiReader(bytes.NewReader(block.Bytes()), &network.NopBlobWriter{})
+	fms := fieldMetadataMap{}
+	require.NoError(t, parseMetadataBlock(r, fms))
+	require.Len(t, fms, 4)
+	assert.Len(t, fms[1].fields, 4)
+	assert.Len(t, fms[2].fields, 2)
+	assert.Empty(t, fms[3].fields)
+	assert.Empty(t, fms[4].fields)
+
+	syncTime := time.Date(2021, 2, 9, 22, 22, 30, 0, time.UTC)
+	clock := traceClock{syncTime: syncTime, syncTimeQPC: 1000, qpcFreq: 1000}
+	metrics, events, err := parseEventBlock(r, fms, clock)
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+	require.Len(t, events, 2)
+
+	gc := events[0]
+	assert.Equal(t, EventGCStart, gc.Name)
+	assert.Equal(t, syncTime.Add(500*time.Millisecond), gc.Timestamp)
+	assert.EqualValues(t, 7, gc.ThreadID)
+	assert.EqualValues(t, 3, gc.GCCount())
+	assert.EqualValues(t, 1, gc.GCDepth())
+
+	ex := events[1]
+	assert.Equal(t, EventExceptionThrown, ex.Name)
+	assert.Equal(t, syncTime.Add(time.Second), ex.Timestamp)
+	assert.Equal(t, "System.InvalidOperationException", ex.ExceptionType())
+	assert.Equal(t, "Oops", ex.ExceptionMessage())
+}
+
+func TestTraceClock(t *testing.T) {
+	syncTime := time.Date(2021, 2, 9, 22, 22, 30, 0, time.UTC)
+	c := traceClock{syncTime: syncTime, syncTimeQPC: 10000000, qpcFreq: 10000000}
+	assert.Equal(t, syncTime.Add(1500*time.Millisecond), c.time(25000000))
+	assert.Equal(t, syncTime.Add(-100*time.Nanosecond), c.time(9999999))
+	assert.Equal(t, syncTime, traceClock{syncTime: syncTime}.time(42))
+}
+
+type testMetadata struct {
+	id       int32
+	provider string
+	eventID  int32
+	version  int32
+}
+
+type testEvent struct {
+	metadataID uint64
+	threadID   uint64
+	timestamp  uint64
+	payload    []byte
+}
+
+// writeMetadataBlock writes a metadata block with the minimum metadata of the
+// runtime events.
+func writeMetadataBlock(buf *bytes.Buffer, mds []testMetadata) {
+	var events []testEvent
+	for _, md := range mds {
+		events = append(events, testEvent{payload: payload(
+			md.id, md.provider, md.eventID, "", uint64(0), md.version, int32(4), int32(0),
+		)})
+	}
+	// the header is followed by the 16 bytes of the min and max timestamps
+	writeBlock(buf, 20, events)
+}
+
+func writeEventBlock(buf *bytes.Buffer, events []testEvent) {
+	writeBlock(buf, 4, events)
+}
+
+func writeBlock(buf *bytes.Buffer, headerSize uint16, events []testEvent) {
+	var b bytes.Buffer
+	_ = binary.Write(&b, network.ByteOrder, headerSize)
+	_ = binary.Write(&b, network.ByteOrder, uint16(1))
+	b.Write(make([]byte, headerSize-4))
+	var prevTimestamp uint64
+	for _, e := range events {
+		b.WriteByte(byte(headerFlagMetadataID | headerFlagThreadID | headerFlagDataLength))
+		writeCompressed(&b, e.metadataID)
+		writeCompressed(&b, e.threadID)
+		writeCompressed(&b, e.timestamp-prevTimestamp)
+		prevTimestamp = e.timestamp
+		writeCompressed(&b, uint64(len(e.payload)))
+		b.Write(e.payload)
+	}
+	_ = binary.Write(buf, network.ByteOrder, int32(b.Len()))
+	// the block starts on a 4-byte boundary
+	buf.Write(make([]byte, (4-buf.Len()%4)%4))
+	buf.Write(b.Bytes())
+}
+
+func writeCompressed(buf *bytes.Buffer, v uint64) {
+	p := make([]byte, binary.MaxVarintLen64)
+	buf.Write(p[:binary.PutUvarint(p, v)])
+}
+
+// payload serializes the passed-in values, the strings being zero-terminated
+// utf16 strings.
+func payload(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			_ = binary.Write(&buf, network.ByteOrder, utf16.Encode([]rune(s)))
+			buf.Write([]byte{0, 0})
+			continue
+		}
+		_ = binary.Write(&buf, network.ByteOrder, v)
+	}
+	return buf.Bytes()
+}
@@ -15,17 +15,36 @@
 package dotnet
 
 import (
+	"time"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
-// parseTraceMessage parses a trace message, populating an eventSource struct. The
-// results are currently not used, but parsing this message is necessary for byte
-// alignment to process subsequent messages.
+// traceClock converts the QPC timestamps of the events to times, using the
+// synchronization time of the trace message.
+type traceClock struct {
+	syncTime    time.Time
+	syncTimeQPC int64
+	qpcFreq     int64
+}
+
+func (c traceClock) time(qpc int64) time.Time {
+	if c.qpcFreq <= 0 {
+		return c.syncTime
+	}
+	d := qpc - c.syncTimeQPC
+	sec := d / c.qpcFreq
+	nsec := (d % c.qpcFreq) * int64(time.Second) / c.qpcFreq
+	return c.syncTime.Add(time.Duration(sec)*time.Second + time.Duration(nsec))
+}
+
+// parseTraceMessage parses a trace message, returning the clock used to
+// timestamp the runtime events. The rest of the message is currently not used,
+// but parsing it is necessary for byte alignment to process subsequent
+// messages.
 // https://github.com/Microsoft/perfview/blob/main/src/TraceEvent/EventPipe/EventPipeFormat.md#the-first-object-the-trace-object
-func parseTraceMessage(r network.MultiReader) (err error) {
+func parseTraceMessage(r network.MultiReader) (clock traceClock, err error) {
 	type eventSource struct {
-		syncTimeQPC             int64
-		qpcFreq                 int64
 		pointerSize             int32
 		processID               int32
 		numProcessors           int32
@@ -34,19 +53,17 @@ func parseTraceMessage(r network.MultiReader) (err error) {
 
 	es := eventSource{}
 
-	// skip date
-	const dateSize = 16
-	err = r.Seek(dateSize)
+	clock.syncTime, err = parseSystemTime(r)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.syncTimeQPC)
+	err = r.Read(&clock.syncTimeQPC)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.qpcFreq)
+	err = r.Read(&clock.qpcFreq)
 	if err != nil {
 		return
 	}
@@ -70,3 +87,25 @@ func parseTraceMessage(r network.MultiReader) (err error) {
 
 	return
 }
+
+// parseSystemTime parses a Windows SYSTEMTIME struct, in UTC.
+// https://docs.microsoft.com/en-us/windows/win32/api/minwinbase/ns-minwinbase-systemtime
+func parseSystemTime(r network.MultiReader) (time.Time, error) {
+	var st struct {
+		Year, Month, DayOfWeek, Day, Hour, Minute, Second, Milliseconds uint16
+	}
+	err := r.Read(&st)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(
+		int(st.Year),
+		time.Month(st.Month),
+		int(st.Day),
+		int(st.Hour),
+		int(st.Minute),
+		int(st.Second),
+		int(st.Milliseconds)*int(time.Millisecond),
+		time.UTC,
+	), nil
+}
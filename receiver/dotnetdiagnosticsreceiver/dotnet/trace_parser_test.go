@@ -30,7 +30,7 @@ func TestTraceParser(t *testing.T) {
 	reader := network.NewMultiReader(rw, &network.NopBlobWriter{})
 	err = reader.Seek(81)
 	require.NoError(t, err)
-	err = parseTraceMessage(reader)
+	_, err = parseTraceMessage(reader)
 	require.NoError(t, err)
 	require.Equal(t, 129, reader.Pos())
 }
@@ -49,6 +49,6 @@ func testTraceParserReadError(t *testing.T, data [][]byte, i int) {
 	err := reader.Seek(81)
 	require.NoError(t, err)
 	rw.ErrOnRead(i)
-	err = parseTraceMessage(reader)
+	_, err = parseTraceMessage(reader)
 	require.Error(t, err)
 }
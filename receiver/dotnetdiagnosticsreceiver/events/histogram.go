@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// durationBounds are the bucket bounds, in milliseconds, of the GC duration
+// histograms, from the sub-millisecond ephemeral GCs to the blocking full GCs
+// of large heaps.
+var durationBounds = []float64{0.1, 0.5, 1, 5, 10, 50, 100, 500, 1000, 5000}
+
+// histogram is a cumulative histogram with the durationBounds bucket bounds.
+type histogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram() *histogram {
+	return &histogram{buckets: make([]uint64, len(durationBounds)+1)}
+}
+
+func (h *histogram) record(v float64) {
+	// the upper bounds are inclusive
+	h.buckets[sort.SearchFloat64s(durationBounds, v)]++
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) copyTo(dp pdata.HistogramDataPoint, start, now pdata.Timestamp) {
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(now)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.SetExplicitBounds(durationBounds)
+	dp.SetBucketCounts(append([]uint64(nil), h.buckets...))
+}
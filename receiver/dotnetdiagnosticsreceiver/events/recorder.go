@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+const (
+	metricGCDuration        = "dotnet.gc.duration"
+	metricGCPauseDuration   = "dotnet.gc.pause.duration"
+	metricThreadPoolThreads = "dotnet.threadpool.worker_threads"
+
+	labelGeneration = "generation"
+)
+
+// from the SUSPEND_REASON enum of the runtime
+// https://docs.microsoft.com/en-us/dotnet/fundamentals/diagnostics/runtime-garbage-collection-events#gcsuspendeebegin_v1-event
+const (
+	suspendForGC     = 1
+	suspendForGCPrep = 6
+)
+
+// maxPendingGCs bounds the number of GCs whose start is kept while waiting
+// for their end, should the end events be lost.
+const maxPendingGCs = 16
+
+// Recorder aggregates the runtime events of a dotnet process into metrics,
+// and sends the exceptions to the next logs consumer. Record conforms to
+// dotnet.EventsConsumer so it can be passed into a Parser, and AppendMetrics
+// is called by a metrics.Sender to send the metrics along with the counters.
+// Both are expected to be called from the goroutine of the Parser.
+type Recorder struct {
+	nextLogs  consumer.Logs
+	resource  pdata.Resource
+	logger    *zap.Logger
+	startTime time.Time
+
+	gcStarts      map[uint32]dotnet.Event
+	gcDurations   map[uint32]*histogram
+	suspendBegin  time.Time
+	gcPauses      *histogram
+	workerThreads int64
+	hasThreads    bool
+}
+
+// NewRecorder creates a Recorder whose logs have the attributes of the passed
+// in resource, the resource of the dotnet process. The logs consumer may be
+// nil, in which case the exceptions are dropped.
+func NewRecorder(nextLogs consumer.Logs, resource pdata.Resource, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		nextLogs:    nextLogs,
+		resource:    resource,
+		logger:      logger,
+		startTime:   time.Now(),
+		gcStarts:    map[uint32]dotnet.Event{},
+		gcDurations: map[uint32]*histogram{},
+		gcPauses:    newHistogram(),
+	}
+}
+
+// Record aggregates the passed in runtime events, and sends the exceptions
+// to the next logs consumer. Conforms to dotnet.EventsConsumer.
+func (r *Recorder) Record(events []dotnet.Event) {
+	var exceptions []dotnet.Event
+	for _, e := range events {
+		switch e.Name {
+		case dotnet.EventGCStart:
+			if len(r.gcStarts) >= maxPendingGCs {
+				r.gcStarts = map[uint32]dotnet.Event{}
+			}
+			r.gcStarts[e.GCCount()] = e
+		case dotnet.EventGCEnd:
+			r.recordGCEnd(e)
+		case dotnet.EventGCSuspendEEBegin:
+			r.suspendBegin = time.Time{}
+			if e.Reason() == suspendForGC || e.Reason() == suspendForGCPrep {
+				r.suspendBegin = e.Timestamp
+			}
+		case dotnet.EventGCRestartEEEnd:
+			if !r.suspendBegin.IsZero() {
+				r.gcPauses.record(millis(e.Timestamp.Sub(r.suspendBegin)))
+				r.suspendBegin = time.Time{}
+			}
+		case dotnet.EventThreadPoolWorkerThreadStart, dotnet.EventThreadPoolWorkerThreadStop:
+			r.workerThreads = int64(e.ActiveWorkerThreadCount())
+			r.hasThreads = true
+		case dotnet.EventThreadPoolWorkerThreadAdjustment:
+			r.workerThreads = int64(e.NewWorkerThreadCount())
+			r.hasThreads = true
+		case dotnet.EventExceptionThrown:
+			exceptions = append(exceptions, e)
+		}
+	}
+	if len(exceptions) > 0 {
+		r.sendExceptions(exceptions)
+	}
+}
+
+func (r *Recorder) recordGCEnd(e dotnet.Event) {
+	start, ok := r.gcStarts[e.GCCount()]
+	if !ok {
+		return
+	}
+	delete(r.gcStarts, e.GCCount())
+	h, ok := r.gcDurations[start.GCDepth()]
+	if !ok {
+		h = newHistogram()
+		r.gcDurations[start.GCDepth()] = h
+	}
+	h.record(millis(e.Timestamp.Sub(start.Timestamp)))
+}
+
+func (r *Recorder) sendExceptions(exceptions []dotnet.Event) {
+	if r.nextLogs == nil {
+		return
+	}
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	r.resource.CopyTo(rl.Resource())
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, e := range exceptions {
+		lr := logs.AppendEmpty()
+		lr.SetName(e.Name)
+		lr.SetTimestamp(pdata.TimestampFromTime(e.Timestamp))
+		// the level of the ExceptionThrown event
+		lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+		lr.SetSeverityText("Error")
+		lr.Body().SetStringVal(e.ExceptionMessage())
+		attrs := lr.Attributes()
+		attrs.InsertString(conventions.AttributeExceptionType, e.ExceptionType())
+		attrs.InsertString(conventions.AttributeExceptionMessage, e.ExceptionMessage())
+	}
+	err := r.nextLogs.ConsumeLogs(context.Background(), ld)
+	if err != nil {
+		r.logger.Error(err.Error())
+	}
+}
+
+// AppendMetrics appends the metrics aggregated from the runtime events since
+// the creation of the Recorder to the passed in slice. Only the metrics whose
+// events were received are appended.
+func (r *Recorder) AppendMetrics(ms pdata.MetricSlice, now time.Time) {
+	startPD := pdata.TimestampFromTime(r.startTime)
+	nowPD := pdata.TimestampFromTime(now)
+
+	if len(r.gcDurations) > 0 {
+		m := ms.AppendEmpty()
+		m.SetName(metricGCDuration)
+		m.SetDescription("Duration of the garbage collections, by generation")
+		m.SetUnit("ms")
+		m.SetDataType(pdata.MetricDataTypeHistogram)
+		hist := m.Histogram()
+		hist.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		// sorted for testing
+		generations := make([]int, 0, len(r.gcDurations))
+		for gen := range r.gcDurations {
+			generations = append(generations, int(gen))
+		}
+		sort.Ints(generations)
+		for _, gen := range generations {
+			dp := hist.DataPoints().AppendEmpty()
+			dp.LabelsMap().Insert(labelGeneration, strconv.Itoa(gen))
+			r.gcDurations[uint32(gen)].copyTo(dp, startPD, nowPD)
+		}
+	}
+
+	if r.gcPauses.count > 0 {
+		m := ms.AppendEmpty()
+		m.SetName(metricGCPauseDuration)
+		m.SetDescription("Duration of the suspensions of the execution engine for the garbage collections")
+		m.SetUnit("ms")
+		m.SetDataType(pdata.MetricDataTypeHistogram)
+		hist := m.Histogram()
+		hist.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		r.gcPauses.copyTo(hist.DataPoints().AppendEmpty(), startPD, nowPD)
+	}
+
+	if r.hasThreads {
+		m := ms.AppendEmpty()
+		m.SetName(metricThreadPoolThreads)
+		m.SetDescription("Number of worker threads of the thread pool")
+		m.SetUnit("1")
+		m.SetDataType(pdata.MetricDataTypeIntGauge)
+		dp := m.IntGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(nowPD)
+		dp.SetValue(r.workerThreads)
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
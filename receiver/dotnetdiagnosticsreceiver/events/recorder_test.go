@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+var t0 = time.Date(2021, 2, 9, 22, 22, 30, 0, time.UTC)
+
+func event(name string, offset time.Duration, payload map[string]interface{}) dotnet.Event {
+	return dotnet.Event{Name: name, Timestamp: t0.Add(offset), Payload: payload}
+}
+
+func TestRecorder_GC(t *testing.T) {
+	r := NewRecorder(nil, pdata.NewResource(), zap.NewNop())
+	r.Record([]dotnet.Event{
+		event(dotnet.EventGCSuspendEEBegin, 0, map[string]interface{}{"Reason": uint32(suspendForGC), "Count": uint32(1)}),
+		event(dotnet.EventGCStart, 100*time.Microsecond, map[string]interface{}{"Count": uint32(1), "Depth": uint32(0)}),
+		event(dotnet.EventGCEnd, 2*time.Millisecond, map[string]interface{}{"Count": uint32(1), "Depth": uint32(0)}),
+		event(dotnet.EventGCRestartEEEnd, 3*time.Millisecond, nil),
+		// a suspension for another reason is not a GC pause
+		event(dotnet.EventGCSuspendEEBegin, 10*time.Millisecond, map[string]interface{}{"Reason": uint32(2)}),
+		event(dotnet.EventGCRestartEEEnd, 20*time.Millisecond, nil),
+	})
+	r.Record([]dotnet.Event{
+		event(dotnet.EventGCStart, time.Second, map[string]interface{}{"Count": uint32(2), "Depth": uint32(2)}),
+		// the end of a GC whose start was not received is ignored
+		event(dotnet.EventGCEnd, time.Second, map[string]interface{}{"Count": uint32(3), "Depth": uint32(0)}),
+		event(dotnet.EventGCEnd, 1200*time.Millisecond, map[string]interface{}{"Count": uint32(2), "Depth": uint32(2)}),
+	})
+
+	ms := pdata.NewMetricSlice()
+	now := t0.Add(2 * time.Second)
+	r.AppendMetrics(ms, now)
+	require.Equal(t, 2, ms.Len())
+
+	durations := ms.At(0)
+	assert.Equal(t, "dotnet.gc.duration", durations.Name())
+	assert.Equal(t, "ms", durations.Unit())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, durations.Histogram().AggregationTemporality())
+	dps := durations.Histogram().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	gen, _ := dps.At(0).LabelsMap().Get("generation")
+	assert.Equal(t, "0", gen)
+	assert.EqualValues(t, 1, dps.At(0).Count())
+	assert.InDelta(t, 1.9, dps.At(0).Sum(), 1e-9)
+	assert.Equal(t, []uint64{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, dps.At(0).BucketCounts())
+	assert.Equal(t, pdata.TimestampFromTime(now), dps.At(0).Timestamp())
+	gen, _ = dps.At(1).LabelsMap().Get("generation")
+	assert.Equal(t, "2", gen)
+	assert.InDelta(t, 200, dps.At(1).Sum(), 1e-9)
+	assert.Equal(t, []uint64{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0}, dps.At(1).BucketCounts())
+
+	pauses := ms.At(1)
+	assert.Equal(t, "dotnet.gc.pause.duration", pauses.Name())
+	require.Equal(t, 1, pauses.Histogram().DataPoints().Len())
+	dp := pauses.Histogram().DataPoints().At(0)
+	assert.EqualValues(t, 1, dp.Count())
+	assert.InDelta(t, 3, dp.Sum(), 1e-9)
+	assert.Equal(t, durationBounds, dp.ExplicitBounds())
+}
+
+func TestRecorder_PendingGCs(t *testing.T) {
+	r := NewRecorder(nil, pdata.NewResource(), zap.NewNop())
+	for i := 0; i < 2*maxPendingGCs; i++ {
+		r.Record([]dotnet.Event{
+			event(dotnet.EventGCStart, 0, map[string]interface{}{"Count": uint32(i), "Depth": uint32(0)}),
+		})
+	}
+	assert.LessOrEqual(t, len(r.gcStarts), maxPendingGCs)
+}
+
+func TestRecorder_ThreadPool(t *testing.T) {
+	r := NewRecorder(nil, pdata.NewResource(), zap.NewNop())
+	r.Record([]dotnet.Event{
+		event(dotnet.EventThreadPoolWorkerThreadStart, 0, map[string]interface{}{"ActiveWorkerThreadCount": uint32(4)}),
+		event(dotnet.EventThreadPoolWorkerThreadAdjustment, 0, map[string]interface{}{"NewWorkerThreadCount": uint32(6)}),
+		event(dotnet.EventThreadPoolWorkerThreadStop, 0, map[string]interface{}{"ActiveWorkerThreadCount": uint32(5)}),
+	})
+
+	ms := pdata.NewMetricSlice()
+	r.AppendMetrics(ms, t0)
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "dotnet.threadpool.worker_threads", ms.At(0).Name())
+	assert.EqualValues(t, 5, ms.At(0).IntGauge().DataPoints().At(0).Value())
+}
+
+func TestRecorder_NoEvents(t *testing.T) {
+	r := NewRecorder(nil, pdata.NewResource(), zap.NewNop())
+	ms := pdata.NewMetricSlice()
+	r.AppendMetrics(ms, t0)
+	assert.Equal(t, 0, ms.Len())
+}
+
+func TestRecorder_Exceptions(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.Attributes().InsertInt(conventions.AttributeProcessID, 1234)
+	sink := &consumertest.LogsSink{}
+	r := NewRecorder(sink, resource, zap.NewNop())
+	r.Record([]dotnet.Event{
+		event(dotnet.EventExceptionThrown, time.Second, map[string]interface{}{
+			"ExceptionType":    "System.InvalidOperationException",
+			"ExceptionMessage": "Oops",
+		}),
+		event(dotnet.EventGCRestartEEEnd, 0, nil),
+	})
+
+	require.Len(t, sink.AllLogs(), 1)
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	pid, _ := rl.Resource().Attributes().Get(conventions.AttributeProcessID)
+	assert.EqualValues(t, 1234, pid.IntVal())
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 1, logs.Len())
+	lr := logs.At(0)
+	assert.Equal(t, "ExceptionThrown", lr.Name())
+	assert.Equal(t, pdata.TimestampFromTime(t0.Add(time.Second)), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "Oops", lr.Body().StringVal())
+	typ, _ := lr.Attributes().Get(conventions.AttributeExceptionType)
+	assert.Equal(t, "System.InvalidOperationException", typ.StringVal())
+}
+
+func TestRecorder_ExceptionsError(t *testing.T) {
+	obs, logs := observer.New(zapcore.WarnLevel)
+	r := NewRecorder(consumertest.NewErr(errors.New("")), pdata.NewResource(), zap.New(obs))
+	r.Record([]dotnet.Event{event(dotnet.EventExceptionThrown, 0, nil)})
+	assert.Equal(t, 1, logs.Len())
+
+	// without logs consumer, the exceptions are dropped
+	r = NewRecorder(nil, pdata.NewResource(), zap.NewNop())
+	r.Record([]dotnet.Event{event(dotnet.EventExceptionThrown, 0, nil)})
+}
@@ -17,9 +17,9 @@ package dotnetdiagnosticsreceiver
 import (
 	"context"
 	"io"
-	"math"
 	"net"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -27,8 +27,6 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
 const typeStr = "dotnet_diagnostics"
@@ -38,6 +36,7 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver),
 	)
 }
 
@@ -47,32 +46,73 @@ func createDefaultConfig() config.Receiver {
 			ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
 			CollectionInterval: time.Second,
 		},
-		Counters: []string{"System.Runtime", "Microsoft.AspNetCore.Hosting"},
+		Counters:          []string{"System.Runtime", "Microsoft.AspNetCore.Hosting"},
+		DiscoveryInterval: 10 * time.Second,
+		ConnectTimeout:    5 * time.Second,
 	}
 }
 
 func createMetricsReceiver(
-	ctx context.Context,
+	_ context.Context,
 	params component.ReceiverCreateParams,
 	baseConfig config.Receiver,
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
-	cfg := baseConfig.(*Config)
-	bw := network.NewBlobWriter(cfg.LocalDebugDir, cfg.MaxLocalDebugFiles, params.Logger)
-	sec := int(math.Round(cfg.CollectionInterval.Seconds()))
-	return NewReceiver(
-		ctx,
-		consumer,
-		mkConnectionSupplier(cfg.PID, net.Dial, filepath.Glob),
-		cfg.Counters,
-		sec,
-		params.Logger,
-		bw,
-	)
+	r, err := getOrCreateReceiver(baseConfig.(*Config), params)
+	if err != nil {
+		return nil, err
+	}
+	r.registerMetricsConsumer(consumer)
+	return r, nil
 }
 
-func mkConnectionSupplier(pid int, df network.DialFunc, gf network.GlobFunc) connectionSupplier {
-	return func() (io.ReadWriter, error) {
-		return network.Connect(pid, df, gf)
+// createLogsReceiver creates a receiver sending the exceptions thrown by the
+// dotnet processes as logs, shared with the metrics receiver of the same
+// config.
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	baseConfig config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	r, err := getOrCreateReceiver(baseConfig.(*Config), params)
+	if err != nil {
+		return nil, err
 	}
+	r.registerLogsConsumer(consumer)
+	return r, nil
+}
+
+func getOrCreateReceiver(cfg *Config, params component.ReceiverCreateParams) (*processReceiver, error) {
+	receiverLock.Lock()
+	defer receiverLock.Unlock()
+	r := receivers[cfg]
+	if r == nil {
+		var err error
+		r, err = newProcessReceiver(
+			cfg,
+			params.Logger,
+			filepath.Glob,
+			dialSocket,
+			procfsReader("/proc"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		receivers[cfg] = r
+	}
+	return r, nil
+}
+
+func removeReceiver(cfg *Config) {
+	receiverLock.Lock()
+	defer receiverLock.Unlock()
+	delete(receivers, cfg)
+}
+
+var receiverLock sync.Mutex
+var receivers = map[*Config]*processReceiver{}
+
+func dialSocket(path string) (io.ReadWriter, error) {
+	return net.Dial("unix", path)
 }
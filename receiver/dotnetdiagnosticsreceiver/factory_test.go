@@ -16,12 +16,13 @@ package dotnetdiagnosticsreceiver
 
 import (
 	"context"
-	"net"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
@@ -49,13 +50,63 @@ func TestNewFactory(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
-func TestMkConnectionSupplier(t *testing.T) {
-	connect := mkConnectionSupplier(0, func(network, address string) (net.Conn, error) {
-		return nil, nil
-	}, func(pattern string) (matches []string, err error) {
-		return []string{""}, nil
-	})
-	readWriter, err := connect()
+func TestNewFactory_Logs(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mr, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lr, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	// the receiver is shared by the pipelines
+	assert.Same(t, mr, lr)
+}
+
+func TestSharedReceiverLifecycle(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	mr, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lr, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// each pipeline starts and shuts down the shared receiver
+	host := &testHost{Host: componenttest.NewNopHost()}
+	require.NoError(t, mr.Start(context.Background(), host))
+	require.NoError(t, lr.Start(context.Background(), &testHost{Host: componenttest.NewNopHost()}))
+	require.Same(t, host, mr.(*processReceiver).host)
+	require.NoError(t, mr.Shutdown(context.Background()))
+	require.NoError(t, lr.Shutdown(context.Background()))
+
+	receiverLock.Lock()
+	_, ok := receivers[cfg]
+	receiverLock.Unlock()
+	require.False(t, ok)
+
+	// the config gets a new receiver once the previous one is shut down
+	r, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
 	require.NoError(t, err)
-	require.Nil(t, readWriter)
+	assert.NotSame(t, mr, r)
+}
+
+// testHost is a distinct host for each of the pipelines.
+type testHost struct {
+	component.Host
+	_ int
+}
+
+func TestNewFactory_InvalidFilter(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.ProcessName = "("
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.Error(t, err)
+}
+
+func TestDialSocket(t *testing.T) {
+	_, err := dialSocket(filepath.Join(t.TempDir(), "dotnet-diagnostic-1-1-socket"))
+	require.Error(t, err)
 }
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
@@ -28,12 +29,22 @@ import (
 // conforms to dotnet.MetricsConsumer so it can be passed into a Parser.
 type Sender struct {
 	next         consumer.Metrics
+	resource     pdata.Resource
+	appender     Appender
 	logger       *zap.Logger
 	prevSendTime time.Time
 }
 
-func NewSender(next consumer.Metrics, logger *zap.Logger) *Sender {
-	return &Sender{next: next, logger: logger}
+// Appender appends metrics not extracted from the counters, e.g. aggregated
+// from the runtime events, to the metrics sent by a Sender.
+type Appender interface {
+	AppendMetrics(ms pdata.MetricSlice, now time.Time)
+}
+
+// NewSender creates a Sender whose metrics have the attributes of the passed
+// in resource, the resource of the dotnet process. The Appender is optional.
+func NewSender(next consumer.Metrics, resource pdata.Resource, appender Appender, logger *zap.Logger) *Sender {
+	return &Sender{next: next, resource: resource, appender: appender, logger: logger}
 }
 
 // Send accepts a slice of dotnet.Metrics, converts them to pdata.Metrics, and
@@ -41,6 +52,11 @@ func NewSender(next consumer.Metrics, logger *zap.Logger) *Sender {
 func (s *Sender) Send(rawMetrics []dotnet.Metric) {
 	now := time.Now()
 	pdm := rawMetricsToPdata(rawMetrics, s.prevSendTime, now)
+	rm := pdm.ResourceMetrics().At(0)
+	s.resource.CopyTo(rm.Resource())
+	if s.appender != nil {
+		s.appender.AppendMetrics(rm.InstrumentationLibraryMetrics().At(0).Metrics(), now)
+	}
 	s.prevSendTime = now
 	err := s.next.ConsumeMetrics(context.Background(), pdm)
 	if err != nil {
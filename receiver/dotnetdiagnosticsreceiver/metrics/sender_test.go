@@ -17,9 +17,11 @@ package metrics
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -27,7 +29,37 @@ import (
 
 func TestSendError(t *testing.T) {
 	observedLogger, logs := observer.New(zapcore.WarnLevel)
-	s := NewSender(consumertest.NewErr(errors.New("")), zap.New(observedLogger))
+	s := NewSender(consumertest.NewErr(errors.New("")), pdata.NewResource(), nil, zap.New(observedLogger))
 	s.Send(nil)
 	require.Equal(t, 1, logs.Len())
 }
+
+func TestSendResource(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.Attributes().InsertInt("process.pid", 1234)
+	sink := &consumertest.MetricsSink{}
+	s := NewSender(sink, resource, nil, zap.NewNop())
+	s.Send(nil)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	pid, ok := sink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get("process.pid")
+	require.True(t, ok)
+	require.EqualValues(t, 1234, pid.IntVal())
+}
+
+type fakeAppender struct{}
+
+func (fakeAppender) AppendMetrics(ms pdata.MetricSlice, now time.Time) {
+	ms.AppendEmpty().SetName("dotnet.gc.duration")
+}
+
+func TestSendAppender(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	s := NewSender(sink, pdata.NewResource(), fakeAppender{}, zap.NewNop())
+	s.Send(nil)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	ms := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	require.Equal(t, "dotnet.gc.duration", ms.At(0).Name())
+}
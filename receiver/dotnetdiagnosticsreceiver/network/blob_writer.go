@@ -75,13 +75,14 @@ func newBlobFileWriter(dir string, maxFiles int, logger *zap.Logger) *blobFileWr
 		logger:    logger,
 		dir:       dir,
 		maxFiles:  maxFiles,
-		mkdir:     os.Mkdir,
+		mkdir:     os.MkdirAll,
 		remove:    os.Remove,
 		writeFile: ioutil.WriteFile,
 	}
 }
 
-// Init() creates the directory that will contain the blob files.
+// Init() creates the directory that will contain the blob files, along with
+// any necessary parents.
 func (w *blobFileWriter) Init() error {
 	err := w.mkdir(w.dir, 0700)
 	if os.IsExist(err) {
@@ -19,6 +19,8 @@ import (
 	"net"
 	"os"
 	"path"
+	"strconv"
+	"strings"
 )
 
 type DialFunc func(network, address string) (net.Conn, error)
@@ -68,3 +70,55 @@ func fullGlob(pid int, tempdir string) string {
 func globPattern(pid int) string {
 	return fmt.Sprintf("dotnet-diagnostic-%d-*-socket", pid)
 }
+
+// Discover returns the paths to the Unix domain socket files of the dotnet
+// processes found in tempdir, by process ID. When a process ID has several
+// socket files, which happens when a stale file of an exited process has the
+// process ID of a new one, the most recent one is returned.
+func Discover(tempdir string, glob GlobFunc) (map[int]string, error) {
+	matches, err := glob(path.Join(tempdir, discoveryGlobPattern))
+	if err != nil {
+		return nil, err
+	}
+	sockets := map[int]string{}
+	keys := map[int]int64{}
+	for _, match := range matches {
+		pid, key, ok := parseSocketFile(path.Base(match))
+		if !ok {
+			continue
+		}
+		if _, found := sockets[pid]; found && key <= keys[pid] {
+			continue
+		}
+		sockets[pid] = match
+		keys[pid] = key
+	}
+	return sockets, nil
+}
+
+// discoveryGlobPattern matches the Unix domain socket files of all the dotnet
+// processes.
+const discoveryGlobPattern = "dotnet-diagnostic-*-*-socket"
+
+// parseSocketFile returns the process ID and the disambiguation key, which is
+// the start time of the process, of a socket file name following the .NET
+// diagnostics naming convention.
+func parseSocketFile(name string) (int, int64, bool) {
+	const prefix, suffix = "dotnet-diagnostic-", "-socket"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return 0, 0, false
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix), "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	pid, err := strconv.Atoi(parts[0])
+	if err != nil || pid <= 0 {
+		return 0, 0, false
+	}
+	key, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return pid, key, true
+}
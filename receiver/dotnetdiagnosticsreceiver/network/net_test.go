@@ -81,3 +81,29 @@ func testNoResultsMatcher(pattern string) (matches []string, err error) {
 func testMultiResultsMatcher(pattern string) (matches []string, err error) {
 	return []string{pattern, "foo"}, nil
 }
+
+func TestDiscover(t *testing.T) {
+	var pattern string
+	sockets, err := Discover("/tmp", func(p string) ([]string, error) {
+		pattern = p
+		return []string{
+			"/tmp/dotnet-diagnostic-1234-100-socket",
+			// stale socket file of an exited process with the same pid
+			"/tmp/dotnet-diagnostic-1234-50-socket",
+			"/tmp/dotnet-diagnostic-5678-200-socket",
+			"/tmp/dotnet-diagnostic-x-200-socket",
+			"/tmp/dotnet-diagnostic-42-socket",
+		}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/dotnet-diagnostic-*-*-socket", pattern)
+	assert.Equal(t, map[int]string{
+		1234: "/tmp/dotnet-diagnostic-1234-100-socket",
+		5678: "/tmp/dotnet-diagnostic-5678-200-socket",
+	}, sockets)
+}
+
+func TestDiscover_Error(t *testing.T) {
+	_, err := Discover("/tmp", testErrorMatcher)
+	assert.Error(t, err)
+}
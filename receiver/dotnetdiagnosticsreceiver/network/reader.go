@@ -62,24 +62,25 @@ type MultiReader interface {
 // which keeps track of the byte count.
 type mReader struct {
 	pr PositionalReader
+	// singleByte is the buffer of ReadByte, it belongs to the reader since
+	// several processes may be read concurrently
+	singleByte []byte
 }
 
 func NewMultiReader(r io.Reader, bw BlobWriter) MultiReader {
 	pr := NewPositionalReader(r, bw)
-	return &mReader{pr: pr}
+	return &mReader{pr: pr, singleByte: make([]byte, 1)}
 }
 
 var _ MultiReader = (*mReader)(nil)
 
-var singleByte = make([]byte, 1)
-
 // ReadByte reads and returns a single byte from the stream
 func (r *mReader) ReadByte() (byte, error) {
-	err := r.Read(singleByte)
+	err := r.Read(r.singleByte)
 	if err != nil {
 		return 0, err
 	}
-	return singleByte[0], nil
+	return r.singleByte[0], nil
 }
 
 // From https://github.com/Microsoft/perfview/blob/master/src/TraceEvent/EventPipe/EventPipeFormat.md
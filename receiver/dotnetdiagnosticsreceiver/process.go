@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnetdiagnosticsreceiver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// processInfo describes a dotnet process, it is used to filter the processes
+// to collect the metrics of and to set the resource attributes of the metrics.
+type processInfo struct {
+	pid int
+	// name is the executable name of the process, "dotnet" for the apps run
+	// with the dotnet command and the app name for the self-contained apps.
+	name string
+	// commandLine is the arguments of the process separated with spaces.
+	commandLine string
+}
+
+// processReader returns the information of a process given its ID.
+type processReader func(pid int) (processInfo, error)
+
+// procfsReader returns a processReader reading the information of the
+// processes from the proc filesystem mounted at procDir, which is only
+// available on Linux.
+func procfsReader(procDir string) processReader {
+	return func(pid int) (processInfo, error) {
+		dir := filepath.Join(procDir, strconv.Itoa(pid))
+		comm, err := ioutil.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			return processInfo{}, err
+		}
+		cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil {
+			return processInfo{}, err
+		}
+		args := bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0})
+		return processInfo{
+			pid:         pid,
+			name:        executableName(dir, string(args[0]), strings.TrimSpace(string(comm))),
+			commandLine: string(bytes.Join(args, []byte{' '})),
+		}, nil
+	}
+}
+
+// executableName returns the base name of the executable of the process in
+// dir. The comm file of the process is truncated to 15 characters, so the
+// name is read from the exe link, which can't be read for the processes of
+// other users, then from the first argument of the command line, and only
+// then from comm.
+func executableName(dir string, arg0 string, comm string) string {
+	if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+		return filepath.Base(strings.TrimSuffix(exe, " (deleted)"))
+	}
+	if arg0 != "" {
+		return filepath.Base(arg0)
+	}
+	return comm
+}
+
+// resource returns the resource of the metrics of the process.
+func (p processInfo) resource() pdata.Resource {
+	r := pdata.NewResource()
+	attrs := r.Attributes()
+	attrs.InsertInt(conventions.AttributeProcessID, int64(p.pid))
+	if p.name != "" {
+		attrs.InsertString(conventions.AttributeProcessExecutableName, p.name)
+	}
+	if p.commandLine != "" {
+		attrs.InsertString(conventions.AttributeProcessCommandLine, p.commandLine)
+	}
+	return r
+}
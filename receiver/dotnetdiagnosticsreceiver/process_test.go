@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnetdiagnosticsreceiver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestProcfsReader(t *testing.T) {
+	procDir := t.TempDir()
+	dir := filepath.Join(procDir, "42")
+	require.NoError(t, os.Mkdir(dir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte("dotnet\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte("dotnet\x00MyApp.dll\x00--verbose\x00"), 0600))
+
+	read := procfsReader(procDir)
+	info, err := read(42)
+	require.NoError(t, err)
+	assert.Equal(t, processInfo{pid: 42, name: "dotnet", commandLine: "dotnet MyApp.dll --verbose"}, info)
+
+	_, err = read(43)
+	assert.Error(t, err)
+}
+
+func TestProcfsReader_ExecutableName(t *testing.T) {
+	procDir := t.TempDir()
+	dir := filepath.Join(procDir, "42")
+	require.NoError(t, os.Mkdir(dir, 0700))
+	// comm is truncated to 15 characters
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte("MyLongApplicati\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte("./MyLongApplicationName\x00--verbose\x00"), 0600))
+
+	read := procfsReader(procDir)
+	info, err := read(42)
+	require.NoError(t, err)
+	assert.Equal(t, "MyLongApplicationName", info.name)
+
+	require.NoError(t, os.Symlink("/app/MyLongApplicationName (deleted)", filepath.Join(dir, "exe")))
+	info, err = read(42)
+	require.NoError(t, err)
+	assert.Equal(t, "MyLongApplicationName", info.name)
+
+	// the command line of zombie processes is empty
+	require.NoError(t, os.Remove(filepath.Join(dir, "exe")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), nil, 0600))
+	info, err = read(42)
+	require.NoError(t, err)
+	assert.Equal(t, "MyLongApplicati", info.name)
+}
+
+func TestProcessInfoResource(t *testing.T) {
+	attrs := processInfo{pid: 42, name: "dotnet", commandLine: "dotnet MyApp.dll"}.resource().Attributes()
+	assert.Equal(t, 3, attrs.Len())
+	pid, _ := attrs.Get(conventions.AttributeProcessID)
+	assert.EqualValues(t, 42, pid.IntVal())
+	name, _ := attrs.Get(conventions.AttributeProcessExecutableName)
+	assert.Equal(t, "dotnet", name.StringVal())
+	cmd, _ := attrs.Get(conventions.AttributeProcessCommandLine)
+	assert.Equal(t, "dotnet MyApp.dll", cmd.StringVal())
+
+	attrs = processInfo{pid: 42}.resource().Attributes()
+	assert.Equal(t, 1, attrs.Len())
+}
@@ -17,25 +17,37 @@ package dotnetdiagnosticsreceiver
 import (
 	"context"
 	"io"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/events"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
+// receiver collects the metrics of a single dotnet process.
 type receiver struct {
-	nextConsumer consumer.Metrics
-	connect      connectionSupplier
-	counters     []string
-	intervalSec  int
-	logger       *zap.Logger
+	nextConsumer    consumer.Metrics
+	nextLogs        consumer.Logs
+	connect         connectionSupplier
+	counters        []string
+	runtimeKeywords dotnet.RuntimeKeywords
+	intervalSec     int
+	resource        pdata.Resource
+	logger          *zap.Logger
 
 	bw     network.BlobWriter
+	conn   io.ReadWriter
 	cancel context.CancelFunc
+	// done is closed when the receiver stops parsing the metrics of the
+	// process, because of a connection or parsing error or of a shutdown.
+	done chan struct{}
 }
 
 type connectionSupplier func() (io.ReadWriter, error)
@@ -51,24 +63,88 @@ func NewReceiver(
 	logger *zap.Logger,
 	bw network.BlobWriter,
 ) (component.MetricsReceiver, error) {
+	return newReceiver(mc, nil, connect, counters, 0, intervalSec, pdata.NewResource(), logger, bw), nil
+}
+
+// newReceiver creates a receiver which also requests the runtime events of
+// the given keywords, sending their metrics along with the counters and their
+// exceptions to the optional logs consumer. The metrics consumer is optional
+// too, when the receiver is only used in a logs pipeline.
+func newReceiver(
+	mc consumer.Metrics,
+	lc consumer.Logs,
+	connect connectionSupplier,
+	counters []string,
+	runtimeKeywords dotnet.RuntimeKeywords,
+	intervalSec int,
+	resource pdata.Resource,
+	logger *zap.Logger,
+	bw network.BlobWriter,
+) *receiver {
 	return &receiver{
-		nextConsumer: mc,
-		connect:      connect,
-		counters:     counters,
-		intervalSec:  intervalSec,
-		logger:       logger,
-		bw:           bw,
-	}, nil
+		nextConsumer:    mc,
+		nextLogs:        lc,
+		connect:         connect,
+		counters:        counters,
+		runtimeKeywords: runtimeKeywords,
+		intervalSec:     intervalSec,
+		resource:        resource,
+		logger:          logger,
+		bw:              bw,
+		done:            make(chan struct{}),
+	}
 }
 
+// Start connects to the dotnet process and requests its metrics. The
+// handshake with the process is interrupted when ctx is done.
 func (r *receiver) Start(ctx context.Context, host component.Host) error {
 	conn, err := r.connect()
 	if err != nil {
 		return err
 	}
+	r.conn = conn
+
+	handshakeDone := r.watchHandshake(ctx)
+	err = r.start(handshakeDone)
+	if err != nil {
+		handshakeDone()
+		r.closeConn()
+	}
+	return err
+}
+
+// watchHandshake interrupts the reads and writes of the handshake with the
+// process when ctx is done, e.g. on its deadline when the process doesn't
+// respond or its socket file is stale. The returned function stops watching
+// ctx once the handshake is over.
+func (r *receiver) watchHandshake(ctx context.Context) func() {
+	dc, ok := r.conn.(interface{ SetDeadline(time.Time) error })
+	if !ok || ctx.Done() == nil {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			_ = dc.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-stopped
+			_ = dc.SetDeadline(time.Time{})
+		})
+	}
+}
 
-	w := dotnet.NewRequestWriter(conn, r.intervalSec, r.counters...)
-	err = w.SendRequest()
+func (r *receiver) start(handshakeDone func()) error {
+	w := dotnet.NewRequestWriter(r.conn, r.intervalSec, r.runtimeKeywords, r.counters...)
+	err := w.SendRequest()
 	if err != nil {
 		return err
 	}
@@ -78,8 +154,19 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
-	sender := metrics.NewSender(r.nextConsumer, r.logger)
-	p := dotnet.NewParser(conn, sender.Send, r.bw, r.logger)
+	var appender metrics.Appender
+	var consumeEvents dotnet.EventsConsumer
+	if r.runtimeKeywords != 0 {
+		recorder := events.NewRecorder(r.nextLogs, r.resource, r.logger)
+		appender = recorder
+		consumeEvents = recorder.Record
+	}
+	consumeMetrics := func([]dotnet.Metric) {}
+	if r.nextConsumer != nil {
+		sender := metrics.NewSender(r.nextConsumer, r.resource, appender, r.logger)
+		consumeMetrics = sender.Send
+	}
+	p := dotnet.NewParser(r.conn, consumeMetrics, consumeEvents, r.bw, r.logger)
 
 	err = p.ParseIPC()
 	if err != nil {
@@ -90,10 +177,13 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 	if err != nil {
 		return err
 	}
+	handshakeDone()
 
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
 	go func() {
-		ctx, r.cancel = context.WithCancel(context.Background())
-		err = p.ParseAll(ctx)
+		defer close(r.done)
+		err := p.ParseAll(ctx)
 		if err != nil {
 			r.logger.Error("parseAll error", zap.Error(err))
 		}
@@ -105,5 +195,15 @@ func (r *receiver) Shutdown(context.Context) error {
 	if r.cancel != nil {
 		r.cancel()
 	}
+	// closing the connection unblocks the parser waiting for metrics
+	r.closeConn()
 	return nil
 }
+
+func (r *receiver) closeConn() {
+	if c, ok := r.conn.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			r.logger.Debug("failed to close the connection", zap.Error(err))
+		}
+	}
+}
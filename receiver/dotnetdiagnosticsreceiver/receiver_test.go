@@ -15,6 +15,7 @@
 package dotnetdiagnosticsreceiver
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -25,9 +26,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
@@ -55,6 +58,36 @@ func TestReceiverBlobData(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReceiverBlobData_RuntimeEvents(t *testing.T) {
+	data, err := network.ReadBlobData("testdata", 16)
+	require.NoError(t, err)
+	rw := network.NewBlobReader(data)
+	ctx := context.Background()
+	// only used in a logs pipeline
+	r := newReceiver(
+		nil,
+		consumertest.NewNop(),
+		func() (io.ReadWriter, error) {
+			return rw, nil
+		},
+		nil,
+		dotnet.RuntimeKeywordException,
+		1,
+		pdata.NewResource(),
+		zap.NewNop(),
+		&network.NopBlobWriter{},
+	)
+	err = r.Start(ctx, componenttest.NewNopHost())
+	require.NoError(t, err)
+	<-rw.Gate()
+	err = r.Shutdown(ctx)
+	require.NoError(t, err)
+
+	var provider bytes.Buffer
+	network.WriteUTF16String(&provider, dotnet.RuntimeProviderName)
+	assert.True(t, bytes.Contains(rw.WriteBuf, provider.Bytes()))
+}
+
 func TestReceiverBlobData_ParsingError(t *testing.T) {
 	data, err := network.ReadBlobData("testdata", 16)
 	require.NoError(t, err)
@@ -16,8 +16,11 @@ package influxdbreceiver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -73,11 +76,13 @@ func (r *metricsReceiver) Start(_ context.Context, host component.Host) error {
 	}
 
 	nr := mux.NewRouter()
-	nr.HandleFunc("/write", r.handleWrite)        // InfluxDB 1.x
-	nr.HandleFunc("/api/v2/write", r.handleWrite) // InfluxDB 2.x
+	nr.HandleFunc("/write", r.handleWriteV1).Methods(http.MethodPost)        // InfluxDB 1.x
+	nr.HandleFunc("/api/v2/write", r.handleWriteV2).Methods(http.MethodPost) // InfluxDB 2.x
+	nr.HandleFunc("/ping", r.handlePing).Methods(http.MethodGet, http.MethodHead)
+	nr.HandleFunc("/query", r.handleQuery).Methods(http.MethodGet, http.MethodPost) // InfluxDB 1.x, database creation only
 
 	r.wg.Add(1)
-	r.server = r.httpServerSettings.ToServer(nr)
+	r.server = r.httpServerSettings.ToServer(nr, confighttp.WithErrorHandler(handleDecompressionError))
 	go func() {
 		defer r.wg.Done()
 		if err := r.server.Serve(ln); err != nil && err != http.ErrServerClosed {
@@ -96,28 +101,187 @@ func (r *metricsReceiver) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-const defaultPrecision = lineprotocol.Nanosecond
+const (
+	// influxDBVersion is reported in the X-Influxdb-Version header, clients
+	// like Telegraf only use it for logging.
+	influxDBVersion = "otelcol-influxdbreceiver"
 
-var precisions = map[string]lineprotocol.Precision{
-	lineprotocol.Nanosecond.String():  lineprotocol.Nanosecond,
-	lineprotocol.Microsecond.String(): lineprotocol.Microsecond,
-	lineprotocol.Millisecond.String(): lineprotocol.Millisecond,
-	lineprotocol.Second.String():      lineprotocol.Second,
+	// Resource attributes set from the write query parameters.
+	attributeInfluxDBOrg    = "influxdb.org"
+	attributeInfluxDBBucket = "influxdb.bucket"
+)
+
+// apiVersion selects the error format of the responses, since InfluxDB 1.x
+// and 2.x clients expect different JSON bodies.
+type apiVersion int
+
+const (
+	apiV1 apiVersion = iota
+	apiV2
+)
+
+// writeParams holds the query parameters of a write request, normalized
+// across InfluxDB 1.x and 2.x.
+type writeParams struct {
+	precision precision
+	org       string
+	bucket    string
+}
+
+// precision is the unit of the line protocol timestamps. InfluxDB 1.x also
+// accepts minutes and hours, which are decoded as seconds and scaled.
+type precision struct {
+	lp    lineprotocol.Precision
+	scale time.Duration
+}
+
+var defaultPrecision = precision{lp: lineprotocol.Nanosecond}
+
+// precisions has all the precision values accepted by either InfluxDB 1.x
+// or 2.x, so both write endpoints behave consistently.
+var precisions = map[string]precision{
+	"n":  {lp: lineprotocol.Nanosecond},
+	"ns": {lp: lineprotocol.Nanosecond},
+	"u":  {lp: lineprotocol.Microsecond},
+	"us": {lp: lineprotocol.Microsecond},
+	"µ":  {lp: lineprotocol.Microsecond},
+	"µs": {lp: lineprotocol.Microsecond},
+	"ms": {lp: lineprotocol.Millisecond},
+	"s":  {lp: lineprotocol.Second},
+	"m":  {lp: lineprotocol.Second, scale: time.Minute},
+	"h":  {lp: lineprotocol.Second, scale: time.Hour},
+}
+
+func (p precision) time(dec *lineprotocol.Decoder) (time.Time, error) {
+	ts, err := dec.Time(p.lp, time.Time{})
+	if err != nil || p.scale == 0 || ts.IsZero() {
+		return ts, err
+	}
+	return time.Unix(0, 0).Add(time.Duration(ts.Unix()) * p.scale), nil
 }
 
-func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+func parsePrecision(query url.Values) (precision, error) {
+	precisionStr := query.Get("precision")
+	if precisionStr == "" {
+		return defaultPrecision, nil
+	}
+	p, ok := precisions[precisionStr]
+	if !ok {
+		return precision{}, fmt.Errorf("unrecognized precision '%s'", precisionStr)
+	}
+	return p, nil
+}
+
+// handleWriteV1 handles InfluxDB 1.x writes. The database and retention
+// policy are mapped to the bucket the same way InfluxDB 2.x maps them in its
+// 1.x compatibility API: "db/rp", or only "db" when rp is not set.
+func (r *metricsReceiver) handleWriteV1(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	p, err := parsePrecision(query)
+	if err != nil {
+		writeError(w, apiV1, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bucket := query.Get("db")
+	if rp := query.Get("rp"); bucket != "" && rp != "" {
+		bucket += "/" + rp
+	}
+
+	r.handleWrite(w, req, apiV1, writeParams{precision: p, bucket: bucket})
+}
+
+// handleWriteV2 handles InfluxDB 2.x writes.
+func (r *metricsReceiver) handleWriteV2(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	p, err := parsePrecision(query)
+	if err != nil {
+		writeError(w, apiV2, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	org := query.Get("org")
+	if org == "" {
+		org = query.Get("orgID")
+	}
+
+	r.handleWrite(w, req, apiV2, writeParams{precision: p, org: org, bucket: query.Get("bucket")})
+}
+
+// handlePing answers the InfluxDB health check used by clients before writing.
+func (r *metricsReceiver) handlePing(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("X-Influxdb-Version", influxDBVersion)
+	w.Header().Set("X-Influxdb-Build", "OSS")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleQuery acknowledges the "CREATE DATABASE" statements sent by InfluxDB
+// 1.x clients, like Telegraf, before they start writing. Any other query is
+// rejected since the receiver does not store data.
+func (r *metricsReceiver) handleQuery(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		writeError(w, apiV1, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	q := strings.TrimSpace(req.Form.Get("q"))
+	if !strings.HasPrefix(strings.ToUpper(q), "CREATE DATABASE") {
+		writeError(w, apiV1, http.StatusBadRequest, "only CREATE DATABASE queries are supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Influxdb-Version", influxDBVersion)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"results":[{"statement_id":0}]}`))
+}
+
+// handleDecompressionError reports failures to decompress request bodies
+// with the error format of the requested API.
+func handleDecompressionError(w http.ResponseWriter, req *http.Request, errMsg string, statusCode int) {
+	version := apiV1
+	if strings.HasPrefix(req.URL.Path, "/api/v2/") {
+		version = apiV2
+	}
+	writeError(w, version, statusCode, fmt.Sprintf("failed to decompress body: %s", errMsg))
+}
+
+// writeError writes the error body expected by the clients of each API version.
+func writeError(w http.ResponseWriter, version apiVersion, status int, msg string) {
+	var body interface{}
+	switch version {
+	case apiV2:
+		code := "invalid"
+		if status >= http.StatusInternalServerError {
+			code = "internal error"
+		}
+		body = struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}{Code: code, Message: msg}
+	default:
+		body = struct {
+			Error string `json:"error"`
+		}{Error: msg}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Influxdb-Error", msg)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request, version apiVersion, params writeParams) {
 	defer func() {
 		_ = req.Body.Close()
 	}()
 
-	precision := defaultPrecision
-	if precisionStr := req.URL.Query().Get("precision"); precisionStr != "" {
-		var ok bool
-		if precision, ok = precisions[precisionStr]; !ok {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "unrecognized precision '%s'", precisionStr)
-			return
-		}
+	// gzip and deflate bodies were already decompressed by the HTTP server,
+	// see confighttp.HTTPServerSettings.ToServer.
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		writeError(w, version, http.StatusUnsupportedMediaType,
+			fmt.Sprintf("unsupported content encoding '%s'", encoding))
+		return
 	}
 
 	batch := r.converter.NewBatch()
@@ -128,8 +292,7 @@ func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request)
 	for line := 0; lpDecoder.Next(); line++ {
 		measurement, err := lpDecoder.Measurement()
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse measurement on line %d", line)
+			writeError(w, version, http.StatusBadRequest, fmt.Sprintf("failed to parse measurement on line %d", line))
 			return
 		}
 
@@ -138,8 +301,7 @@ func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request)
 			tags[string(k)] = string(vTag)
 		}
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse tag on line %d", line)
+			writeError(w, version, http.StatusBadRequest, fmt.Sprintf("failed to parse tag on line %d", line))
 			return
 		}
 
@@ -148,53 +310,65 @@ func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request)
 			fields[string(k)] = vField.Interface()
 		}
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse field on line %d", line)
+			writeError(w, version, http.StatusBadRequest, fmt.Sprintf("failed to parse field on line %d", line))
 			return
 		}
 
-		ts, err := lpDecoder.Time(precision, time.Time{})
+		ts, err := params.precision.time(lpDecoder)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse timestamp on line %d", line)
+			writeError(w, version, http.StatusBadRequest, fmt.Sprintf("failed to parse timestamp on line %d", line))
 			return
 		}
 
 		if err = lpDecoder.Err(); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to parse line: %s", err.Error())
+			writeError(w, version, http.StatusBadRequest, fmt.Sprintf("failed to parse line: %s", err.Error()))
 			return
 		}
 
 		err = batch.AddPoint(string(measurement), tags, fields, ts, common.InfluxMetricValueTypeUntyped)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = fmt.Fprintf(w, "failed to append to the batch")
+			writeError(w, version, http.StatusBadRequest, "failed to append to the batch")
 			return
 		}
 	}
 
 	b, err := batch.ToProtoBytes()
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = fmt.Fprintf(w, "failed to convert batch to protobuf bytes")
+		writeError(w, version, http.StatusBadRequest, "failed to convert batch to protobuf bytes")
 		return
 	}
 	md, err := pdata.MetricsFromOtlpProtoBytes(b)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = fmt.Fprintf(w, "failed to convert protobuf bytes to OTLP object")
+		writeError(w, version, http.StatusBadRequest, "failed to convert protobuf bytes to OTLP object")
 		return
 	}
+	setResourceAttributes(md, params)
 	if err = r.nextConsumer.ConsumeMetrics(req.Context(), md); err != nil {
 		if consumererror.IsPermanent(err) {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, version, http.StatusBadRequest, err.Error())
 		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, version, http.StatusInternalServerError, err.Error())
 		}
 		r.logger.Debug("failed to pass metrics to next consumer: %s", err.Error())
 		return
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setResourceAttributes records the org and bucket the client wrote to.
+func setResourceAttributes(md pdata.Metrics, params writeParams) {
+	if params.org == "" && params.bucket == "" {
+		return
+	}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		attrs := rms.At(i).Resource().Attributes()
+		if params.org != "" {
+			attrs.UpsertString(attributeInfluxDBOrg, params.org)
+		}
+		if params.bucket != "" {
+			attrs.UpsertString(attributeInfluxDBBucket, params.bucket)
+		}
+	}
 }
@@ -0,0 +1,217 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func startReceiver(t *testing.T) (string, *consumertest.MetricsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+
+	r, err := newMetricsReceiver(cfg, newZapInfluxLogger(zap.NewNop()), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+
+	return "http://" + cfg.Endpoint, sink
+}
+
+func TestWriteEndpoints(t *testing.T) {
+	baseURL, sink := startReceiver(t)
+
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		gzip          bool
+		wantAttrs     map[string]string
+		wantTimestamp time.Time
+	}{
+		{
+			name:          "v1_db_rp_precision",
+			path:          "/write?db=telegraf&rp=autogen&precision=s",
+			body:          "cpu_temp,foo=bar gauge=87.332 1622548800",
+			wantAttrs:     map[string]string{attributeInfluxDBBucket: "telegraf/autogen"},
+			wantTimestamp: time.Unix(1622548800, 0),
+		},
+		{
+			name:          "v1_gzip_minutes",
+			path:          "/write?db=telegraf&precision=m",
+			body:          "cpu_temp,foo=bar gauge=87.332 27042480",
+			gzip:          true,
+			wantAttrs:     map[string]string{attributeInfluxDBBucket: "telegraf"},
+			wantTimestamp: time.Unix(1622548800, 0),
+		},
+		{
+			name:          "v2_org_bucket",
+			path:          "/api/v2/write?org=myorg&bucket=mybucket&precision=us",
+			body:          "cpu_temp,foo=bar gauge=87.332 1622548800000000",
+			wantAttrs:     map[string]string{attributeInfluxDBOrg: "myorg", attributeInfluxDBBucket: "mybucket"},
+			wantTimestamp: time.Unix(1622548800, 0),
+		},
+		{
+			name:          "v2_gzip",
+			path:          "/api/v2/write?org=myorg&bucket=mybucket&precision=ms",
+			body:          "cpu_temp,foo=bar gauge=87.332 1622548800000",
+			gzip:          true,
+			wantAttrs:     map[string]string{attributeInfluxDBOrg: "myorg", attributeInfluxDBBucket: "mybucket"},
+			wantTimestamp: time.Unix(1622548800, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink.Reset()
+
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.gzip {
+				var buf bytes.Buffer
+				gw := gzip.NewWriter(&buf)
+				_, err := gw.Write([]byte(tt.body))
+				require.NoError(t, err)
+				require.NoError(t, gw.Close())
+				body = &buf
+			}
+
+			req, err := http.NewRequest(http.MethodPost, baseURL+tt.path, body)
+			require.NoError(t, err)
+			if tt.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			rm := mds[0].ResourceMetrics().At(0)
+			attrs := map[string]string{}
+			rm.Resource().Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+				if k == attributeInfluxDBOrg || k == attributeInfluxDBBucket {
+					attrs[k] = v.StringVal()
+				}
+				return true
+			})
+			assert.Equal(t, tt.wantAttrs, attrs)
+
+			metric := rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+			require.Equal(t, pdata.MetricDataTypeDoubleGauge, metric.DataType())
+			assert.Equal(t, pdata.TimestampFromTime(tt.wantTimestamp), metric.DoubleGauge().DataPoints().At(0).Timestamp())
+		})
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	baseURL, _ := startReceiver(t)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "v1_bad_precision",
+			path:       "/write?precision=d",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"unrecognized precision 'd'"}`,
+		},
+		{
+			name:       "v2_bad_precision",
+			path:       "/api/v2/write?precision=d",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"invalid","message":"unrecognized precision 'd'"}`,
+		},
+		{
+			name:       "v2_bad_gzip",
+			path:       "/api/v2/write",
+			header:     map[string]string{"Content-Encoding": "gzip"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"invalid","message":"failed to decompress body: gzip: invalid header"}`,
+		},
+		{
+			name:       "v1_unsupported_encoding",
+			path:       "/write",
+			header:     map[string]string{"Content-Encoding": "br"},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   `{"error":"unsupported content encoding 'br'"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, baseURL+tt.path, strings.NewReader("cpu_temp gauge=1"))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			b, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantBody, string(b))
+		})
+	}
+}
+
+func TestPingAndQuery(t *testing.T) {
+	baseURL, _ := startReceiver(t)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		req, err := http.NewRequest(method, baseURL+"/ping", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode, method)
+		assert.NotEmpty(t, resp.Header.Get("X-Influxdb-Version"), method)
+	}
+
+	resp, err := http.Post(baseURL+"/query", "application/x-www-form-urlencoded",
+		strings.NewReader(fmt.Sprintf("q=%s", `CREATE+DATABASE+"telegraf"`)))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(baseURL + "/query?q=SELECT+*+FROM+cpu")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
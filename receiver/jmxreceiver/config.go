@@ -21,11 +21,25 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
+const (
+	// modeJAR runs the JMX Metric Gatherer JAR in a Java subprocess.
+	modeJAR = "jar"
+	// modeJolokia reads the MBeans of the target system from the Jolokia REST
+	// API of the Endpoint.
+	modeJolokia = "jolokia"
+)
+
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"`
+	// The collection mode, either `jar` (the default) to run the JMX Metric Gatherer JAR, or `jolokia` to read the
+	// MBeans of the TargetSystem from the Jolokia REST API at Endpoint, without a Java runtime.
+	Mode string `mapstructure:"mode"`
+	// The TLS settings of the Jolokia REST API, in the `jolokia` mode.
+	JolokiaTLS configtls.TLSClientSetting `mapstructure:"jolokia_tls"`
 	// The path for the JMX Metric Gatherer uber JAR (/opt/opentelemetry-java-contrib-jmx-metrics.jar by default).
 	JARPath string `mapstructure:"jar_path"`
 	// The Service URL or host:port for the target coerced to one of form: service:jmx:rmi:///jndi/rmi://<host>:<port>/jmxrmi.
@@ -102,6 +116,14 @@ func (c *Config) parseProperties() []string {
 }
 
 func (c *Config) validate() error {
+	switch c.Mode {
+	case "", modeJAR:
+	case modeJolokia:
+		return c.validateJolokia()
+	default:
+		return fmt.Errorf("%v `mode` must be %q or %q: %q", c.ID(), modeJAR, modeJolokia, c.Mode)
+	}
+
 	var missingFields []string
 	if c.Endpoint == "" {
 		missingFields = append(missingFields, "`endpoint`")
@@ -127,3 +149,45 @@ func (c *Config) validate() error {
 
 	return nil
 }
+
+func (c *Config) validateJolokia() error {
+	var missingFields []string
+	if c.Endpoint == "" {
+		missingFields = append(missingFields, "`endpoint`")
+	}
+	if c.TargetSystem == "" {
+		missingFields = append(missingFields, "`target_system`")
+	}
+	if missingFields != nil {
+		baseMsg := fmt.Sprintf("%v missing required field", c.ID())
+		if len(missingFields) > 1 {
+			baseMsg += "s"
+		}
+		return fmt.Errorf("%v: %v", baseMsg, strings.Join(missingFields, ", "))
+	}
+
+	if c.GroovyScript != "" {
+		return fmt.Errorf("%v `groovy_script` is not supported in the %q mode", c.ID(), modeJolokia)
+	}
+	for _, system := range c.targetSystems() {
+		if _, ok := jolokiaTargetSystems[system]; !ok {
+			return fmt.Errorf("%v `target_system` is not supported in the %q mode: %q", c.ID(), modeJolokia, system)
+		}
+	}
+
+	if c.CollectionInterval <= 0 {
+		return fmt.Errorf("%v `interval` must be positive: %vms", c.ID(), c.CollectionInterval.Milliseconds())
+	}
+	return nil
+}
+
+// targetSystems returns the comma-separated target systems.
+func (c *Config) targetSystems() []string {
+	var systems []string
+	for _, system := range strings.Split(c.TargetSystem, ",") {
+		if system = strings.TrimSpace(system); system != "" {
+			systems = append(systems, system)
+		}
+	}
+	return systems
+}
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
@@ -39,7 +40,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 6)
+	assert.Equal(t, len(cfg.Receivers), 7)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)].(*Config)
 	require.NoError(t, configcheck.ValidateConfig(r0))
@@ -165,4 +166,86 @@ func TestLoadConfig(t *testing.T) {
 	err = r5.validate()
 	require.Error(t, err)
 	assert.Equal(t, "jmx/invalidotlptimeout `otlp.timeout` must be positive: -100ms", err.Error())
+
+	r6 := cfg.Receivers[config.NewIDWithName(typeStr, "jolokia")].(*Config)
+	require.NoError(t, configcheck.ValidateConfig(r6))
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "jolokia")),
+			Mode:             "jolokia",
+			JolokiaTLS: configtls.TLSClientSetting{
+				InsecureSkipVerify: true,
+			},
+			JARPath:            "/opt/opentelemetry-java-contrib-jmx-metrics.jar",
+			Endpoint:           "http://myendpoint:8778/jolokia",
+			TargetSystem:       "jvm,kafka",
+			CollectionInterval: 10 * time.Second,
+			Username:           "myusername",
+			Password:           "mypassword",
+			OTLPExporterConfig: otlpExporterConfig{
+				Endpoint: "0.0.0.0:0",
+				TimeoutSettings: exporterhelper.TimeoutSettings{
+					Timeout: 5 * time.Second,
+				},
+			},
+		}, r6)
+	require.NoError(t, r6.validate())
+	assert.Equal(t, []string{"jvm", "kafka"}, r6.targetSystems())
+}
+
+func TestValidateJolokia(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "missing fields",
+			modify: func(c *Config) {},
+			err:    "jmx missing required fields: `endpoint`, `target_system`",
+		},
+		{
+			name: "groovy script",
+			modify: func(c *Config) {
+				c.Endpoint = "http://localhost:8778/jolokia"
+				c.TargetSystem = "jvm"
+				c.GroovyScript = "mygroovyscriptpath"
+			},
+			err: "jmx `groovy_script` is not supported in the \"jolokia\" mode",
+		},
+		{
+			name: "unknown target system",
+			modify: func(c *Config) {
+				c.Endpoint = "http://localhost:8778/jolokia"
+				c.TargetSystem = "jvm,hadoop"
+			},
+			err: "jmx `target_system` is not supported in the \"jolokia\" mode: \"hadoop\"",
+		},
+		{
+			name: "zero interval",
+			modify: func(c *Config) {
+				c.Endpoint = "http://localhost:8778/jolokia"
+				c.TargetSystem = "cassandra"
+				c.CollectionInterval = 0
+			},
+			err: "jmx `interval` must be positive: 0ms",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Mode = modeJolokia
+			tt.modify(cfg)
+			err := cfg.validate()
+			require.Error(t, err)
+			assert.Equal(t, tt.err, err.Error())
+		})
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Mode = "agent"
+	err := cfg.validate()
+	require.Error(t, err)
+	assert.Equal(t, "jmx `mode` must be \"jar\" or \"jolokia\": \"agent\"", err.Error())
 }
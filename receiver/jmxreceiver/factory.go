@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
 const (
@@ -61,5 +62,33 @@ func createReceiver(
 	if err := jmxConfig.validate(); err != nil {
 		return nil, err
 	}
+	if jmxConfig.Mode == modeJolokia {
+		return newJolokiaReceiver(params, jmxConfig, consumer)
+	}
 	return newJMXMetricReceiver(params, jmxConfig, consumer), nil
 }
+
+// newJolokiaReceiver creates a receiver scraping the Jolokia REST API on the
+// collection interval.
+func newJolokiaReceiver(
+	params component.ReceiverCreateParams,
+	cfg *Config,
+	consumer consumer.Metrics,
+) (component.MetricsReceiver, error) {
+	s := newJolokiaScraper(params.Logger, cfg)
+	return scraperhelper.NewScraperControllerReceiver(
+		&scraperhelper.ScraperControllerSettings{
+			ReceiverSettings:   cfg.ReceiverSettings,
+			CollectionInterval: cfg.CollectionInterval,
+		},
+		params.Logger,
+		consumer,
+		scraperhelper.AddResourceMetricsScraper(
+			scraperhelper.NewResourceMetricsScraper(
+				cfg.ID(),
+				s.scrape,
+				scraperhelper.WithStart(s.start),
+			),
+		),
+	)
+}
@@ -0,0 +1,267 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+const jolokiaInstrumentationLibName = "otelcol/jmx"
+
+// jolokiaRequest is a read request of the bulk requests of the Jolokia
+// protocol.
+// https://jolokia.org/reference/html/protocol.html#read
+type jolokiaRequest struct {
+	Type      string   `json:"type"`
+	MBean     string   `json:"mbean"`
+	Attribute []string `json:"attribute"`
+}
+
+type jolokiaResponse struct {
+	Value  interface{} `json:"value"`
+	Status int         `json:"status"`
+	Error  string      `json:"error"`
+}
+
+// jolokiaScraper reads the MBeans of the target systems from the Jolokia REST
+// API, replacing the JMX Metric Gatherer in the jolokia mode.
+type jolokiaScraper struct {
+	logger    *zap.Logger
+	config    *Config
+	client    *http.Client
+	startTime pdata.Timestamp
+
+	metrics  []mbeanMetric
+	requests []jolokiaRequest
+	// the metrics of the requests, by index of the requests
+	requestMetrics [][]mbeanMetric
+}
+
+func newJolokiaScraper(logger *zap.Logger, config *Config) *jolokiaScraper {
+	s := &jolokiaScraper{
+		logger: logger,
+		config: config,
+	}
+	for _, system := range config.targetSystems() {
+		s.metrics = append(s.metrics, jolokiaTargetSystems[system]...)
+	}
+
+	// a single request per MBean, reading all its mapped attributes
+	requestIndex := map[string]int{}
+	for _, metric := range s.metrics {
+		attribute := strings.SplitN(metric.attribute, ".", 2)[0]
+		i, ok := requestIndex[metric.mbean]
+		if !ok {
+			i = len(s.requests)
+			requestIndex[metric.mbean] = i
+			s.requests = append(s.requests, jolokiaRequest{Type: "read", MBean: metric.mbean})
+			s.requestMetrics = append(s.requestMetrics, nil)
+		}
+		if !containsString(s.requests[i].Attribute, attribute) {
+			s.requests[i].Attribute = append(s.requests[i].Attribute, attribute)
+		}
+		s.requestMetrics[i] = append(s.requestMetrics[i], metric)
+	}
+	return s
+}
+
+func (s *jolokiaScraper) start(context.Context, component.Host) error {
+	tlsConfig, err := s.config.JolokiaTLS.LoadTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the TLS config of the Jolokia REST API: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	s.client = &http.Client{
+		Transport: transport,
+		Timeout:   s.config.CollectionInterval,
+	}
+	s.startTime = pdata.TimestampFromTime(time.Now())
+	return nil
+}
+
+func (s *jolokiaScraper) scrape(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	responses, err := s.read(ctx)
+	if err != nil {
+		s.logger.Error("Failed to read the MBeans from Jolokia", zap.Error(err))
+		return pdata.NewResourceMetricsSlice(), err
+	}
+
+	rms := pdata.NewResourceMetricsSlice()
+	ilm := rms.AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(jolokiaInstrumentationLibName)
+	now := pdata.TimestampFromTime(time.Now())
+
+	var errs scrapererror.ScrapeErrors
+	for i, response := range responses {
+		request := s.requests[i]
+		if response.Status != http.StatusOK {
+			errs.AddPartial(len(s.requestMetrics[i]), fmt.Errorf("failed to read %s: %d %s", request.MBean, response.Status, response.Error))
+			continue
+		}
+		attributes := mbeanAttributes(request.MBean, response.Value)
+		for _, metric := range s.requestMetrics[i] {
+			s.addMetric(ilm.Metrics(), metric, attributes, now)
+		}
+	}
+	return rms, errs.Combine()
+}
+
+// read sends the bulk request of the MBeans to Jolokia, and returns the
+// responses in the order of the requests.
+func (s *jolokiaScraper) read(ctx context.Context) ([]jolokiaResponse, error) {
+	body, err := json.Marshal(s.requests)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Username != "" {
+		req.SetBasicAuth(s.config.Username, s.config.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var responses []jolokiaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+		return nil, err
+	}
+	if len(responses) != len(s.requests) {
+		return nil, fmt.Errorf("expected %d responses, got %d", len(s.requests), len(responses))
+	}
+	return responses, nil
+}
+
+// mbeanAttributes returns the attributes by MBean name of the value of a read
+// response, which for an MBean pattern is keyed by the names of the matching
+// MBeans.
+func mbeanAttributes(mbean string, value interface{}) map[string]map[string]interface{} {
+	values, _ := value.(map[string]interface{})
+	if !strings.ContainsAny(mbean, "*?") {
+		return map[string]map[string]interface{}{mbean: values}
+	}
+	attributes := make(map[string]map[string]interface{}, len(values))
+	for name, v := range values {
+		if attrs, ok := v.(map[string]interface{}); ok {
+			attributes[name] = attrs
+		}
+	}
+	return attributes
+}
+
+func (s *jolokiaScraper) addMetric(ms pdata.MetricSlice, metric mbeanMetric, attributes map[string]map[string]interface{}, now pdata.Timestamp) {
+	var m pdata.Metric
+	created := false
+	for name, attrs := range attributes {
+		value, ok := attributeValue(attrs, metric.attribute)
+		if !ok {
+			continue
+		}
+		if !created {
+			m = ms.AppendEmpty()
+			m.SetName(metric.name)
+			m.SetDescription(metric.description)
+			m.SetUnit(metric.unit)
+			m.SetDataType(metric.dataType)
+			created = true
+		}
+
+		var labels pdata.StringMap
+		switch metric.dataType {
+		case pdata.MetricDataTypeIntGauge:
+			dp := m.IntGauge().DataPoints().AppendEmpty()
+			dp.SetTimestamp(now)
+			dp.SetValue(int64(value))
+			labels = dp.LabelsMap()
+		case pdata.MetricDataTypeIntSum:
+			sum := m.IntSum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(s.startTime)
+			dp.SetTimestamp(now)
+			dp.SetValue(int64(value))
+			labels = dp.LabelsMap()
+		case pdata.MetricDataTypeDoubleGauge:
+			dp := m.DoubleGauge().DataPoints().AppendEmpty()
+			dp.SetTimestamp(now)
+			dp.SetValue(value)
+			labels = dp.LabelsMap()
+		}
+		if metric.labelKey != "" {
+			labels.Insert(metric.labelKey, objectNameProperty(name, metric.labelKey))
+		}
+	}
+}
+
+// attributeValue returns the numeric value of an attribute, or of a key of
+// its composite value, e.g. HeapMemoryUsage.used.
+func attributeValue(attrs map[string]interface{}, attribute string) (float64, bool) {
+	parts := strings.SplitN(attribute, ".", 2)
+	value := attrs[parts[0]]
+	if len(parts) == 2 {
+		composite, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		value = composite[parts[1]]
+	}
+	f, ok := value.(float64)
+	return f, ok
+}
+
+// objectNameProperty returns the value of a key property of an ObjectName,
+// e.g. "G1 Young Generation" for the name of
+// java.lang:name=G1 Young Generation,type=GarbageCollector.
+func objectNameProperty(objectName, key string) string {
+	i := strings.Index(objectName, ":")
+	for _, property := range strings.Split(objectName[i+1:], ",") {
+		kv := strings.SplitN(property, "=", 2)
+		if len(kv) == 2 && kv[0] == key {
+			return strings.Trim(kv[1], `"`)
+		}
+	}
+	return ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
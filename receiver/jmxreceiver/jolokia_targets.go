@@ -0,0 +1,211 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// mbeanMetric maps an MBean attribute read from Jolokia to a metric.
+type mbeanMetric struct {
+	// The ObjectName of the MBean, which may be a pattern, e.g.
+	// java.lang:type=GarbageCollector,name=*
+	mbean string
+	// The attribute of the MBean, followed by the key of its composite value
+	// after a dot, e.g. HeapMemoryUsage.used
+	attribute string
+
+	name        string
+	description string
+	unit        string
+	dataType    pdata.MetricDataType
+	// The key property of the ObjectName added as the label of the same name
+	// to the data points, for the MBean patterns.
+	labelKey string
+}
+
+// jolokiaTargetSystems are the MBeans collected for the target systems in the
+// jolokia mode, named after the metrics of the target system scripts of the
+// JMX Metric Gatherer.
+var jolokiaTargetSystems = map[string][]mbeanMetric{
+	"jvm":       jvmMetrics,
+	"kafka":     kafkaMetrics,
+	"cassandra": cassandraMetrics,
+}
+
+var jvmMetrics = append(append(append([]mbeanMetric{
+	{
+		mbean: "java.lang:type=ClassLoading", attribute: "LoadedClassCount",
+		name: "jvm.classes.loaded", description: "number of loaded classes", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+	{
+		mbean: "java.lang:type=GarbageCollector,name=*", attribute: "CollectionCount",
+		name: "jvm.gc.collections.count", description: "total number of collections that have occurred", unit: "1",
+		dataType: pdata.MetricDataTypeIntSum, labelKey: "name",
+	},
+	{
+		mbean: "java.lang:type=GarbageCollector,name=*", attribute: "CollectionTime",
+		name: "jvm.gc.collections.elapsed", description: "the approximate accumulated collection elapsed time in milliseconds", unit: "ms",
+		dataType: pdata.MetricDataTypeIntSum, labelKey: "name",
+	},
+	{
+		mbean: "java.lang:type=Threading", attribute: "ThreadCount",
+		name: "jvm.threads.count", description: "number of threads", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+},
+	memoryUsageMetrics("java.lang:type=Memory", "HeapMemoryUsage", "jvm.memory.heap", "current heap usage", "")...),
+	memoryUsageMetrics("java.lang:type=Memory", "NonHeapMemoryUsage", "jvm.memory.nonheap", "current non-heap usage", "")...),
+	memoryUsageMetrics("java.lang:type=MemoryPool,name=*", "Usage", "jvm.memory.pool", "current memory pool usage", "name")...)
+
+// memoryUsageMetrics maps the keys of a java.lang.management.MemoryUsage
+// composite value.
+func memoryUsageMetrics(mbean, attribute, name, description, labelKey string) []mbeanMetric {
+	var metrics []mbeanMetric
+	for _, key := range []string{"init", "committed", "used", "max"} {
+		metrics = append(metrics, mbeanMetric{
+			mbean: mbean, attribute: attribute + "." + key,
+			name: name + "." + key, description: description, unit: "by",
+			dataType: pdata.MetricDataTypeIntGauge, labelKey: labelKey,
+		})
+	}
+	return metrics
+}
+
+var kafkaMetrics = []mbeanMetric{
+	{
+		mbean: "kafka.server:type=BrokerTopicMetrics,name=BytesInPerSec", attribute: "OneMinuteRate",
+		name: "kafka.bytes.in", description: "bytes in per second from clients", unit: "by",
+		dataType: pdata.MetricDataTypeDoubleGauge,
+	},
+	{
+		mbean: "kafka.server:type=BrokerTopicMetrics,name=BytesOutPerSec", attribute: "OneMinuteRate",
+		name: "kafka.bytes.out", description: "bytes out per second to clients", unit: "by",
+		dataType: pdata.MetricDataTypeDoubleGauge,
+	},
+	{
+		mbean: "kafka.server:type=BrokerTopicMetrics,name=MessagesInPerSec", attribute: "OneMinuteRate",
+		name: "kafka.messages.in", description: "number of messages in per second", unit: "1",
+		dataType: pdata.MetricDataTypeDoubleGauge,
+	},
+	{
+		mbean: "kafka.server:type=ReplicaManager,name=IsrExpandsPerSec", attribute: "Count",
+		name: "kafka.isr.expands", description: "in-sync replica expands count", unit: "1",
+		dataType: pdata.MetricDataTypeIntSum,
+	},
+	{
+		mbean: "kafka.server:type=ReplicaManager,name=IsrShrinksPerSec", attribute: "Count",
+		name: "kafka.isr.shrinks", description: "in-sync replica shrinks count", unit: "1",
+		dataType: pdata.MetricDataTypeIntSum,
+	},
+	{
+		mbean: "kafka.server:type=ReplicaFetcherManager,name=MaxLag,clientId=Replica", attribute: "Value",
+		name: "kafka.max.lag", description: "max lag in messages between follower and leader replicas", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+	{
+		mbean: "kafka.controller:type=KafkaController,name=ActiveControllerCount", attribute: "Value",
+		name: "kafka.controller.active.count", description: "controller is active on broker", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+	{
+		mbean: "kafka.controller:type=KafkaController,name=OfflinePartitionsCount", attribute: "Value",
+		name: "kafka.partitions.offline.count", description: "number of partitions without an active leader", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+	{
+		mbean: "kafka.server:type=ReplicaManager,name=UnderReplicatedPartitions", attribute: "Value",
+		name: "kafka.partitions.underreplicated.count", description: "number of under replicated partitions", unit: "1",
+		dataType: pdata.MetricDataTypeIntGauge,
+	},
+	{
+		mbean: "kafka.controller:type=ControllerStats,name=LeaderElectionRateAndTimeMs", attribute: "Count",
+		name: "kafka.leader.election.rate", description: "leader election count", unit: "1",
+		dataType: pdata.MetricDataTypeIntSum,
+	},
+	{
+		mbean: "kafka.controller:type=ControllerStats,name=UncleanLeaderElectionsPerSec", attribute: "Count",
+		name: "kafka.unclean.election.rate", description: "unclean leader election count", unit: "1",
+		dataType: pdata.MetricDataTypeIntSum,
+	},
+}
+
+var cassandraMetrics = append(append(append(
+	clientRequestMetrics("RangeSlice", "range_slice", "Token range read request"),
+	clientRequestMetrics("Read", "read", "Regular read request")...),
+	clientRequestMetrics("Write", "write", "Regular write request")...),
+	[]mbeanMetric{
+		{
+			mbean: "org.apache.cassandra.metrics:type=Storage,name=Load", attribute: "Count",
+			name: "cassandra.storage.load.count", description: "Size of the on disk data size this node manages", unit: "by",
+			dataType: pdata.MetricDataTypeIntGauge,
+		},
+		{
+			mbean: "org.apache.cassandra.metrics:type=Storage,name=TotalHints", attribute: "Count",
+			name: "cassandra.storage.total_hints.count", description: "Number of hint messages written to this node since [re]start", unit: "1",
+			dataType: pdata.MetricDataTypeIntSum,
+		},
+		{
+			mbean: "org.apache.cassandra.metrics:type=Storage,name=TotalHintsInProgress", attribute: "Count",
+			name: "cassandra.storage.total_hints.in_progress.count", description: "Number of hints attempting to be sent currently", unit: "1",
+			dataType: pdata.MetricDataTypeIntGauge,
+		},
+		{
+			mbean: "org.apache.cassandra.metrics:type=Compaction,name=CompletedTasks", attribute: "Value",
+			name: "cassandra.compaction.tasks.completed", description: "Number of completed compactions since server [re]start", unit: "1",
+			dataType: pdata.MetricDataTypeIntSum,
+		},
+		{
+			mbean: "org.apache.cassandra.metrics:type=Compaction,name=PendingTasks", attribute: "Value",
+			name: "cassandra.compaction.tasks.pending", description: "Estimated number of compactions remaining to perform", unit: "1",
+			dataType: pdata.MetricDataTypeIntGauge,
+		},
+	}...)
+
+// clientRequestMetrics maps the latency, timeout and unavailable metrics of
+// a scope of the client requests of Cassandra.
+func clientRequestMetrics(scope, name, description string) []mbeanMetric {
+	prefix := "org.apache.cassandra.metrics:type=ClientRequest,scope=" + scope + ",name="
+	return []mbeanMetric{
+		{
+			mbean: prefix + "Latency", attribute: "50thPercentile",
+			name: "cassandra.client.request." + name + ".latency.50p", description: description + " latency - 50th percentile", unit: "µs",
+			dataType: pdata.MetricDataTypeDoubleGauge,
+		},
+		{
+			mbean: prefix + "Latency", attribute: "99thPercentile",
+			name: "cassandra.client.request." + name + ".latency.99p", description: description + " latency - 99th percentile", unit: "µs",
+			dataType: pdata.MetricDataTypeDoubleGauge,
+		},
+		{
+			mbean: prefix + "Latency", attribute: "Max",
+			name: "cassandra.client.request." + name + ".latency.max", description: "Maximum " + strings.ToLower(description) + " latency", unit: "µs",
+			dataType: pdata.MetricDataTypeDoubleGauge,
+		},
+		{
+			mbean: prefix + "Timeouts", attribute: "Count",
+			name: "cassandra.client.request." + name + ".timeout.count", description: "Number of " + strings.ToLower(description) + " timeouts encountered", unit: "1",
+			dataType: pdata.MetricDataTypeIntSum,
+		},
+		{
+			mbean: prefix + "Unavailables", attribute: "Count",
+			name: "cassandra.client.request." + name + ".unavailable.count", description: "Number of " + strings.ToLower(description) + " unavailable exceptions encountered", unit: "1",
+			dataType: pdata.MetricDataTypeIntSum,
+		},
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+func TestJolokiaScraper(t *testing.T) {
+	jolokia := newJolokiaMock(t)
+	defer jolokia.Close()
+
+	s := newTestJolokiaScraper(t, jolokia.URL+"/jolokia", "jvm")
+	rms, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, 16, ms.Len())
+	values := metricValues(ms)
+	assert.Equal(t, 6873.0, values["jvm.classes.loaded"])
+	assert.Equal(t, 28.0, values["jvm.gc.collections.count{G1 Young Generation}"])
+	assert.Equal(t, 0.0, values["jvm.gc.collections.count{G1 Old Generation}"])
+	assert.Equal(t, 174.0, values["jvm.gc.collections.elapsed{G1 Young Generation}"])
+	assert.Equal(t, 41.0, values["jvm.threads.count"])
+	assert.Equal(t, 81474600.0, values["jvm.memory.heap.used"])
+	assert.Equal(t, 4204789760.0, values["jvm.memory.heap.max"])
+	assert.Equal(t, -1.0, values["jvm.memory.nonheap.max"])
+	assert.Equal(t, 40318928.0, values["jvm.memory.pool.used{Metaspace}"])
+	assert.Equal(t, 166723584.0, values["jvm.memory.pool.committed{G1 Eden Space}"])
+
+	gc := findMetric(t, ms, "jvm.gc.collections.count")
+	assert.Equal(t, pdata.MetricDataTypeIntSum, gc.DataType())
+	assert.True(t, gc.IntSum().IsMonotonic())
+	assert.Equal(t, "1", gc.Unit())
+}
+
+func TestJolokiaScraper_PartialError(t *testing.T) {
+	jolokia := newJolokiaMock(t)
+	defer jolokia.Close()
+
+	// the unclean leader elections MBean is missing
+	s := newTestJolokiaScraper(t, jolokia.URL+"/jolokia", "kafka")
+	rms, err := s.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, 10, ms.Len())
+	values := metricValues(ms)
+	assert.Equal(t, 1523.72, values["kafka.bytes.in"])
+	assert.Equal(t, 4.0, values["kafka.isr.shrinks"])
+	assert.Equal(t, 2.0, values["kafka.partitions.underreplicated.count"])
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, findMetric(t, ms, "kafka.bytes.in").DataType())
+}
+
+func TestJolokiaScraper_Error(t *testing.T) {
+	jolokia := newJolokiaMock(t)
+	defer jolokia.Close()
+
+	s := newTestJolokiaScraper(t, jolokia.URL+"/missing", "jvm")
+	_, err := s.scrape(context.Background())
+	require.Error(t, err)
+
+	s.config.Endpoint = jolokia.URL + "/jolokia"
+	s.config.Username = "wrong"
+	_, err = s.scrape(context.Background())
+	require.Error(t, err)
+}
+
+func TestJolokiaScraper_Requests(t *testing.T) {
+	s := newJolokiaScraper(zap.NewNop(), &Config{TargetSystem: "jvm"})
+	// the memory usage metrics share the requests of their MBeans
+	assert.Equal(t, []jolokiaRequest{
+		{Type: "read", MBean: "java.lang:type=ClassLoading", Attribute: []string{"LoadedClassCount"}},
+		{Type: "read", MBean: "java.lang:type=GarbageCollector,name=*", Attribute: []string{"CollectionCount", "CollectionTime"}},
+		{Type: "read", MBean: "java.lang:type=Threading", Attribute: []string{"ThreadCount"}},
+		{Type: "read", MBean: "java.lang:type=Memory", Attribute: []string{"HeapMemoryUsage", "NonHeapMemoryUsage"}},
+		{Type: "read", MBean: "java.lang:type=MemoryPool,name=*", Attribute: []string{"Usage"}},
+	}, s.requests)
+}
+
+func TestJolokiaReceiver(t *testing.T) {
+	jolokia := newJolokiaMock(t)
+	defer jolokia.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Mode = modeJolokia
+	cfg.Endpoint = jolokia.URL + "/jolokia"
+	cfg.TargetSystem = "jvm"
+	cfg.Username = "user"
+	cfg.Password = "password"
+	cfg.CollectionInterval = 10 * time.Millisecond
+
+	sink := new(consumertest.MetricsSink)
+	r, err := NewFactory().CreateMetricsReceiver(
+		context.Background(),
+		component.ReceiverCreateParams{Logger: zap.NewNop()},
+		cfg, sink,
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.MetricsCount() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func newTestJolokiaScraper(t *testing.T, endpoint, targetSystem string) *jolokiaScraper {
+	s := newJolokiaScraper(zap.NewNop(), &Config{
+		Endpoint:           endpoint,
+		TargetSystem:       targetSystem,
+		Username:           "user",
+		Password:           "password",
+		CollectionInterval: 10 * time.Second,
+	})
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	return s
+}
+
+// newJolokiaMock responds to the bulk read requests with the values of the
+// testdata, by MBean.
+func newJolokiaMock(t *testing.T) *httptest.Server {
+	b, err := ioutil.ReadFile(path.Join("testdata", "jolokia.json"))
+	require.NoError(t, err)
+	var values map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &values))
+
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/jolokia" || req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "password" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var requests []jolokiaRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&requests))
+
+		responses := make([]map[string]interface{}, 0, len(requests))
+		for _, r := range requests {
+			value, ok := values[r.MBean]
+			if !ok {
+				responses = append(responses, map[string]interface{}{
+					"request": r,
+					"status":  404,
+					"error":   "javax.management.InstanceNotFoundException : " + r.MBean,
+				})
+				continue
+			}
+			responses = append(responses, map[string]interface{}{
+				"request": r,
+				"value":   value,
+				"status":  200,
+			})
+		}
+		require.NoError(t, json.NewEncoder(rw).Encode(responses))
+	}))
+}
+
+// metricValues returns the values of the data points by metric name,
+// followed by their label between braces.
+func metricValues(ms pdata.MetricSlice) map[string]float64 {
+	values := map[string]float64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		add := func(labels pdata.StringMap, value float64) {
+			key := m.Name()
+			labels.Range(func(_, v string) bool {
+				key += "{" + v + "}"
+				return true
+			})
+			values[key] = value
+		}
+		switch m.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			for j := 0; j < m.IntGauge().DataPoints().Len(); j++ {
+				dp := m.IntGauge().DataPoints().At(j)
+				add(dp.LabelsMap(), float64(dp.Value()))
+			}
+		case pdata.MetricDataTypeIntSum:
+			for j := 0; j < m.IntSum().DataPoints().Len(); j++ {
+				dp := m.IntSum().DataPoints().At(j)
+				add(dp.LabelsMap(), float64(dp.Value()))
+			}
+		case pdata.MetricDataTypeDoubleGauge:
+			for j := 0; j < m.DoubleGauge().DataPoints().Len(); j++ {
+				dp := m.DoubleGauge().DataPoints().At(j)
+				add(dp.LabelsMap(), dp.Value())
+			}
+		}
+	}
+	return values
+}
+
+func findMetric(t *testing.T, ms pdata.MetricSlice, name string) pdata.Metric {
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() == name {
+			return ms.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", name)
+	return pdata.Metric{}
+}
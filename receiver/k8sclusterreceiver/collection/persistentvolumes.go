@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	metadata "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume metadata.
+	persistentVolumeStorageClass = "k8s.storageclass.name"
+)
+
+var persistentVolumePhaseMetric = &metricspb.MetricDescriptor{
+	Name: "k8s.persistentvolume.phase",
+	Description: "Current phase of the persistent volume (1 - Pending, 2 - Available, 3 - Bound, " +
+		"4 - Released, 5 - Failed, -1 - Unknown)",
+	Type: metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolume.capacity",
+	Description: "Storage capacity of the persistent volume",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPersistentVolume(pv *corev1.PersistentVolume) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumePhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(persistentVolumePhaseValue(pv.Status.Phase)),
+			},
+		},
+	}
+
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolume(pv),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolume(pv *corev1.PersistentVolume) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeUID:       string(pv.UID),
+			k8sKeyPersistentVolumeName:      pv.Name,
+			conventions.AttributeK8sCluster: pv.ClusterName,
+		},
+	}
+}
+
+var persistentVolumePhaseValues = map[corev1.PersistentVolumePhase]int64{
+	corev1.VolumePending:   1,
+	corev1.VolumeAvailable: 2,
+	corev1.VolumeBound:     3,
+	corev1.VolumeReleased:  4,
+	corev1.VolumeFailed:    5,
+}
+
+func persistentVolumePhaseValue(phase corev1.PersistentVolumePhase) int64 {
+	if v, ok := persistentVolumePhaseValues[phase]; ok {
+		return v
+	}
+	return -1
+}
+
+func getMetadataForPersistentVolume(pv *corev1.PersistentVolume) map[metadata.ResourceID]*KubernetesMetadata {
+	km := getGenericMetadata(&pv.ObjectMeta, k8sKindPersistentVolume)
+	if pv.Spec.StorageClassName != "" {
+		km.metadata[persistentVolumeStorageClass] = pv.Spec.StorageClassName
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		km.metadata[k8sKeyPersistentVolumeClaimName] = ref.Name
+		km.metadata[k8sKeyPersistentVolumeClaimUID] = string(ref.UID)
+		km.metadata[conventions.AttributeK8sNamespace] = ref.Namespace
+	}
+
+	return map[metadata.ResourceID]*KubernetesMetadata{
+		metadata.ResourceID(pv.UID): km,
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	metadata "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+var serviceEndpointsReadyMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.service.endpoints.ready",
+	Description: "Number of ready endpoint addresses of the service",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var serviceEndpointsNotReadyMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.service.endpoints.not_ready",
+	Description: "Number of endpoint addresses of the service that are not ready",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+// getMetricsForEndpoints returns the endpoint counts of the service with the
+// same name as the endpoints. The services store is used to look up the
+// uid of the service.
+func getMetricsForEndpoints(ep *corev1.Endpoints, services cache.Store) []*resourceMetrics {
+	ready, notReady := 0, 0
+	for _, subset := range ep.Subsets {
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: serviceEndpointsReadyMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(ready)),
+			},
+		},
+		{
+			MetricDescriptor: serviceEndpointsNotReadyMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(notReady)),
+			},
+		},
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForEndpoints(ep, services),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForEndpoints(ep *corev1.Endpoints, services cache.Store) *resourcepb.Resource {
+	labels := map[string]string{
+		k8sKeyServiceName:                 ep.Name,
+		conventions.AttributeK8sNamespace: ep.Namespace,
+		conventions.AttributeK8sCluster:   ep.ClusterName,
+	}
+
+	if services != nil {
+		obj, exists, err := services.GetByKey(ep.Namespace + "/" + ep.Name)
+		if err == nil && exists {
+			labels[k8sKeyServiceUID] = string(obj.(*corev1.Service).UID)
+		}
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+func getMetadataForService(svc *corev1.Service) map[metadata.ResourceID]*KubernetesMetadata {
+	km := getGenericMetadata(&svc.ObjectMeta, k8sKindService)
+	km.metadata[k8sKeyServiceName] = svc.Name
+
+	return map[metadata.ResourceID]*KubernetesMetadata{
+		metadata.ResourceID(svc.UID): km,
+	}
+}
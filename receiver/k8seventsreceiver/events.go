@@ -0,0 +1,135 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8seventsreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Attributes of the object involved in the event, set on the resource.
+const (
+	attributeObjectKind            = "k8s.object.kind"
+	attributeObjectName            = "k8s.object.name"
+	attributeObjectUID             = "k8s.object.uid"
+	attributeObjectAPIVersion      = "k8s.object.api_version"
+	attributeObjectResourceVersion = "k8s.object.resource_version"
+	attributeObjectFieldPath       = "k8s.object.fieldpath"
+)
+
+// Attributes of the event, set on the log record.
+const (
+	attributeEventName            = "k8s.event.name"
+	attributeEventUID             = "k8s.event.uid"
+	attributeEventReason          = "k8s.event.reason"
+	attributeEventAction          = "k8s.event.action"
+	attributeEventCount           = "k8s.event.count"
+	attributeEventStartTime       = "k8s.event.start_time"
+	attributeEventSourceComponent = "k8s.event.source.component"
+	attributeEventSourceHost      = "k8s.event.source.host"
+)
+
+// kindAttributes are the semantic conventions attributes of the name and uid
+// of the kinds of objects that have them.
+var kindAttributes = map[string]struct{ name, uid string }{
+	"Pod":         {conventions.AttributeK8sPod, conventions.AttributeK8sPodUID},
+	"Node":        {conventions.AttributeK8sNodeName, conventions.AttributeK8sNodeUID},
+	"Deployment":  {conventions.AttributeK8sDeployment, conventions.AttributeK8sDeploymentUID},
+	"ReplicaSet":  {conventions.AttributeK8sReplicaSet, conventions.AttributeK8sReplicaSetUID},
+	"StatefulSet": {conventions.AttributeK8sStatefulSet, conventions.AttributeK8sStatefulSetUID},
+	"DaemonSet":   {conventions.AttributeK8sDaemonSet, conventions.AttributeK8sDaemonSetUID},
+	"Job":         {conventions.AttributeK8sJob, conventions.AttributeK8sJobUID},
+	"CronJob":     {conventions.AttributeK8sCronJob, conventions.AttributeK8sCronJobUID},
+}
+
+// eventToLogs converts a kubernetes event to a log record whose resource is
+// the object involved in the event.
+func eventToLogs(ev *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+
+	obj := ev.InvolvedObject
+	resourceAttrs := rl.Resource().Attributes()
+	insertString(resourceAttrs, conventions.AttributeK8sNamespace, obj.Namespace)
+	insertString(resourceAttrs, attributeObjectKind, obj.Kind)
+	insertString(resourceAttrs, attributeObjectName, obj.Name)
+	insertString(resourceAttrs, attributeObjectUID, string(obj.UID))
+	insertString(resourceAttrs, attributeObjectAPIVersion, obj.APIVersion)
+	insertString(resourceAttrs, attributeObjectResourceVersion, obj.ResourceVersion)
+	insertString(resourceAttrs, attributeObjectFieldPath, obj.FieldPath)
+	if attrs, ok := kindAttributes[obj.Kind]; ok {
+		insertString(resourceAttrs, attrs.name, obj.Name)
+		insertString(resourceAttrs, attrs.uid, string(obj.UID))
+	}
+
+	lr := rl.InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetName(ev.Reason)
+	lr.SetTimestamp(pdata.TimestampFromTime(eventTimestamp(ev)))
+	lr.SetSeverityText(ev.Type)
+	lr.SetSeverityNumber(severityNumber(ev.Type))
+	lr.Body().SetStringVal(ev.Message)
+
+	attrs := lr.Attributes()
+	insertString(attrs, attributeEventName, ev.Name)
+	insertString(attrs, attributeEventUID, string(ev.UID))
+	insertString(attrs, attributeEventReason, ev.Reason)
+	insertString(attrs, attributeEventAction, ev.Action)
+	insertString(attrs, attributeEventSourceComponent, ev.Source.Component)
+	insertString(attrs, attributeEventSourceHost, ev.Source.Host)
+	if !ev.FirstTimestamp.IsZero() {
+		attrs.InsertString(attributeEventStartTime, ev.FirstTimestamp.UTC().Format(time.RFC3339))
+	}
+	if ev.Count > 0 {
+		attrs.InsertInt(attributeEventCount, int64(ev.Count))
+	}
+
+	return ld
+}
+
+// eventTimestamp returns the time of the latest occurrence of the event.
+func eventTimestamp(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// severityNumber maps the event types to log severities, Normal events are
+// informational and Warning events are warnings.
+func severityNumber(eventType string) pdata.SeverityNumber {
+	switch eventType {
+	case corev1.EventTypeNormal:
+		return pdata.SeverityNumberINFO
+	case corev1.EventTypeWarning:
+		return pdata.SeverityNumberWARN
+	default:
+		return pdata.SeverityNumberUNDEFINED
+	}
+}
+
+func insertString(attrs pdata.AttributeMap, key string, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8seventsreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEventToLogs(t *testing.T) {
+	first := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Minute)
+	ev := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "nginx.1681f0b1b6a8",
+			UID:             "event-uid",
+			ResourceVersion: "42",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            "Pod",
+			Namespace:       "default",
+			Name:            "nginx",
+			UID:             "pod-uid",
+			APIVersion:      "v1",
+			ResourceVersion: "7",
+			FieldPath:       "spec.containers{nginx}",
+		},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Source:         corev1.EventSource{Component: "kubelet", Host: "node-1"},
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+		Count:          3,
+		Type:           corev1.EventTypeWarning,
+	}
+
+	ld := eventToLogs(ev)
+	require.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+
+	assert.Equal(t, map[string]pdata.AttributeValue{
+		conventions.AttributeK8sNamespace: pdata.NewAttributeValueString("default"),
+		attributeObjectKind:               pdata.NewAttributeValueString("Pod"),
+		attributeObjectName:               pdata.NewAttributeValueString("nginx"),
+		attributeObjectUID:                pdata.NewAttributeValueString("pod-uid"),
+		attributeObjectAPIVersion:         pdata.NewAttributeValueString("v1"),
+		attributeObjectResourceVersion:    pdata.NewAttributeValueString("7"),
+		attributeObjectFieldPath:          pdata.NewAttributeValueString("spec.containers{nginx}"),
+		conventions.AttributeK8sPod:       pdata.NewAttributeValueString("nginx"),
+		conventions.AttributeK8sPodUID:    pdata.NewAttributeValueString("pod-uid"),
+	}, attributesToMap(rl.Resource().Attributes()))
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "BackOff", lr.Name())
+	assert.Equal(t, pdata.TimestampFromTime(last), lr.Timestamp())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "Back-off restarting failed container", lr.Body().StringVal())
+	assert.Equal(t, map[string]pdata.AttributeValue{
+		attributeEventName:            pdata.NewAttributeValueString("nginx.1681f0b1b6a8"),
+		attributeEventUID:             pdata.NewAttributeValueString("event-uid"),
+		attributeEventReason:          pdata.NewAttributeValueString("BackOff"),
+		attributeEventSourceComponent: pdata.NewAttributeValueString("kubelet"),
+		attributeEventSourceHost:      pdata.NewAttributeValueString("node-1"),
+		attributeEventStartTime:       pdata.NewAttributeValueString("2021-06-01T10:00:00Z"),
+		attributeEventCount:           pdata.NewAttributeValueInt(3),
+	}, attributesToMap(lr.Attributes()))
+}
+
+func TestEventTimestamp(t *testing.T) {
+	created := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	ev := &corev1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	assert.Equal(t, created, eventTimestamp(ev))
+
+	first := created.Add(time.Second)
+	ev.FirstTimestamp = metav1.NewTime(first)
+	assert.Equal(t, first, eventTimestamp(ev))
+
+	eventTime := created.Add(2 * time.Second)
+	ev.EventTime = metav1.NewMicroTime(eventTime)
+	assert.Equal(t, eventTime, eventTimestamp(ev))
+
+	last := created.Add(3 * time.Second)
+	ev.LastTimestamp = metav1.NewTime(last)
+	assert.Equal(t, last, eventTimestamp(ev))
+}
+
+func TestSeverityNumber(t *testing.T) {
+	assert.Equal(t, pdata.SeverityNumberINFO, severityNumber(corev1.EventTypeNormal))
+	assert.Equal(t, pdata.SeverityNumberWARN, severityNumber(corev1.EventTypeWarning))
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, severityNumber("Unknown"))
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]pdata.AttributeValue {
+	result := map[string]pdata.AttributeValue{}
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		result[k] = v
+		return true
+	})
+	return result
+}
@@ -0,0 +1,212 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8seventsreceiver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage"
+)
+
+const (
+	transport = "http"
+
+	// resourceVersionKeyPrefix is the prefix of the storage keys holding the
+	// latest resource version emitted for each watched namespace.
+	resourceVersionKeyPrefix = "latest_resource_version/"
+)
+
+var _ component.LogsReceiver = (*eventsReceiver)(nil)
+
+type eventsReceiver struct {
+	config        *Config
+	logger        *zap.Logger
+	client        kubernetes.Interface
+	consumer      consumer.Logs
+	storageClient storage.Client
+	cancel        context.CancelFunc
+	obsrecv       *obsreport.Receiver
+}
+
+// namespaceWatch tracks the resource versions of the events of a watched
+// namespace, it is only accessed by the handlers of its informer.
+type namespaceWatch struct {
+	namespace string
+	// checkpoint is the resource version stored by a previous run, the events
+	// up to it were already emitted and are skipped when listed again.
+	checkpoint uint64
+	// latest is the highest resource version emitted so far.
+	latest uint64
+}
+
+// newReceiver creates the Kubernetes events receiver with the given configuration.
+func newReceiver(
+	logger *zap.Logger, config *Config, consumer consumer.Logs,
+	client kubernetes.Interface) component.LogsReceiver {
+	return &eventsReceiver{
+		config:   config,
+		logger:   logger,
+		client:   client,
+		consumer: consumer,
+		obsrecv:  obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: config.ID(), Transport: transport}),
+	}
+}
+
+func (r *eventsReceiver) Start(ctx context.Context, host component.Host) error {
+	if err := r.setStorageClient(ctx, host); err != nil {
+		return err
+	}
+
+	namespaces := r.config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	watches := make([]*namespaceWatch, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		w := &namespaceWatch{namespace: namespace}
+		if err := r.loadCheckpoint(ctx, w); err != nil {
+			return err
+		}
+		watches = append(watches, w)
+	}
+
+	var c context.Context
+	c, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), r.config.ID(), transport))
+	for _, w := range watches {
+		r.startWatch(c, w)
+	}
+	return nil
+}
+
+func (r *eventsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+// startWatch starts an informer on the events of the namespace of w.
+func (r *eventsReceiver) startWatch(ctx context.Context, w *namespaceWatch) {
+	factory := informers.NewSharedInformerFactoryWithOptions(r.client, 0, informers.WithNamespace(w.namespace))
+	factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.handleEvent(ctx, w, obj)
+		},
+		// An event is updated when it occurs again, its count and last
+		// timestamp are incremented.
+		UpdateFunc: func(_, obj interface{}) {
+			r.handleEvent(ctx, w, obj)
+		},
+	})
+	factory.Start(ctx.Done())
+}
+
+func (r *eventsReceiver) handleEvent(ctx context.Context, w *namespaceWatch, obj interface{}) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	// Resource versions are opaque strings for the API, but they are the
+	// etcd revisions in practice. The events that don't have a numeric
+	// version are not deduplicated.
+	version, err := strconv.ParseUint(ev.ResourceVersion, 10, 64)
+	hasVersion := err == nil
+	if hasVersion && version <= w.checkpoint {
+		return
+	}
+
+	obsCtx := r.obsrecv.StartLogsReceiveOp(ctx)
+	err = r.consumer.ConsumeLogs(obsCtx, eventToLogs(ev))
+	r.obsrecv.EndLogsReceiveOp(obsCtx, typeStr, 1, err)
+	if err != nil {
+		r.logger.Error("Failed to consume the event",
+			zap.String("namespace", ev.Namespace), zap.String("name", ev.Name), zap.Error(err))
+		return
+	}
+
+	if hasVersion && version > w.latest {
+		w.latest = version
+		if err := r.storageClient.Set(ctx, storageKey(w.namespace), []byte(ev.ResourceVersion)); err != nil {
+			r.logger.Warn("Failed to store the latest resource version", zap.String("namespace", w.namespace), zap.Error(err))
+		}
+	}
+}
+
+// loadCheckpoint restores the latest resource version emitted by a previous
+// run for the namespace of w.
+func (r *eventsReceiver) loadCheckpoint(ctx context.Context, w *namespaceWatch) error {
+	data, err := r.storageClient.Get(ctx, storageKey(w.namespace))
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	version, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		r.logger.Warn("Ignoring invalid stored resource version",
+			zap.String("namespace", w.namespace), zap.ByteString("version", data))
+		return nil
+	}
+	w.checkpoint = version
+	w.latest = version
+	return nil
+}
+
+func (r *eventsReceiver) setStorageClient(ctx context.Context, host component.Host) error {
+	var storageExtension storage.Extension
+	for _, ext := range host.GetExtensions() {
+		if se, ok := ext.(storage.Extension); ok {
+			if storageExtension != nil {
+				return errors.New("multiple storage extensions found")
+			}
+			storageExtension = se
+		}
+	}
+
+	if storageExtension == nil {
+		r.storageClient = storage.NewNopClient()
+		return nil
+	}
+
+	client, err := storageExtension.GetClient(ctx, component.KindReceiver, r.config.ID())
+	if err != nil {
+		return err
+	}
+
+	r.storageClient = client
+	return nil
+}
+
+// storageKey returns the key of the latest resource version of a namespace,
+// the namespace is empty when all the namespaces are watched.
+func storageKey(namespace string) string {
+	return resourceVersionKeyPrefix + namespace
+}
@@ -0,0 +1,172 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8seventsreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newTestEvent(namespace, name, resourceVersion string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			ResourceVersion: resourceVersion,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: namespace,
+			Name:      "pod-" + name,
+		},
+		Type:    corev1.EventTypeNormal,
+		Reason:  "Started",
+		Message: "Started container " + name,
+		Count:   1,
+	}
+}
+
+func createEvent(t *testing.T, client kubernetes.Interface, ev *corev1.Event) {
+	_, err := client.CoreV1().Events(ev.Namespace).Create(context.Background(), ev, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+func newTestConfig(namespaces ...string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Namespaces = namespaces
+	return cfg
+}
+
+func TestReceiver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createEvent(t, client, newTestEvent("default", "one", "10"))
+	createEvent(t, client, newTestEvent("other", "two", "11"))
+
+	sink := new(consumertest.LogsSink)
+	r := newReceiver(zap.NewNop(), newTestConfig("default"), sink, client)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(ctx))
+	}()
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	createEvent(t, client, newTestEvent("default", "three", "12"))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	logs := sink.AllLogs()
+	name, ok := logs[0].ResourceLogs().At(0).Resource().Attributes().Get(attributeObjectName)
+	require.True(t, ok)
+	assert.Equal(t, "pod-one", name.StringVal())
+	name, ok = logs[1].ResourceLogs().At(0).Resource().Attributes().Get(attributeObjectName)
+	require.True(t, ok)
+	assert.Equal(t, "pod-three", name.StringVal())
+}
+
+func TestReceiverDeduplicatesAcrossRestarts(t *testing.T) {
+	storageDir := t.TempDir()
+	client := fake.NewSimpleClientset()
+	createEvent(t, client, newTestEvent("default", "one", "10"))
+	createEvent(t, client, newTestEvent("default", "two", "11"))
+
+	ctx := context.Background()
+	cfg := newTestConfig()
+
+	host := storagetest.NewStorageHost(t, storageDir, "test")
+	sink := new(consumertest.LogsSink)
+	r := newReceiver(zap.NewNop(), cfg, sink, client)
+	require.NoError(t, r.Start(ctx, host))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, r.Shutdown(ctx))
+	for _, e := range host.GetExtensions() {
+		require.NoError(t, e.Shutdown(ctx))
+	}
+
+	// The event occurs again while the receiver is not running.
+	ev := newTestEvent("default", "one", "12")
+	ev.Count = 2
+	_, err := client.CoreV1().Events("default").Update(ctx, ev, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	host = storagetest.NewStorageHost(t, storageDir, "test")
+	sink = new(consumertest.LogsSink)
+	r = newReceiver(zap.NewNop(), cfg, sink, client)
+	require.NoError(t, r.Start(ctx, host))
+	defer func() {
+		require.NoError(t, r.Shutdown(ctx))
+	}()
+
+	// Only the update is emitted, the other event was already emitted.
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	count, ok := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes().Get(attributeEventCount)
+	require.True(t, ok)
+	assert.EqualValues(t, 2, count.IntVal())
+
+	createEvent(t, client, newTestEvent("default", "three", "13"))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReceiverMultipleStorageExtensions(t *testing.T) {
+	host := storagetest.NewStorageHost(t, t.TempDir(), "one", "two")
+	r := newReceiver(zap.NewNop(), newTestConfig(), consumertest.NewNop(), fake.NewSimpleClientset())
+	require.Error(t, r.Start(context.Background(), host))
+}
+
+func TestReceiverConsumerError(t *testing.T) {
+	ctx := context.Background()
+	r := newReceiver(zap.NewNop(), newTestConfig(), consumertest.NewErr(assert.AnError), fake.NewSimpleClientset()).(*eventsReceiver)
+	require.NoError(t, r.setStorageClient(ctx, storagetest.NewStorageHost(t, t.TempDir(), "test")))
+
+	// The event is not checkpointed when it could not be consumed.
+	w := &namespaceWatch{}
+	r.handleEvent(ctx, w, newTestEvent("default", "one", "10"))
+	assert.EqualValues(t, 0, w.latest)
+	data, err := r.storageClient.Get(ctx, storageKey(""))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	r.consumer = consumertest.NewNop()
+	r.handleEvent(ctx, w, newTestEvent("default", "one", "10"))
+	assert.EqualValues(t, 10, w.latest)
+	data, err = r.storageClient.Get(ctx, storageKey(""))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("10"), data)
+}
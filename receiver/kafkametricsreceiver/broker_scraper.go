@@ -17,6 +17,8 @@ package kafkametricsreceiver
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -24,15 +26,24 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver/internal/metadata"
 )
 
+// topic configurations added as labels of the log sizes
+const (
+	retentionMsConfig    = "retention.ms"
+	retentionBytesConfig = "retention.bytes"
+)
+
 type brokerScraper struct {
 	client       sarama.Client
+	clusterAdmin sarama.ClusterAdmin
 	logger       *zap.Logger
+	topicFilter  *regexp.Regexp
 	config       Config
 	saramaConfig *sarama.Config
 }
@@ -46,11 +57,24 @@ func (s *brokerScraper) start(context.Context, component.Host) error {
 	if err != nil {
 		return fmt.Errorf("failed to create client while starting brokers scraper: %w", err)
 	}
+	clusterAdmin, err := newClusterAdmin(s.config.Brokers, s.saramaConfig)
+	if err != nil {
+		if client != nil {
+			_ = client.Close()
+		}
+		return fmt.Errorf("failed to create cluster admin while starting brokers scraper: %w", err)
+	}
 	s.client = client
+	s.clusterAdmin = clusterAdmin
 	return nil
 }
 
 func (s *brokerScraper) shutdown(context.Context) error {
+	if s.clusterAdmin != nil {
+		if err := s.clusterAdmin.Close(); err != nil {
+			s.logger.Warn("failed to close cluster admin", zap.Error(err))
+		}
+	}
 	if !s.client.Closed() {
 		return s.client.Close()
 	}
@@ -67,12 +91,147 @@ func (s *brokerScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, err
 	}
 	metrics.AddGaugeDataPoint(metadata.M.KafkaBrokers.Name(), int64(len(brokers)))
 
-	return metrics.Metrics.ResourceMetrics(), nil
+	scrapeErrors := scrapererror.ScrapeErrors{}
+
+	clusterBrokers, controllerID, err := s.clusterAdmin.DescribeCluster()
+	if err != nil {
+		s.logger.Error("Error describing cluster", zap.Error(err))
+		scrapeErrors.AddPartial(4, err)
+		return metrics.Metrics.ResourceMetrics(), scrapeErrors.Combine()
+	}
+	metrics.AddGaugeDataPoint(metadata.M.KafkaControllerId.Name(), int64(controllerID))
+
+	brokerIDs := make([]int32, 0, len(clusterBrokers))
+	for _, broker := range clusterBrokers {
+		brokerIDs = append(brokerIDs, broker.ID())
+	}
+
+	if err := s.addPartitionStateMetrics(&metrics, brokerIDs); err != nil {
+		scrapeErrors.AddPartial(2, err)
+	}
+	if err := s.addLogSizeMetrics(&metrics, brokerIDs, &scrapeErrors); err != nil {
+		scrapeErrors.AddPartial(1, err)
+	}
+
+	return metrics.Metrics.ResourceMetrics(), scrapeErrors.Combine()
+}
+
+// addPartitionStateMetrics adds the number of offline partitions of the
+// cluster, and the number of under-replicated partitions of each broker
+// counted on the leader of the partitions, across all the topics.
+func (s *brokerScraper) addPartitionStateMetrics(metrics *simple.Metrics, brokerIDs []int32) error {
+	topics, err := s.client.Topics()
+	if err != nil {
+		return err
+	}
+	topicsMetadata, err := s.clusterAdmin.DescribeTopics(topics)
+	if err != nil {
+		return err
+	}
+
+	underReplicated := make(map[int32]int64, len(brokerIDs))
+	for _, id := range brokerIDs {
+		underReplicated[id] = 0
+	}
+	var offline int64
+	for _, topicMetadata := range topicsMetadata {
+		for _, partition := range topicMetadata.Partitions {
+			if partition.Leader < 0 {
+				offline++
+				continue
+			}
+			if len(partition.Isr) < len(partition.Replicas) {
+				underReplicated[partition.Leader]++
+			}
+		}
+	}
+
+	metrics.AddGaugeDataPoint(metadata.M.KafkaPartitionsOffline.Name(), offline)
+	for id, count := range underReplicated {
+		metrics.WithLabels(map[string]string{
+			metadata.L.Broker: strconv.FormatInt(int64(id), 10),
+		}).AddGaugeDataPoint(metadata.M.KafkaBrokerUnderReplicatedPartitions.Name(), count)
+	}
+	return nil
+}
+
+// addLogSizeMetrics adds the size of the replicas of the partitions of the
+// matched topics in each log directory of the brokers, along with the
+// retention configuration of the topics.
+func (s *brokerScraper) addLogSizeMetrics(metrics *simple.Metrics, brokerIDs []int32, scrapeErrors *scrapererror.ScrapeErrors) error {
+	logDirs, err := s.clusterAdmin.DescribeLogDirs(brokerIDs)
+	if err != nil {
+		return err
+	}
+
+	// the retention labels of the topics, described once per scrape
+	topicLabels := map[string]map[string]string{}
+	for brokerID, dirs := range logDirs {
+		brokerMetrics := metrics.WithLabels(map[string]string{
+			metadata.L.Broker: strconv.FormatInt(int64(brokerID), 10),
+		})
+		for _, dir := range dirs {
+			if dir.ErrorCode != sarama.ErrNoError {
+				scrapeErrors.AddPartial(1, fmt.Errorf("log dir %s of broker %d: %w", dir.Path, brokerID, dir.ErrorCode))
+				continue
+			}
+			dirMetrics := brokerMetrics.WithLabels(map[string]string{metadata.L.LogDir: dir.Path})
+			for _, topic := range dir.Topics {
+				if !s.topicFilter.MatchString(topic.Topic) {
+					continue
+				}
+				labels, ok := topicLabels[topic.Topic]
+				if !ok {
+					labels, err = s.retentionLabels(topic.Topic)
+					if err != nil {
+						scrapeErrors.AddPartial(1, err)
+						continue
+					}
+					topicLabels[topic.Topic] = labels
+				}
+				topicMetrics := dirMetrics.WithLabels(labels)
+				for _, partition := range topic.Partitions {
+					topicMetrics.WithLabels(map[string]string{
+						metadata.L.Partition: strconv.FormatInt(int64(partition.PartitionID), 10),
+					}).AddGaugeDataPoint(metadata.M.KafkaBrokerLogSize.Name(), partition.Size)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// retentionLabels returns the topic label, and the retention configuration
+// labels of the topic, including the broker defaults.
+func (s *brokerScraper) retentionLabels(topic string) (map[string]string, error) {
+	entries, err := s.clusterAdmin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.TopicResource,
+		Name:        topic,
+		ConfigNames: []string{retentionMsConfig, retentionBytesConfig},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe config of topic %s: %w", topic, err)
+	}
+	labels := map[string]string{metadata.L.Topic: topic}
+	for _, entry := range entries {
+		switch entry.Name {
+		case retentionMsConfig:
+			labels[metadata.L.RetentionMs] = entry.Value
+		case retentionBytesConfig:
+			labels[metadata.L.RetentionBytes] = entry.Value
+		}
+	}
+	return labels, nil
 }
 
 func createBrokerScraper(_ context.Context, cfg Config, saramaConfig *sarama.Config, logger *zap.Logger) (scraperhelper.ResourceMetricsScraper, error) {
+	topicFilter, err := regexp.Compile(cfg.TopicMatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile topic filter: %w", err)
+	}
 	s := brokerScraper{
 		logger:       logger,
+		topicFilter:  topicFilter,
 		config:       cfg,
 		saramaConfig: saramaConfig,
 	}
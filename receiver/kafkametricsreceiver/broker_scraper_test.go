@@ -17,11 +17,16 @@ package kafkametricsreceiver
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver/internal/metadata"
 )
 
 func TestBrokerShutdown(t *testing.T) {
@@ -69,6 +74,7 @@ func TestBrokerScraper_createBrokerScraper(t *testing.T) {
 
 func TestBrokerScraperStart(t *testing.T) {
 	newSaramaClient = mockNewSaramaClient
+	newClusterAdmin = mockNewClusterAdmin
 	sc := sarama.NewConfig()
 	ms, err := createBrokerScraper(context.Background(), Config{}, sc, zap.NewNop())
 	assert.NotNil(t, ms)
@@ -89,13 +95,41 @@ func TestBrokerScraper_startBrokerScraper_handles_client_error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestBrokerScraper_startBrokerScraper_handles_clusterAdmin_error(t *testing.T) {
+	newSaramaClient = func(addrs []string, conf *sarama.Config) (sarama.Client, error) {
+		client := newMockClient()
+		client.Mock.
+			On("Close").Return(nil)
+		return client, nil
+	}
+	newClusterAdmin = func(addrs []string, conf *sarama.Config) (sarama.ClusterAdmin, error) {
+		return nil, fmt.Errorf("new cluster admin failed")
+	}
+	sc := sarama.NewConfig()
+	ms, err := createBrokerScraper(context.Background(), Config{}, sc, zap.NewNop())
+	assert.Nil(t, err)
+	assert.NotNil(t, ms)
+	err = ms.Start(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestBrokerScraper_createBrokerScraper_handles_invalid_topicMatch(t *testing.T) {
+	ms, err := createBrokerScraper(context.Background(), Config{
+		TopicMatch: "[",
+	}, sarama.NewConfig(), zap.NewNop())
+	assert.Error(t, err)
+	assert.Nil(t, ms)
+}
+
 func TestBrokerScraper_scrape(t *testing.T) {
 	client := newMockClient()
 	client.Mock.On("Brokers").Return(testBrokers)
 	bs := brokerScraper{
-		client: client,
-		logger: zap.NewNop(),
-		config: Config{},
+		client:       client,
+		clusterAdmin: newMockClusterAdmin(),
+		logger:       zap.NewNop(),
+		topicFilter:  regexp.MustCompile(defaultTopicMatch),
+		config:       Config{},
 	}
 	ms, err := bs.scrape(context.Background())
 	assert.Nil(t, err)
@@ -113,3 +147,80 @@ func TestBrokersScraper_createBrokerScraper(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, ms)
 }
+
+func TestBrokerScraper_scrapeClusterMetrics(t *testing.T) {
+	client := newMockClient()
+	client.Mock.On("Brokers").Return(testBrokers)
+	bs := brokerScraper{
+		client:       client,
+		clusterAdmin: newMockClusterAdmin(),
+		logger:       zap.NewNop(),
+		topicFilter:  regexp.MustCompile(defaultTopicMatch),
+	}
+	rms, err := bs.scrape(context.Background())
+	require.NoError(t, err)
+
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 5, ms.Len())
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		dps := m.IntGauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			key := m.Name()
+			if broker, ok := dp.LabelsMap().Get(metadata.L.Broker); ok {
+				key += "/" + broker
+			}
+			if partition, ok := dp.LabelsMap().Get(metadata.L.Partition); ok {
+				topic, _ := dp.LabelsMap().Get(metadata.L.Topic)
+				dir, _ := dp.LabelsMap().Get(metadata.L.LogDir)
+				retentionMs, _ := dp.LabelsMap().Get(metadata.L.RetentionMs)
+				retentionBytes, _ := dp.LabelsMap().Get(metadata.L.RetentionBytes)
+				assert.Equal(t, testTopic, topic)
+				assert.Equal(t, "/var/lib/kafka/data", dir)
+				assert.Equal(t, "604800000", retentionMs)
+				assert.Equal(t, "-1", retentionBytes)
+				key += "/" + partition
+			}
+			values[key] = dp.Value()
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"kafka.brokers":                              1,
+		"kafka.controller_id":                        2,
+		"kafka.partitions.offline":                   1,
+		"kafka.broker.under_replicated_partitions/1": 0,
+		"kafka.broker.under_replicated_partitions/2": 1,
+		// the internal topics are filtered out
+		"kafka.broker.log_size/1/0": 1024,
+		"kafka.broker.log_size/1/1": 2000,
+		"kafka.broker.log_size/2/0": 1024,
+		"kafka.broker.log_size/2/1": 2048,
+	}, values)
+}
+
+func TestBrokerScraper_scrapeHandlesErrors(t *testing.T) {
+	client := newMockClient()
+	client.Mock.On("Brokers").Return(testBrokers)
+	clusterAdmin := newMockClusterAdmin()
+	clusterAdmin.topicsMetadata = nil
+	clusterAdmin.configEntries = nil
+	bs := brokerScraper{
+		client:       client,
+		clusterAdmin: clusterAdmin,
+		logger:       zap.NewNop(),
+		topicFilter:  regexp.MustCompile(defaultTopicMatch),
+	}
+	rms, err := bs.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, 2, ms.Len())
+
+	clusterAdmin.clusterBrokers = nil
+	rms, err = bs.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, 1, rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+}
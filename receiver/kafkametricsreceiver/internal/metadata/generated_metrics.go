@@ -55,48 +55,60 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	KafkaBrokers                 MetricIntf
-	KafkaConsumerGroupLag        MetricIntf
-	KafkaConsumerGroupLagSum     MetricIntf
-	KafkaConsumerGroupMembers    MetricIntf
-	KafkaConsumerGroupOffset     MetricIntf
-	KafkaConsumerGroupOffsetSum  MetricIntf
-	KafkaPartitionCurrentOffset  MetricIntf
-	KafkaPartitionOldestOffset   MetricIntf
-	KafkaPartitionReplicas       MetricIntf
-	KafkaPartitionReplicasInSync MetricIntf
-	KafkaTopicPartitions         MetricIntf
+	KafkaBrokerLogSize                   MetricIntf
+	KafkaBrokerUnderReplicatedPartitions MetricIntf
+	KafkaBrokers                         MetricIntf
+	KafkaConsumerGroupLag                MetricIntf
+	KafkaConsumerGroupLagSum             MetricIntf
+	KafkaConsumerGroupMembers            MetricIntf
+	KafkaConsumerGroupOffset             MetricIntf
+	KafkaConsumerGroupOffsetSum          MetricIntf
+	KafkaControllerId                    MetricIntf
+	KafkaPartitionCurrentOffset          MetricIntf
+	KafkaPartitionOldestOffset           MetricIntf
+	KafkaPartitionReplicas               MetricIntf
+	KafkaPartitionReplicasInSync         MetricIntf
+	KafkaPartitionsOffline               MetricIntf
+	KafkaTopicPartitions                 MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
 func (m *metricStruct) Names() []string {
 	return []string{
+		"kafka.broker.log_size",
+		"kafka.broker.under_replicated_partitions",
 		"kafka.brokers",
 		"kafka.consumer_group.lag",
 		"kafka.consumer_group.lag_sum",
 		"kafka.consumer_group.members",
 		"kafka.consumer_group.offset",
 		"kafka.consumer_group.offset_sum",
+		"kafka.controller_id",
 		"kafka.partition.current_offset",
 		"kafka.partition.oldest_offset",
 		"kafka.partition.replicas",
 		"kafka.partition.replicas_in_sync",
+		"kafka.partitions.offline",
 		"kafka.topic.partitions",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"kafka.brokers":                    Metrics.KafkaBrokers,
-	"kafka.consumer_group.lag":         Metrics.KafkaConsumerGroupLag,
-	"kafka.consumer_group.lag_sum":     Metrics.KafkaConsumerGroupLagSum,
-	"kafka.consumer_group.members":     Metrics.KafkaConsumerGroupMembers,
-	"kafka.consumer_group.offset":      Metrics.KafkaConsumerGroupOffset,
-	"kafka.consumer_group.offset_sum":  Metrics.KafkaConsumerGroupOffsetSum,
-	"kafka.partition.current_offset":   Metrics.KafkaPartitionCurrentOffset,
-	"kafka.partition.oldest_offset":    Metrics.KafkaPartitionOldestOffset,
-	"kafka.partition.replicas":         Metrics.KafkaPartitionReplicas,
-	"kafka.partition.replicas_in_sync": Metrics.KafkaPartitionReplicasInSync,
-	"kafka.topic.partitions":           Metrics.KafkaTopicPartitions,
+	"kafka.broker.log_size":                    Metrics.KafkaBrokerLogSize,
+	"kafka.broker.under_replicated_partitions": Metrics.KafkaBrokerUnderReplicatedPartitions,
+	"kafka.brokers":                            Metrics.KafkaBrokers,
+	"kafka.consumer_group.lag":                 Metrics.KafkaConsumerGroupLag,
+	"kafka.consumer_group.lag_sum":             Metrics.KafkaConsumerGroupLagSum,
+	"kafka.consumer_group.members":             Metrics.KafkaConsumerGroupMembers,
+	"kafka.consumer_group.offset":              Metrics.KafkaConsumerGroupOffset,
+	"kafka.consumer_group.offset_sum":          Metrics.KafkaConsumerGroupOffsetSum,
+	"kafka.controller_id":                      Metrics.KafkaControllerId,
+	"kafka.partition.current_offset":           Metrics.KafkaPartitionCurrentOffset,
+	"kafka.partition.oldest_offset":            Metrics.KafkaPartitionOldestOffset,
+	"kafka.partition.replicas":                 Metrics.KafkaPartitionReplicas,
+	"kafka.partition.replicas_in_sync":         Metrics.KafkaPartitionReplicasInSync,
+	"kafka.partitions.offline":                 Metrics.KafkaPartitionsOffline,
+	"kafka.topic.partitions":                   Metrics.KafkaTopicPartitions,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -105,23 +117,45 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.KafkaBrokers.Name():                 Metrics.KafkaBrokers.Init,
-		Metrics.KafkaConsumerGroupLag.Name():        Metrics.KafkaConsumerGroupLag.Init,
-		Metrics.KafkaConsumerGroupLagSum.Name():     Metrics.KafkaConsumerGroupLagSum.Init,
-		Metrics.KafkaConsumerGroupMembers.Name():    Metrics.KafkaConsumerGroupMembers.Init,
-		Metrics.KafkaConsumerGroupOffset.Name():     Metrics.KafkaConsumerGroupOffset.Init,
-		Metrics.KafkaConsumerGroupOffsetSum.Name():  Metrics.KafkaConsumerGroupOffsetSum.Init,
-		Metrics.KafkaPartitionCurrentOffset.Name():  Metrics.KafkaPartitionCurrentOffset.Init,
-		Metrics.KafkaPartitionOldestOffset.Name():   Metrics.KafkaPartitionOldestOffset.Init,
-		Metrics.KafkaPartitionReplicas.Name():       Metrics.KafkaPartitionReplicas.Init,
-		Metrics.KafkaPartitionReplicasInSync.Name(): Metrics.KafkaPartitionReplicasInSync.Init,
-		Metrics.KafkaTopicPartitions.Name():         Metrics.KafkaTopicPartitions.Init,
+		Metrics.KafkaBrokerLogSize.Name():                   Metrics.KafkaBrokerLogSize.Init,
+		Metrics.KafkaBrokerUnderReplicatedPartitions.Name(): Metrics.KafkaBrokerUnderReplicatedPartitions.Init,
+		Metrics.KafkaBrokers.Name():                         Metrics.KafkaBrokers.Init,
+		Metrics.KafkaConsumerGroupLag.Name():                Metrics.KafkaConsumerGroupLag.Init,
+		Metrics.KafkaConsumerGroupLagSum.Name():             Metrics.KafkaConsumerGroupLagSum.Init,
+		Metrics.KafkaConsumerGroupMembers.Name():            Metrics.KafkaConsumerGroupMembers.Init,
+		Metrics.KafkaConsumerGroupOffset.Name():             Metrics.KafkaConsumerGroupOffset.Init,
+		Metrics.KafkaConsumerGroupOffsetSum.Name():          Metrics.KafkaConsumerGroupOffsetSum.Init,
+		Metrics.KafkaControllerId.Name():                    Metrics.KafkaControllerId.Init,
+		Metrics.KafkaPartitionCurrentOffset.Name():          Metrics.KafkaPartitionCurrentOffset.Init,
+		Metrics.KafkaPartitionOldestOffset.Name():           Metrics.KafkaPartitionOldestOffset.Init,
+		Metrics.KafkaPartitionReplicas.Name():               Metrics.KafkaPartitionReplicas.Init,
+		Metrics.KafkaPartitionReplicasInSync.Name():         Metrics.KafkaPartitionReplicasInSync.Init,
+		Metrics.KafkaPartitionsOffline.Name():               Metrics.KafkaPartitionsOffline.Init,
+		Metrics.KafkaTopicPartitions.Name():                 Metrics.KafkaTopicPartitions.Init,
 	}
 }
 
 // Metrics contains a set of methods for each metric that help with
 // manipulating those metrics.
 var Metrics = &metricStruct{
+	&metricImpl{
+		"kafka.broker.log_size",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.broker.log_size")
+			metric.SetDescription("Size of the log segments of the replica of partition of topic in a log directory of the broker.")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"kafka.broker.under_replicated_partitions",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.broker.under_replicated_partitions")
+			metric.SetDescription("Number of partitions led by the broker with fewer in-sync replicas than replicas.")
+			metric.SetUnit("{partitions}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"kafka.brokers",
 		func(metric pdata.Metric) {
@@ -176,6 +210,15 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"kafka.controller_id",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.controller_id")
+			metric.SetDescription("ID of the broker acting as the controller of the cluster.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"kafka.partition.current_offset",
 		func(metric pdata.Metric) {
@@ -212,6 +255,15 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"kafka.partitions.offline",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.partitions.offline")
+			metric.SetDescription("Number of partitions without an active leader.")
+			metric.SetUnit("{partitions}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"kafka.topic.partitions",
 		func(metric pdata.Metric) {
@@ -229,15 +281,27 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
+	// Broker (The ID (integer) of a broker)
+	Broker string
 	// Group (The ID (string) of a consumer group)
 	Group string
+	// LogDir (The absolute path of a log directory of a broker)
+	LogDir string
 	// Partition (The number (integer) of the partition)
 	Partition string
+	// RetentionBytes (The retention.bytes configuration of the topic)
+	RetentionBytes string
+	// RetentionMs (The retention.ms configuration of the topic)
+	RetentionMs string
 	// Topic (The ID (integer) of a topic)
 	Topic string
 }{
+	"broker",
 	"group",
+	"log_dir",
 	"partition",
+	"retention_bytes",
+	"retention_ms",
 	"topic",
 }
 
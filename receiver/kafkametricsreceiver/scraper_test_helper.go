@@ -134,6 +134,11 @@ type mockClusterAdmin struct {
 	consumerGroups            map[string]string
 	consumerGroupDescriptions []*sarama.GroupDescription
 	consumerGroupOffsets      *sarama.OffsetFetchResponse
+	clusterBrokers            []*sarama.Broker
+	controllerID              int32
+	topicsMetadata            []*sarama.TopicMetadata
+	logDirs                   map[int32][]sarama.DescribeLogDirsResponseDirMetadata
+	configEntries             map[string][]sarama.ConfigEntry
 }
 
 func (s *mockClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
@@ -164,6 +169,39 @@ func (s *mockClusterAdmin) ListConsumerGroupOffsets(string, map[string][]int32)
 	return s.consumerGroupOffsets, nil
 }
 
+func (s *mockClusterAdmin) DescribeCluster() ([]*sarama.Broker, int32, error) {
+	if s.clusterBrokers == nil {
+		return nil, -1, fmt.Errorf("mock describe cluster error")
+	}
+	return s.clusterBrokers, s.controllerID, nil
+}
+
+func (s *mockClusterAdmin) DescribeTopics([]string) ([]*sarama.TopicMetadata, error) {
+	if s.topicsMetadata == nil {
+		return nil, fmt.Errorf("mock describe topics error")
+	}
+	return s.topicsMetadata, nil
+}
+
+func (s *mockClusterAdmin) DescribeLogDirs([]int32) (map[int32][]sarama.DescribeLogDirsResponseDirMetadata, error) {
+	if s.logDirs == nil {
+		return nil, fmt.Errorf("mock describe log dirs error")
+	}
+	return s.logDirs, nil
+}
+
+func (s *mockClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	entries, ok := s.configEntries[resource.Name]
+	if !ok {
+		return nil, fmt.Errorf("mock describe config error")
+	}
+	return entries, nil
+}
+
+func (s *mockClusterAdmin) Close() error {
+	return nil
+}
+
 func newMockClusterAdmin() *mockClusterAdmin {
 	clusterAdmin := new(mockClusterAdmin)
 	r := make(map[string]string)
@@ -199,5 +237,61 @@ func newMockClusterAdmin() *mockClusterAdmin {
 	}
 	clusterAdmin.consumerGroupOffsets = &offsetRes
 
+	// brokers 1 and 2, the partition 1 of the test topic being under-replicated
+	// and the partition of the offsets topic offline
+	md := sarama.MetadataResponse{}
+	md.AddBroker(testBroker+":9092", 1)
+	md.AddBroker(testBroker+":9093", 2)
+	clusterAdmin.clusterBrokers = md.Brokers
+	clusterAdmin.controllerID = 2
+	clusterAdmin.topicsMetadata = []*sarama.TopicMetadata{
+		{
+			Name: testTopic,
+			Partitions: []*sarama.PartitionMetadata{
+				{ID: 0, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}},
+				{ID: 1, Leader: 2, Replicas: []int32{1, 2}, Isr: []int32{2}},
+			},
+		},
+		{
+			Name: "__consumer_offsets",
+			Partitions: []*sarama.PartitionMetadata{
+				{ID: 0, Leader: -1, Replicas: []int32{1}, Isr: []int32{}},
+			},
+		},
+	}
+	clusterAdmin.logDirs = map[int32][]sarama.DescribeLogDirsResponseDirMetadata{
+		1: {
+			{
+				Path: "/var/lib/kafka/data",
+				Topics: []sarama.DescribeLogDirsResponseTopic{
+					{Topic: testTopic, Partitions: []sarama.DescribeLogDirsResponsePartition{
+						{PartitionID: 0, Size: 1024},
+						{PartitionID: 1, Size: 2000},
+					}},
+					{Topic: "__consumer_offsets", Partitions: []sarama.DescribeLogDirsResponsePartition{
+						{PartitionID: 0, Size: 300},
+					}},
+				},
+			},
+		},
+		2: {
+			{
+				Path: "/var/lib/kafka/data",
+				Topics: []sarama.DescribeLogDirsResponseTopic{
+					{Topic: testTopic, Partitions: []sarama.DescribeLogDirsResponsePartition{
+						{PartitionID: 0, Size: 1024},
+						{PartitionID: 1, Size: 2048},
+					}},
+				},
+			},
+		},
+	}
+	clusterAdmin.configEntries = map[string][]sarama.ConfigEntry{
+		testTopic: {
+			{Name: "retention.ms", Value: "604800000", Default: true},
+			{Name: "retention.bytes", Value: "-1", Default: true},
+		},
+	}
+
 	return clusterAdmin
 }
@@ -33,13 +33,21 @@ const (
 	PodMetricGroup       = MetricGroup("pod")
 	NodeMetricGroup      = MetricGroup("node")
 	VolumeMetricGroup    = MetricGroup("volume")
+	CadvisorMetricGroup  = MetricGroup("cadvisor")
+	ResourceMetricGroup  = MetricGroup("resource")
+	// UtilizationMetricGroup are the usages relative to the requests and
+	// limits of the pods and containers, they need their specs.
+	UtilizationMetricGroup = MetricGroup("utilization")
 )
 
 var ValidMetricGroups = map[MetricGroup]bool{
-	ContainerMetricGroup: true,
-	PodMetricGroup:       true,
-	NodeMetricGroup:      true,
-	VolumeMetricGroup:    true,
+	ContainerMetricGroup:   true,
+	PodMetricGroup:         true,
+	NodeMetricGroup:        true,
+	VolumeMetricGroup:      true,
+	CadvisorMetricGroup:    true,
+	ResourceMetricGroup:    true,
+	UtilizationMetricGroup: true,
 }
 
 type metricDataAccumulator struct {
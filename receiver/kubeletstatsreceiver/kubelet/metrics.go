@@ -24,6 +24,7 @@ import (
 
 func MetricsData(
 	logger *zap.Logger, summary *stats.Summary,
+	promMetrics PrometheusMetrics,
 	metadata Metadata, typeStr string,
 	metricGroupsToCollect map[MetricGroup]bool) []*agentmetricspb.ExportMetricsServiceRequest {
 	acc := &metricDataAccumulator{
@@ -46,6 +47,15 @@ func MetricsData(
 			acc.volumeStats(podResource, volumeStats)
 		}
 	}
+	if metricGroupsToCollect[CadvisorMetricGroup] {
+		acc.prometheusStats(summary, promMetrics.Cadvisor, cadvisorMetrics)
+	}
+	if metricGroupsToCollect[ResourceMetricGroup] {
+		acc.prometheusStats(summary, promMetrics.Resource, resourceMetrics)
+	}
+	if metricGroupsToCollect[UtilizationMetricGroup] {
+		acc.utilizationStats(summary)
+	}
 	for _, md := range acc.m {
 		// TODO this should prob go in core
 		md.Resource.Labels["receiver"] = typeStr
@@ -36,6 +36,14 @@ func (f fakeRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f fakeRestClient) CadvisorMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/metrics-cadvisor.txt")
+}
+
+func (f fakeRestClient) ResourceMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/metrics-resource.txt")
+}
+
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
 	statsProvider := NewStatsProvider(rc)
@@ -43,10 +51,10 @@ func TestMetricAccumulator(t *testing.T) {
 	metadataProvider := NewMetadataProvider(rc)
 	podsMetadata, _ := metadataProvider.Pods()
 	metadata := NewMetadata([]MetadataLabel{MetadataLabelContainerID}, podsMetadata, nil)
-	requireMetricsDataOk(t, MetricsData(zap.NewNop(), summary, metadata, "", ValidMetricGroups))
+	requireMetricsDataOk(t, MetricsData(zap.NewNop(), summary, PrometheusMetrics{}, metadata, "", ValidMetricGroups))
 
 	// Disable all groups
-	require.Equal(t, 0, len(MetricsData(zap.NewNop(), summary, metadata, "", map[MetricGroup]bool{})))
+	require.Equal(t, 0, len(MetricsData(zap.NewNop(), summary, PrometheusMetrics{}, metadata, "", map[MetricGroup]bool{})))
 }
 
 func requireMetricsDataOk(t *testing.T, mds []*agentmetricspb.ExportMetricsServiceRequest) {
@@ -170,5 +178,5 @@ func fakeMetrics() []*agentmetricspb.ExportMetricsServiceRequest {
 		PodMetricGroup:       true,
 		NodeMetricGroup:      true,
 	}
-	return MetricsData(zap.NewNop(), summary, Metadata{}, "foo", mgs)
+	return MetricsData(zap.NewNop(), summary, PrometheusMetrics{}, Metadata{}, "foo", mgs)
 }
@@ -0,0 +1,319 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+// PrometheusMetrics contains the metric families scraped from the Prometheus
+// endpoints of the kubelet, a field is nil when its group is not collected.
+type PrometheusMetrics struct {
+	// Cadvisor contains the metrics of the /metrics/cadvisor endpoint.
+	Cadvisor map[string]*dto.MetricFamily
+	// Resource contains the metrics of the /metrics/resource endpoint.
+	Resource map[string]*dto.MetricFamily
+}
+
+// Prometheus labels identifying the resource of a series.
+const (
+	promLabelNamespace = "namespace"
+	promLabelPod       = "pod"
+	promLabelContainer = "container"
+	promLabelInterface = "interface"
+
+	// promPodContainer is the container of the pod sandbox, its series are
+	// the ones of the pod.
+	promPodContainer = "POD"
+)
+
+// promMetric describes how a Prometheus metric family is converted. The
+// series of the families starting with "node_" belong to the node, the ones
+// starting with "pod_" to the pods and the ones starting with "container_"
+// to the containers, or to the pods for the series of the pod sandbox.
+type promMetric struct {
+	// name is the metric name without the node, pod or container prefix.
+	name       string
+	unit       string
+	metricType metricspb.MetricDescriptor_Type
+	// labels are added to every series of the metric.
+	labels map[string]string
+	// promLabels are the Prometheus labels kept as metric labels.
+	promLabels []string
+}
+
+var cadvisorMetrics = map[string]promMetric{
+	"container_cpu_cfs_periods_total": {
+		name: "cpu.cfs.periods", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	"container_cpu_cfs_throttled_periods_total": {
+		name: "cpu.cfs.throttled_periods", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	"container_cpu_cfs_throttled_seconds_total": {
+		name: "cpu.cfs.throttled_time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"container_oom_events_total": {
+		name: "memory.oom_events", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	"container_network_receive_bytes_total": {
+		name: "network.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_transmit_bytes_total": {
+		name: "network.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_receive_errors_total": {
+		name: "network.errors", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_transmit_errors_total": {
+		name: "network.errors", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_receive_packets_total": {
+		name: "network.packets", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_transmit_packets_total": {
+		name: "network.packets", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_receive_packets_dropped_total": {
+		name: "network.dropped", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{promLabelInterface},
+	},
+	"container_network_transmit_packets_dropped_total": {
+		name: "network.dropped", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{promLabelInterface},
+	},
+}
+
+// resourceMetrics have the names of the equivalent summary metrics, they are
+// only added to the resources that don't already have them.
+var resourceMetrics = map[string]promMetric{
+	"node_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"node_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"pod_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"pod_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"container_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"container_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+}
+
+// parsePrometheusMetrics parses metrics in the Prometheus text format.
+func parsePrometheusMetrics(data []byte) (map[string]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(bytes.NewReader(data))
+}
+
+// prometheusStats merges the metrics of a kubelet Prometheus endpoint with the
+// ones of the same resources, the metrics already accumulated from the summary
+// are kept.
+func (a *metricDataAccumulator) prometheusStats(
+	summary *stats.Summary,
+	families map[string]*dto.MetricFamily,
+	mapping map[string]promMetric,
+) {
+	if len(families) == 0 {
+		return
+	}
+
+	pods := make(map[string]stats.PodStats, len(summary.Pods))
+	for _, podStats := range summary.Pods {
+		pods[podStats.PodRef.Namespace+"/"+podStats.PodRef.Name] = podStats
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		if _, ok := mapping[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		family := families[name]
+		pm := mapping[name]
+		for _, m := range family.Metric {
+			resource, prefix, startTime, ok := a.prometheusResource(name, m, summary.Node, pods)
+			if !ok {
+				continue
+			}
+			a.merge(resource, startTime, promToMetric(prefix, pm, family.GetType(), m))
+		}
+	}
+}
+
+// prometheusResource returns the resource of a Prometheus series, the prefix
+// of its metric name and its start time. The series of the system cgroups
+// and of the pods missing from the summary are skipped.
+func (a *metricDataAccumulator) prometheusResource(
+	family string,
+	m *dto.Metric,
+	node stats.NodeStats,
+	pods map[string]stats.PodStats,
+) (*resourcepb.Resource, string, *timestamppb.Timestamp, bool) {
+	if strings.HasPrefix(family, "node_") {
+		return nodeResource(node), nodePrefix, timestamppb.New(node.StartTime.Time), true
+	}
+
+	labels := promLabels(m)
+	podStats, ok := pods[labels[promLabelNamespace]+"/"+labels[promLabelPod]]
+	if !ok {
+		return nil, "", nil, false
+	}
+	podRes := podResource(podStats)
+
+	container := labels[promLabelContainer]
+	if strings.HasPrefix(family, "pod_") || container == "" || container == promPodContainer {
+		return podRes, podPrefix, timestamppb.New(podStats.StartTime.Time), true
+	}
+
+	containerStats := stats.ContainerStats{Name: container, StartTime: podStats.StartTime}
+	for _, cs := range podStats.Containers {
+		if cs.Name == container {
+			containerStats = cs
+			break
+		}
+	}
+	resource, err := containerResource(podRes, containerStats, a.metadata)
+	if err != nil {
+		a.logger.Warn("failed to fetch container metrics", zap.String("pod", podStats.PodRef.Name),
+			zap.String("container", container), zap.Error(err))
+		return nil, "", nil, false
+	}
+	return resource, containerPrefix, timestamppb.New(containerStats.StartTime.Time), true
+}
+
+// merge adds a metric to the accumulated metrics of a resource, unless the
+// resource already has a metric with the same name and labels.
+func (a *metricDataAccumulator) merge(r *resourcepb.Resource, startTime *timestamppb.Timestamp, metric *metricspb.Metric) {
+	if metric == nil {
+		return
+	}
+	applyCurrentTime([]*metricspb.Metric{metric}, a.time)
+	metric.Timeseries[0].StartTimestamp = startTime
+
+	key := resourceKey(r)
+	for _, md := range a.m {
+		if resourceKey(md.Resource) != key {
+			continue
+		}
+		for _, existing := range md.Metrics {
+			if metricKey(existing) == metricKey(metric) {
+				return
+			}
+		}
+		md.Metrics = append(md.Metrics, metric)
+		return
+	}
+
+	a.accumulate(startTime, r, []*metricspb.Metric{metric})
+}
+
+func promToMetric(prefix string, pm promMetric, familyType dto.MetricType, m *dto.Metric) *metricspb.Metric {
+	var value float64
+	switch familyType {
+	case dto.MetricType_COUNTER:
+		value = m.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		value = m.GetGauge().GetValue()
+	case dto.MetricType_UNTYPED:
+		value = m.GetUntyped().GetValue()
+	default:
+		return nil
+	}
+
+	var metric *metricspb.Metric
+	switch pm.metricType {
+	case metricspb.MetricDescriptor_CUMULATIVE_INT64:
+		v := uint64(value)
+		metric = cumulativeInt(prefix+pm.name, &v)
+	case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+		metric = cumulativeDouble(prefix+pm.name, &value)
+	case metricspb.MetricDescriptor_GAUGE_INT64:
+		v := uint64(value)
+		metric = intGauge(prefix+pm.name, pm.unit, &v)
+	default:
+		metric = doubleGauge(prefix+pm.name, pm.unit, &value)
+	}
+	metric.MetricDescriptor.Unit = pm.unit
+
+	if len(pm.labels) > 0 || len(pm.promLabels) > 0 {
+		labels := promLabels(m)
+		attrs := make(map[string]string, len(pm.labels)+len(pm.promLabels))
+		for k, v := range pm.labels {
+			attrs[k] = v
+		}
+		for _, k := range pm.promLabels {
+			attrs[k] = labels[k]
+		}
+		applyLabels(metric, attrs)
+	}
+	return metric
+}
+
+func promLabels(m *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(m.Label))
+	for _, l := range m.Label {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
+
+// resourceKey identifies the node, pod, container or volume of a resource.
+func resourceKey(r *resourcepb.Resource) string {
+	return strings.Join([]string{
+		r.Labels[conventions.AttributeK8sNodeName],
+		r.Labels[conventions.AttributeK8sPodUID],
+		r.Labels[conventions.AttributeK8sContainer],
+		r.Labels[labelVolumeName],
+	}, "/")
+}
+
+// metricKey identifies a metric by its name and labels.
+func metricKey(m *metricspb.Metric) string {
+	parts := []string{m.MetricDescriptor.Name}
+	if len(m.Timeseries) > 0 {
+		for i, k := range m.MetricDescriptor.LabelKeys {
+			parts = append(parts, k.Key+"="+m.Timeseries[0].LabelValues[i].Value)
+		}
+	}
+	sort.Strings(parts[1:])
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,169 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	testPodUID    = "42ad382b-ed0b-446d-9aab-3fdce8b4f9e2"
+	testContainer = "server"
+)
+
+func prometheusMetricsData(t *testing.T, mgs map[MetricGroup]bool) []*agentmetricspb.ExportMetricsServiceRequest {
+	statsProvider := NewStatsProvider(&fakeRestClient{})
+	summary, err := statsProvider.StatsSummary()
+	require.NoError(t, err)
+	var promMetrics PrometheusMetrics
+	promMetrics.Cadvisor, err = statsProvider.CadvisorMetrics()
+	require.NoError(t, err)
+	promMetrics.Resource, err = statsProvider.ResourceMetrics()
+	require.NoError(t, err)
+	return MetricsData(zap.NewNop(), summary, promMetrics, Metadata{}, "", mgs)
+}
+
+func TestCadvisorMetrics(t *testing.T) {
+	mds := prometheusMetricsData(t, map[MetricGroup]bool{CadvisorMetricGroup: true})
+	requireMetricsDataOk(t, mds)
+
+	// the series of the system cgroups and the unmapped families are skipped
+	require.Equal(t, 2, len(mds))
+
+	container := findResourceMetrics(t, mds, testPodUID, testContainer)
+	assert.Equal(t, "go-hello-world-5456b4b8cd-99vxc", container.Resource.Labels[conventions.AttributeK8sPod])
+	assert.Equal(t, "default", container.Resource.Labels[conventions.AttributeK8sNamespace])
+	assert.Equal(t, map[string]interface{}{
+		"container.cpu.cfs.periods":           int64(1200),
+		"container.cpu.cfs.throttled_periods": int64(120),
+		"container.cpu.cfs.throttled_time":    3.5,
+		"container.memory.oom_events":         int64(2),
+	}, metricValues(container.Metrics))
+
+	pod := findResourceMetrics(t, mds, testPodUID, "")
+	assert.Equal(t, map[string]interface{}{
+		"k8s.pod.cpu.cfs.periods":                                  int64(1250),
+		"k8s.pod.network.io,direction=receive,interface=eth0":      int64(15000),
+		"k8s.pod.network.io,direction=receive,interface=tunl0":     int64(500),
+		"k8s.pod.network.io,direction=transmit,interface=tunl0":    int64(700),
+		"k8s.pod.network.dropped,direction=receive,interface=eth0": int64(3),
+	}, metricValues(pod.Metrics))
+}
+
+func TestCadvisorMetricsMergedWithSummary(t *testing.T) {
+	mds := prometheusMetricsData(t, map[MetricGroup]bool{PodMetricGroup: true, CadvisorMetricGroup: true})
+	requireMetricsDataOk(t, mds)
+
+	// the cadvisor pod metrics are added to the summary pod resources, the
+	// container ones to a new resource
+	require.Equal(t, numPods(t)+1, len(mds))
+
+	values := metricValues(findResourceMetrics(t, mds, testPodUID, "").Metrics)
+	// the summary value is kept
+	assert.Equal(t, int64(0), values["k8s.pod.network.io,direction=receive,interface=eth0"])
+	assert.Equal(t, int64(500), values["k8s.pod.network.io,direction=receive,interface=tunl0"])
+	assert.Equal(t, int64(1250), values["k8s.pod.cpu.cfs.periods"])
+}
+
+func TestResourceMetrics(t *testing.T) {
+	mds := prometheusMetricsData(t, map[MetricGroup]bool{ResourceMetricGroup: true})
+	requireMetricsDataOk(t, mds)
+
+	// node, pod and container, the unknown pod is skipped
+	require.Equal(t, 3, len(mds))
+
+	node := findResourceMetrics(t, mds, "", "")
+	assert.Equal(t, "minikube", node.Resource.Labels[conventions.AttributeK8sNodeName])
+	assert.Equal(t, map[string]interface{}{
+		"k8s.node.cpu.time":           4521.25,
+		"k8s.node.memory.working_set": int64(2100000000),
+	}, metricValues(node.Metrics))
+
+	assert.Equal(t, map[string]interface{}{
+		"k8s.pod.cpu.time":           13.0,
+		"k8s.pod.memory.working_set": int64(12500000),
+	}, metricValues(findResourceMetrics(t, mds, testPodUID, "").Metrics))
+
+	assert.Equal(t, map[string]interface{}{
+		"container.cpu.time":           12.5,
+		"container.memory.working_set": int64(12000000),
+	}, metricValues(findResourceMetrics(t, mds, testPodUID, testContainer).Metrics))
+}
+
+func TestResourceMetricsDuplicatedBySummary(t *testing.T) {
+	summaryGroups := map[MetricGroup]bool{
+		NodeMetricGroup:      true,
+		PodMetricGroup:       true,
+		ContainerMetricGroup: true,
+	}
+	expected := prometheusMetricsData(t, summaryGroups)
+
+	summaryGroups[ResourceMetricGroup] = true
+	mds := prometheusMetricsData(t, summaryGroups)
+
+	// the summary provides all the metrics of the resource endpoint
+	require.Equal(t, len(expected), len(mds))
+	for i := range mds {
+		assert.Equal(t, len(expected[i].Metrics), len(mds[i].Metrics))
+	}
+}
+
+func TestParsePrometheusMetricsError(t *testing.T) {
+	_, err := parsePrometheusMetrics([]byte("invalid metric{"))
+	require.Error(t, err)
+}
+
+func numPods(t *testing.T) int {
+	summary, err := NewStatsProvider(&fakeRestClient{}).StatsSummary()
+	require.NoError(t, err)
+	return len(summary.Pods)
+}
+
+// findResourceMetrics returns the metrics of the node when podUID is empty,
+// of a pod when container is empty and of a container otherwise.
+func findResourceMetrics(t *testing.T, mds []*agentmetricspb.ExportMetricsServiceRequest, podUID, container string) *agentmetricspb.ExportMetricsServiceRequest {
+	for _, md := range mds {
+		labels := md.Resource.Labels
+		if labels[conventions.AttributeK8sPodUID] == podUID &&
+			labels[conventions.AttributeK8sContainer] == container &&
+			labels[labelVolumeName] == "" {
+			return md
+		}
+	}
+	require.Failf(t, "resource not found", "pod %q container %q", podUID, container)
+	return nil
+}
+
+// metricValues returns the values of the metrics by metric key.
+func metricValues(metrics []*metricspb.Metric) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, m := range metrics {
+		point := m.Timeseries[0].Points[0]
+		switch v := point.Value.(type) {
+		case *metricspb.Point_Int64Value:
+			values[metricKey(m)] = v.Int64Value
+		case *metricspb.Point_DoubleValue:
+			values[metricKey(m)] = v.DoubleValue
+		}
+	}
+	return values
+}
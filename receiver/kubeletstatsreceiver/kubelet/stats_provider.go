@@ -17,6 +17,7 @@ package kubelet
 import (
 	"encoding/json"
 
+	dto "github.com/prometheus/client_model/go"
 	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
 )
 
@@ -44,3 +45,23 @@ func (p *StatsProvider) StatsSummary() (*stats.Summary, error) {
 	}
 	return &out, nil
 }
+
+// CadvisorMetrics calls the /metrics/cadvisor kubelet endpoint and parses the
+// Prometheus metric families.
+func (p *StatsProvider) CadvisorMetrics() (map[string]*dto.MetricFamily, error) {
+	data, err := p.rc.CadvisorMetrics()
+	if err != nil {
+		return nil, err
+	}
+	return parsePrometheusMetrics(data)
+}
+
+// ResourceMetrics calls the /metrics/resource kubelet endpoint and parses the
+// Prometheus metric families.
+func (p *StatsProvider) ResourceMetrics() (map[string]*dto.MetricFamily, error) {
+	data, err := p.rc.ResourceMetrics()
+	if err != nil {
+		return nil, err
+	}
+	return parsePrometheusMetrics(data)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+// k8sContainerPrefix is the prefix of the container utilization metrics,
+// they are named after the k8s resources they are relative to.
+const k8sContainerPrefix = k8sPrefix + "container."
+
+// utilizationStats adds the CPU and memory usage of the pods and containers
+// relative to their requests and limits, fetched from the /pods endpoint.
+func (a *metricDataAccumulator) utilizationStats(summary *stats.Summary) {
+	if a.metadata.PodsMetadata == nil {
+		return
+	}
+
+	pods := make(map[types.UID]*v1.Pod, len(a.metadata.PodsMetadata.Items))
+	for i := range a.metadata.PodsMetadata.Items {
+		pod := &a.metadata.PodsMetadata.Items[i]
+		pods[pod.UID] = pod
+	}
+
+	for _, podStats := range summary.Pods {
+		pod, ok := pods[types.UID(podStats.PodRef.UID)]
+		if !ok {
+			continue
+		}
+
+		podRes := podResource(podStats)
+		for _, metric := range utilizationMetrics(podPrefix, podStats.CPU, podStats.Memory, podRequirements(pod)) {
+			a.merge(podRes, nil, metric)
+		}
+
+		for _, containerStats := range podStats.Containers {
+			spec, ok := containerSpec(pod, containerStats.Name)
+			if !ok {
+				continue
+			}
+			resource, err := containerResource(podRes, containerStats, a.metadata)
+			if err != nil {
+				a.logger.Warn("failed to fetch container metrics", zap.String("pod", podRes.Labels[conventions.AttributeK8sPod]),
+					zap.String("container", containerStats.Name), zap.Error(err))
+				continue
+			}
+			for _, metric := range utilizationMetrics(k8sContainerPrefix, containerStats.CPU, containerStats.Memory, spec.Resources) {
+				a.merge(resource, nil, metric)
+			}
+		}
+	}
+}
+
+// utilizationMetrics returns the CPU usage and memory working set relative to
+// the requests and limits, the metrics of the missing ones are skipped.
+func utilizationMetrics(prefix string, cpu *stats.CPUStats, mem *stats.MemoryStats, r v1.ResourceRequirements) []*metricspb.Metric {
+	var metrics []*metricspb.Metric
+	if cpu != nil && cpu.UsageNanoCores != nil {
+		usage := float64(*cpu.UsageNanoCores) / 1_000_000_000
+		metrics = append(metrics,
+			utilizationMetric(prefix+"cpu_limit_utilization", usage, r.Limits, v1.ResourceCPU),
+			utilizationMetric(prefix+"cpu_request_utilization", usage, r.Requests, v1.ResourceCPU),
+		)
+	}
+	if mem != nil && mem.WorkingSetBytes != nil {
+		usage := float64(*mem.WorkingSetBytes)
+		metrics = append(metrics,
+			utilizationMetric(prefix+"memory_limit_utilization", usage, r.Limits, v1.ResourceMemory),
+			utilizationMetric(prefix+"memory_request_utilization", usage, r.Requests, v1.ResourceMemory),
+		)
+	}
+	return metrics
+}
+
+func utilizationMetric(metricName string, usage float64, resources v1.ResourceList, name v1.ResourceName) *metricspb.Metric {
+	quantity, ok := resources[name]
+	if !ok {
+		return nil
+	}
+	// CPU quantities are in cores and memory ones in bytes
+	capacity := float64(quantity.MilliValue()) / 1000
+	if capacity <= 0 {
+		return nil
+	}
+	value := usage / capacity
+	return doubleGauge(metricName, "1", &value)
+}
+
+// podRequirements returns the sum of the requests and limits of the
+// containers of a pod, a resource is only set when all the containers have
+// it. The init containers are ignored since they don't run along the others.
+func podRequirements(pod *v1.Pod) v1.ResourceRequirements {
+	r := v1.ResourceRequirements{
+		Limits:   v1.ResourceList{},
+		Requests: v1.ResourceList{},
+	}
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		sumResource(pod.Spec.Containers, name, r.Limits, func(c v1.Container) v1.ResourceList { return c.Resources.Limits })
+		sumResource(pod.Spec.Containers, name, r.Requests, func(c v1.Container) v1.ResourceList { return c.Resources.Requests })
+	}
+	return r
+}
+
+func sumResource(containers []v1.Container, name v1.ResourceName, out v1.ResourceList, list func(v1.Container) v1.ResourceList) {
+	if len(containers) == 0 {
+		return
+	}
+	var sum resource.Quantity
+	for _, c := range containers {
+		q, ok := list(c)[name]
+		if !ok {
+			return
+		}
+		sum.Add(q)
+	}
+	out[name] = sum
+}
+
+func containerSpec(pod *v1.Pod, name string) (v1.Container, bool) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return v1.Container{}, false
+}
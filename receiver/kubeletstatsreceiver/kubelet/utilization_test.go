@@ -0,0 +1,98 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const corednsPodUID = "0adffe8e-9849-4e05-b4cd-92d2d1e1f1c3"
+
+func utilizationMetricsData(t *testing.T, withPods bool) []*agentmetricspb.ExportMetricsServiceRequest {
+	rc := &fakeRestClient{}
+	summary, err := NewStatsProvider(rc).StatsSummary()
+	require.NoError(t, err)
+	var podsMetadata *v1.PodList
+	if withPods {
+		podsMetadata, err = NewMetadataProvider(rc).Pods()
+		require.NoError(t, err)
+	}
+	metadata := NewMetadata(nil, podsMetadata, nil)
+	return MetricsData(zap.NewNop(), summary, PrometheusMetrics{}, metadata, "",
+		map[MetricGroup]bool{UtilizationMetricGroup: true})
+}
+
+func TestUtilizationMetrics(t *testing.T) {
+	mds := utilizationMetricsData(t, true)
+	requireMetricsDataOk(t, mds)
+
+	// only the coredns pod of the test data has requests and limits
+	require.Equal(t, 2, len(mds))
+
+	pod := metricValues(findResourceMetrics(t, mds, corednsPodUID, "").Metrics)
+	require.Equal(t, 3, len(pod))
+	assert.InDelta(t, 0.03430175, pod["k8s.pod.cpu_request_utilization"], 1e-9)
+	assert.InDelta(t, 6934528.0/73400320, pod["k8s.pod.memory_request_utilization"], 1e-9)
+	assert.InDelta(t, 6934528.0/178257920, pod["k8s.pod.memory_limit_utilization"], 1e-9)
+
+	container := metricValues(findResourceMetrics(t, mds, corednsPodUID, "coredns").Metrics)
+	require.Equal(t, 3, len(container))
+	assert.InDelta(t, 0.03508506, container["k8s.container.cpu_request_utilization"], 1e-9)
+	assert.InDelta(t, 6250496.0/73400320, container["k8s.container.memory_request_utilization"], 1e-9)
+	assert.InDelta(t, 6250496.0/178257920, container["k8s.container.memory_limit_utilization"], 1e-9)
+}
+
+func TestUtilizationMetricsWithoutPods(t *testing.T) {
+	require.Equal(t, 0, len(utilizationMetricsData(t, false)))
+}
+
+func TestPodRequirements(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Resources: resourceRequirements("1", "1Gi")},
+		},
+		Containers: []v1.Container{
+			{Resources: resourceRequirements("250m", "64Mi")},
+			{Resources: resourceRequirements("500m", "")},
+		},
+	}}
+
+	r := podRequirements(pod)
+	assert.Equal(t, int64(750), r.Limits.Cpu().MilliValue())
+	assert.Equal(t, int64(750), r.Requests.Cpu().MilliValue())
+	// the memory is not set by all the containers
+	_, ok := r.Limits[v1.ResourceMemory]
+	assert.False(t, ok)
+	_, ok = r.Requests[v1.ResourceMemory]
+	assert.False(t, ok)
+}
+
+func resourceRequirements(cpu, memory string) v1.ResourceRequirements {
+	list := v1.ResourceList{}
+	if cpu != "" {
+		list[v1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		list[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return v1.ResourceRequirements{Limits: list, Requests: list}
+}
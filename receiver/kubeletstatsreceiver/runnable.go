@@ -88,8 +88,8 @@ func (r *runnable) Run() error {
 	}
 
 	var podsMetadata *v1.PodList
-	// fetch metadata only when extra metadata labels are needed
-	if len(r.extraMetadataLabels) > 0 {
+	// fetch metadata only when extra metadata labels or the pod specs are needed
+	if len(r.extraMetadataLabels) > 0 || r.metricGroupsToCollect[kubelet.UtilizationMetricGroup] {
 		podsMetadata, err = r.metadataProvider.Pods()
 		if err != nil {
 			r.logger.Error("call to /pods endpoint failed", zap.Error(err))
@@ -97,8 +97,23 @@ func (r *runnable) Run() error {
 		}
 	}
 
+	var promMetrics kubelet.PrometheusMetrics
+	if r.metricGroupsToCollect[kubelet.CadvisorMetricGroup] {
+		// the summary metrics are still reported when the endpoint fails
+		promMetrics.Cadvisor, err = r.statsProvider.CadvisorMetrics()
+		if err != nil {
+			r.logger.Error("call to /metrics/cadvisor endpoint failed", zap.Error(err))
+		}
+	}
+	if r.metricGroupsToCollect[kubelet.ResourceMetricGroup] {
+		promMetrics.Resource, err = r.statsProvider.ResourceMetrics()
+		if err != nil {
+			r.logger.Error("call to /metrics/resource endpoint failed", zap.Error(err))
+		}
+	}
+
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata, r.detailedPVCLabelsSetter())
-	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
+	mds := kubelet.MetricsData(r.logger, summary, promMetrics, metadata, typeStr, r.metricGroupsToCollect)
 	metrics := pdata.NewMetrics()
 	for i := range mds {
 		internaldata.OCToMetrics(mds[i].Node, mds[i].Resource, mds[i].Metrics).ResourceMetrics().MoveAndAppendTo(metrics.ResourceMetrics())
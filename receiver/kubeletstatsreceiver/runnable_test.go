@@ -186,6 +186,24 @@ func TestRunnableWithMetricGroups(t *testing.T) {
 			},
 			dataLen: numNodes*nodeMetrics + numPods*podMetrics,
 		},
+		{
+			name: "node and resource groups",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.NodeMetricGroup:     true,
+				kubelet.ResourceMetricGroup: true,
+			},
+			// the node metrics of the resource endpoint are already provided
+			// by the summary, the pod and container ones are added
+			dataLen: numNodes*nodeMetrics + 4,
+		},
+		{
+			name: "utilization group",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.UtilizationMetricGroup: true,
+			},
+			// the requests and limits of the coredns pod and container
+			dataLen: 6,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -407,9 +425,11 @@ func TestClientErrors(t *testing.T) {
 		name                  string
 		statsSummaryFail      bool
 		podsFail              bool
+		cadvisorMetricsFail   bool
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect map[kubelet.MetricGroup]bool
 		numLogs               int
+		numMetrics            int
 	}{
 		{
 			name:                  "no_errors_without_metadata",
@@ -443,6 +463,16 @@ func TestClientErrors(t *testing.T) {
 			metricGroupsToCollect: allMetricGroups,
 			numLogs:               1,
 		},
+		{
+			name:                "cadvisor_endpoint_error",
+			cadvisorMetricsFail: true,
+			metricGroupsToCollect: map[kubelet.MetricGroup]bool{
+				kubelet.NodeMetricGroup:     true,
+				kubelet.CadvisorMetricGroup: true,
+			},
+			numLogs:    1,
+			numMetrics: nodeMetrics,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -451,12 +481,14 @@ func TestClientErrors(t *testing.T) {
 				extraMetadataLabels:   test.extraMetadataLabels,
 				metricGroupsToCollect: test.metricGroupsToCollect,
 			}
+			sink := new(consumertest.MetricsSink)
 			r := newRunnable(
 				context.Background(),
-				new(consumertest.MetricsSink),
+				sink,
 				&fakeRestClient{
-					statsSummaryFail: test.statsSummaryFail,
-					podsFail:         test.podsFail,
+					statsSummaryFail:    test.statsSummaryFail,
+					podsFail:            test.podsFail,
+					cadvisorMetricsFail: test.cadvisorMetricsFail,
 				},
 				zap.New(core),
 				options,
@@ -466,6 +498,10 @@ func TestClientErrors(t *testing.T) {
 			err = r.Run()
 			require.NoError(t, err)
 			require.Equal(t, test.numLogs, observedLogs.Len())
+			if test.numMetrics > 0 {
+				// the summary metrics are reported despite the error
+				require.Equal(t, test.numMetrics, sink.MetricsCount())
+			}
 		})
 	}
 }
@@ -505,8 +541,9 @@ func TestConsumerErrors(t *testing.T) {
 var _ kubelet.RestClient = (*fakeRestClient)(nil)
 
 type fakeRestClient struct {
-	statsSummaryFail bool
-	podsFail         bool
+	statsSummaryFail    bool
+	podsFail            bool
+	cadvisorMetricsFail bool
 }
 
 func (f *fakeRestClient) StatsSummary() ([]byte, error) {
@@ -522,3 +559,14 @@ func (f *fakeRestClient) Pods() ([]byte, error) {
 	}
 	return ioutil.ReadFile("testdata/pods.json")
 }
+
+func (f *fakeRestClient) CadvisorMetrics() ([]byte, error) {
+	if f.cadvisorMetricsFail {
+		return nil, errors.New("")
+	}
+	return ioutil.ReadFile("testdata/metrics-cadvisor.txt")
+}
+
+func (f *fakeRestClient) ResourceMetrics() ([]byte, error) {
+	return ioutil.ReadFile("testdata/metrics-resource.txt")
+}
@@ -27,4 +27,10 @@ type Config struct {
 
 	// Timeout for the memcache stats request
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Whether to collect the per slab class metrics of `stats slabs`.
+	SlabStats bool `mapstructure:"slab_stats"`
+
+	// Whether to collect the per slab class metrics of `stats items`.
+	ItemStats bool `mapstructure:"item_stats"`
 }
@@ -60,9 +60,23 @@ func TestIntegration(t *testing.T) {
 	require.Equal(t, 1, ilms.Len())
 
 	metrics := ilms.At(0).Metrics()
-	require.Equal(t, 5, metrics.Len())
-
-	assertAllMetricNamesArePresent(t, metadata.Metrics.Names(), metrics)
+	require.Equal(t, 11, metrics.Len())
+
+	// the slab and item metrics are disabled by default, and an empty server
+	// has no slab classes anyway
+	assertAllMetricNamesArePresent(t, []string{
+		metadata.M.MemcachedBytes.Name(),
+		metadata.M.MemcachedCurrentConnections.Name(),
+		metadata.M.MemcachedTotalConnections.Name(),
+		metadata.M.MemcachedGetHits.Name(),
+		metadata.M.MemcachedGetMisses.Name(),
+		metadata.M.MemcachedCurrentItems.Name(),
+		metadata.M.MemcachedEvictions.Name(),
+		metadata.M.MemcachedCommands.Name(),
+		metadata.M.MemcachedNetwork.Name(),
+		metadata.M.MemcachedThreads.Name(),
+		metadata.M.MemcachedCpuUsage.Name(),
+	}, metrics)
 
 	assert.NoError(t, rcvr.Shutdown(context.Background()))
 }
@@ -55,30 +55,75 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	MemcachedBytes              MetricIntf
-	MemcachedCurrentConnections MetricIntf
-	MemcachedGetHits            MetricIntf
-	MemcachedGetMisses          MetricIntf
-	MemcachedTotalConnections   MetricIntf
+	MemcachedBytes               MetricIntf
+	MemcachedCommands            MetricIntf
+	MemcachedCpuUsage            MetricIntf
+	MemcachedCurrentConnections  MetricIntf
+	MemcachedCurrentItems        MetricIntf
+	MemcachedEvictions           MetricIntf
+	MemcachedGetHits             MetricIntf
+	MemcachedGetMisses           MetricIntf
+	MemcachedItemsAge            MetricIntf
+	MemcachedItemsCurrent        MetricIntf
+	MemcachedItemsEvictions      MetricIntf
+	MemcachedItemsOutOfMemory    MetricIntf
+	MemcachedItemsReclaimed      MetricIntf
+	MemcachedNetwork             MetricIntf
+	MemcachedSlabChunkSize       MetricIntf
+	MemcachedSlabChunks          MetricIntf
+	MemcachedSlabMemoryRequested MetricIntf
+	MemcachedSlabPages           MetricIntf
+	MemcachedThreads             MetricIntf
+	MemcachedTotalConnections    MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
 func (m *metricStruct) Names() []string {
 	return []string{
 		"memcached.bytes",
+		"memcached.commands",
+		"memcached.cpu_usage",
 		"memcached.current_connections",
+		"memcached.current_items",
+		"memcached.evictions",
 		"memcached.get_hits",
 		"memcached.get_misses",
+		"memcached.items.age",
+		"memcached.items.current",
+		"memcached.items.evictions",
+		"memcached.items.out_of_memory",
+		"memcached.items.reclaimed",
+		"memcached.network",
+		"memcached.slab.chunk_size",
+		"memcached.slab.chunks",
+		"memcached.slab.memory_requested",
+		"memcached.slab.pages",
+		"memcached.threads",
 		"memcached.total_connections",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"memcached.bytes":               Metrics.MemcachedBytes,
-	"memcached.current_connections": Metrics.MemcachedCurrentConnections,
-	"memcached.get_hits":            Metrics.MemcachedGetHits,
-	"memcached.get_misses":          Metrics.MemcachedGetMisses,
-	"memcached.total_connections":   Metrics.MemcachedTotalConnections,
+	"memcached.bytes":                 Metrics.MemcachedBytes,
+	"memcached.commands":              Metrics.MemcachedCommands,
+	"memcached.cpu_usage":             Metrics.MemcachedCpuUsage,
+	"memcached.current_connections":   Metrics.MemcachedCurrentConnections,
+	"memcached.current_items":         Metrics.MemcachedCurrentItems,
+	"memcached.evictions":             Metrics.MemcachedEvictions,
+	"memcached.get_hits":              Metrics.MemcachedGetHits,
+	"memcached.get_misses":            Metrics.MemcachedGetMisses,
+	"memcached.items.age":             Metrics.MemcachedItemsAge,
+	"memcached.items.current":         Metrics.MemcachedItemsCurrent,
+	"memcached.items.evictions":       Metrics.MemcachedItemsEvictions,
+	"memcached.items.out_of_memory":   Metrics.MemcachedItemsOutOfMemory,
+	"memcached.items.reclaimed":       Metrics.MemcachedItemsReclaimed,
+	"memcached.network":               Metrics.MemcachedNetwork,
+	"memcached.slab.chunk_size":       Metrics.MemcachedSlabChunkSize,
+	"memcached.slab.chunks":           Metrics.MemcachedSlabChunks,
+	"memcached.slab.memory_requested": Metrics.MemcachedSlabMemoryRequested,
+	"memcached.slab.pages":            Metrics.MemcachedSlabPages,
+	"memcached.threads":               Metrics.MemcachedThreads,
+	"memcached.total_connections":     Metrics.MemcachedTotalConnections,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -87,11 +132,26 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.MemcachedBytes.Name():              Metrics.MemcachedBytes.Init,
-		Metrics.MemcachedCurrentConnections.Name(): Metrics.MemcachedCurrentConnections.Init,
-		Metrics.MemcachedGetHits.Name():            Metrics.MemcachedGetHits.Init,
-		Metrics.MemcachedGetMisses.Name():          Metrics.MemcachedGetMisses.Init,
-		Metrics.MemcachedTotalConnections.Name():   Metrics.MemcachedTotalConnections.Init,
+		Metrics.MemcachedBytes.Name():               Metrics.MemcachedBytes.Init,
+		Metrics.MemcachedCommands.Name():            Metrics.MemcachedCommands.Init,
+		Metrics.MemcachedCpuUsage.Name():            Metrics.MemcachedCpuUsage.Init,
+		Metrics.MemcachedCurrentConnections.Name():  Metrics.MemcachedCurrentConnections.Init,
+		Metrics.MemcachedCurrentItems.Name():        Metrics.MemcachedCurrentItems.Init,
+		Metrics.MemcachedEvictions.Name():           Metrics.MemcachedEvictions.Init,
+		Metrics.MemcachedGetHits.Name():             Metrics.MemcachedGetHits.Init,
+		Metrics.MemcachedGetMisses.Name():           Metrics.MemcachedGetMisses.Init,
+		Metrics.MemcachedItemsAge.Name():            Metrics.MemcachedItemsAge.Init,
+		Metrics.MemcachedItemsCurrent.Name():        Metrics.MemcachedItemsCurrent.Init,
+		Metrics.MemcachedItemsEvictions.Name():      Metrics.MemcachedItemsEvictions.Init,
+		Metrics.MemcachedItemsOutOfMemory.Name():    Metrics.MemcachedItemsOutOfMemory.Init,
+		Metrics.MemcachedItemsReclaimed.Name():      Metrics.MemcachedItemsReclaimed.Init,
+		Metrics.MemcachedNetwork.Name():             Metrics.MemcachedNetwork.Init,
+		Metrics.MemcachedSlabChunkSize.Name():       Metrics.MemcachedSlabChunkSize.Init,
+		Metrics.MemcachedSlabChunks.Name():          Metrics.MemcachedSlabChunks.Init,
+		Metrics.MemcachedSlabMemoryRequested.Name(): Metrics.MemcachedSlabMemoryRequested.Init,
+		Metrics.MemcachedSlabPages.Name():           Metrics.MemcachedSlabPages.Init,
+		Metrics.MemcachedThreads.Name():             Metrics.MemcachedThreads.Init,
+		Metrics.MemcachedTotalConnections.Name():    Metrics.MemcachedTotalConnections.Init,
 	}
 }
 
@@ -107,6 +167,28 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"memcached.commands",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.commands")
+			metric.SetDescription("Number of commands executed, by type of command")
+			metric.SetUnit("{commands}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.cpu_usage",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.cpu_usage")
+			metric.SetDescription("Accumulated user and system CPU time of the memcached process")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+			metric.DoubleSum().SetIsMonotonic(true)
+			metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
 	&metricImpl{
 		"memcached.current_connections",
 		func(metric pdata.Metric) {
@@ -116,6 +198,26 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"memcached.current_items",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.current_items")
+			metric.SetDescription("Number of items currently stored in the cache")
+			metric.SetUnit("{items}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.evictions",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.evictions")
+			metric.SetDescription("Number of valid items removed from the cache to free memory for new items")
+			metric.SetUnit("{evictions}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
 	&metricImpl{
 		"memcached.get_hits",
 		func(metric pdata.Metric) {
@@ -138,6 +240,113 @@ var Metrics = &metricStruct{
 			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"memcached.items.age",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.items.age")
+			metric.SetDescription("Age of the oldest item of the slab class")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.items.current",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.items.current")
+			metric.SetDescription("Number of items currently stored in the slab class")
+			metric.SetUnit("{items}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.items.evictions",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.items.evictions")
+			metric.SetDescription("Number of items of the slab class removed from the cache to free memory for new items")
+			metric.SetUnit("{evictions}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.items.out_of_memory",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.items.out_of_memory")
+			metric.SetDescription("Number of times the slab class was unable to store a new item")
+			metric.SetUnit("{errors}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.items.reclaimed",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.items.reclaimed")
+			metric.SetDescription("Number of times an entry of the slab class was stored using the memory of an expired entry")
+			metric.SetUnit("{items}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.network",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.network")
+			metric.SetDescription("Bytes transferred over the network")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.chunk_size",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.chunk_size")
+			metric.SetDescription("Space allocated to each of the items of the slab class")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.chunks",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.chunks")
+			metric.SetDescription("Number of chunks of the slab class, by state")
+			metric.SetUnit("{chunks}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.memory_requested",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.memory_requested")
+			metric.SetDescription("Number of bytes requested to be stored in the slab class")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.pages",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.pages")
+			metric.SetDescription("Number of pages allocated to the slab class")
+			metric.SetUnit("{pages}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.threads",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.threads")
+			metric.SetDescription("Number of threads used by the memcached instance")
+			metric.SetUnit("{threads}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"memcached.total_connections",
 		func(metric pdata.Metric) {
@@ -157,8 +366,56 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
-}{}
+	// Command (The type of command)
+	Command string
+	// Direction (Direction of the network traffic)
+	Direction string
+	// Slab (The slab class ID)
+	Slab string
+	// State (The state of the CPU time or of the slab chunks)
+	State string
+}{
+	"command",
+	"direction",
+	"slab",
+	"state",
+}
 
 // L contains the possible metric labels that can be used. L is an alias for
 // Labels.
 var L = Labels
+
+// LabelCommand are the possible values that the label "command" can have.
+var LabelCommand = struct {
+	Get   string
+	Set   string
+	Touch string
+	Flush string
+}{
+	"get",
+	"set",
+	"touch",
+	"flush",
+}
+
+// LabelDirection are the possible values that the label "direction" can have.
+var LabelDirection = struct {
+	Received string
+	Sent     string
+}{
+	"received",
+	"sent",
+}
+
+// LabelState are the possible values that the label "state" can have.
+var LabelState = struct {
+	System string
+	User   string
+	Used   string
+	Free   string
+}{
+	"system",
+	"user",
+	"used",
+	"free",
+}
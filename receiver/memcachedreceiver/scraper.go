@@ -16,6 +16,7 @@ package memcachedreceiver
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/grobie/gomemcache/memcache"
@@ -85,9 +86,90 @@ func (r *memcachedScraper) scrape(_ context.Context) (pdata.ResourceMetricsSlice
 				metrics.AddSumDataPoint(metadata.M.MemcachedGetHits.Name(), parseInt(v))
 			case "get_misses":
 				metrics.AddSumDataPoint(metadata.M.MemcachedGetMisses.Name(), parseInt(v))
+			case "curr_items":
+				metrics.AddGaugeDataPoint(metadata.M.MemcachedCurrentItems.Name(), parseInt(v))
+			case "evictions":
+				metrics.AddSumDataPoint(metadata.M.MemcachedEvictions.Name(), parseInt(v))
+			case "cmd_get":
+				metrics.WithLabels(map[string]string{metadata.L.Command: metadata.LabelCommand.Get}).
+					AddSumDataPoint(metadata.M.MemcachedCommands.Name(), parseInt(v))
+			case "cmd_set":
+				metrics.WithLabels(map[string]string{metadata.L.Command: metadata.LabelCommand.Set}).
+					AddSumDataPoint(metadata.M.MemcachedCommands.Name(), parseInt(v))
+			case "cmd_touch":
+				metrics.WithLabels(map[string]string{metadata.L.Command: metadata.LabelCommand.Touch}).
+					AddSumDataPoint(metadata.M.MemcachedCommands.Name(), parseInt(v))
+			case "cmd_flush":
+				metrics.WithLabels(map[string]string{metadata.L.Command: metadata.LabelCommand.Flush}).
+					AddSumDataPoint(metadata.M.MemcachedCommands.Name(), parseInt(v))
+			case "bytes_read":
+				metrics.WithLabels(map[string]string{metadata.L.Direction: metadata.LabelDirection.Received}).
+					AddSumDataPoint(metadata.M.MemcachedNetwork.Name(), parseInt(v))
+			case "bytes_written":
+				metrics.WithLabels(map[string]string{metadata.L.Direction: metadata.LabelDirection.Sent}).
+					AddSumDataPoint(metadata.M.MemcachedNetwork.Name(), parseInt(v))
+			case "threads":
+				metrics.AddGaugeDataPoint(metadata.M.MemcachedThreads.Name(), parseInt(v))
+			case "rusage_system":
+				metrics.WithLabels(map[string]string{metadata.L.State: metadata.LabelState.System}).
+					AddDSumDataPoint(metadata.M.MemcachedCpuUsage.Name(), parseFloat(v))
+			case "rusage_user":
+				metrics.WithLabels(map[string]string{metadata.L.State: metadata.LabelState.User}).
+					AddDSumDataPoint(metadata.M.MemcachedCpuUsage.Name(), parseFloat(v))
+			}
+		}
+
+		if r.config.SlabStats {
+			for slab, slabStats := range stats.Slabs {
+				addSlabMetrics(metrics.WithLabels(map[string]string{metadata.L.Slab: strconv.Itoa(slab)}), slabStats)
+			}
+		}
+		if r.config.ItemStats {
+			for slab, itemStats := range stats.Items {
+				addItemMetrics(metrics.WithLabels(map[string]string{metadata.L.Slab: strconv.Itoa(slab)}), itemStats)
 			}
 		}
 	}
 
 	return metrics.Metrics.ResourceMetrics(), nil
 }
+
+// addSlabMetrics adds the metrics of a slab class from the `stats slabs`
+// command, e.g. "STAT 1:chunk_size 96".
+func addSlabMetrics(metrics *simple.Metrics, stats map[string]string) {
+	for k, v := range stats {
+		switch k {
+		case "chunk_size":
+			metrics.AddGaugeDataPoint(metadata.M.MemcachedSlabChunkSize.Name(), parseInt(v))
+		case "total_pages":
+			metrics.AddGaugeDataPoint(metadata.M.MemcachedSlabPages.Name(), parseInt(v))
+		case "used_chunks":
+			metrics.WithLabels(map[string]string{metadata.L.State: metadata.LabelState.Used}).
+				AddGaugeDataPoint(metadata.M.MemcachedSlabChunks.Name(), parseInt(v))
+		case "free_chunks":
+			metrics.WithLabels(map[string]string{metadata.L.State: metadata.LabelState.Free}).
+				AddGaugeDataPoint(metadata.M.MemcachedSlabChunks.Name(), parseInt(v))
+		case "mem_requested":
+			metrics.AddGaugeDataPoint(metadata.M.MemcachedSlabMemoryRequested.Name(), parseInt(v))
+		}
+	}
+}
+
+// addItemMetrics adds the metrics of the items of a slab class from the
+// `stats items` command, e.g. "STAT items:1:number 5".
+func addItemMetrics(metrics *simple.Metrics, stats map[string]string) {
+	for k, v := range stats {
+		switch k {
+		case "number":
+			metrics.AddGaugeDataPoint(metadata.M.MemcachedItemsCurrent.Name(), parseInt(v))
+		case "age":
+			metrics.AddGaugeDataPoint(metadata.M.MemcachedItemsAge.Name(), parseInt(v))
+		case "evicted":
+			metrics.AddSumDataPoint(metadata.M.MemcachedItemsEvictions.Name(), parseInt(v))
+		case "outofmemory":
+			metrics.AddSumDataPoint(metadata.M.MemcachedItemsOutOfMemory.Name(), parseInt(v))
+		case "reclaimed":
+			metrics.AddSumDataPoint(metadata.M.MemcachedItemsReclaimed.Name(), parseInt(v))
+		}
+	}
+}
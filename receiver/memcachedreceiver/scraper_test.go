@@ -0,0 +1,165 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcachedreceiver
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/memcachedreceiver/internal/metadata"
+)
+
+func TestScraper(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startFakeMemcached(t)
+	scraper := &memcachedScraper{logger: zap.NewNop(), config: cfg}
+
+	rms, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 11, metrics.Len())
+
+	m := metricsByName(metrics)
+	assert.Equal(t, int64(412), m[metadata.M.MemcachedBytes.Name()].IntGauge().DataPoints().At(0).Value())
+	assert.Equal(t, int64(2), m[metadata.M.MemcachedEvictions.Name()].IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, int64(4), m[metadata.M.MemcachedThreads.Name()].IntGauge().DataPoints().At(0).Value())
+	assert.Equal(t, map[string]int64{"get": 25, "set": 12, "touch": 3, "flush": 1},
+		intPointsByLabel(m[metadata.M.MemcachedCommands.Name()].IntSum().DataPoints(), metadata.L.Command))
+	assert.Equal(t, map[string]int64{"received": 2215, "sent": 14850},
+		intPointsByLabel(m[metadata.M.MemcachedNetwork.Name()].IntSum().DataPoints(), metadata.L.Direction))
+
+	cpu := m[metadata.M.MemcachedCpuUsage.Name()].DoubleSum().DataPoints()
+	require.Equal(t, 2, cpu.Len())
+	for i := 0; i < cpu.Len(); i++ {
+		state, _ := cpu.At(i).LabelsMap().Get(metadata.L.State)
+		expected := map[string]float64{"system": 0.310667, "user": 0.177524}[state]
+		assert.Equal(t, expected, cpu.At(i).Value())
+	}
+}
+
+func TestScraper_SlabAndItemStats(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startFakeMemcached(t)
+	cfg.SlabStats = true
+	cfg.ItemStats = true
+	scraper := &memcachedScraper{logger: zap.NewNop(), config: cfg}
+
+	rms, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 11+9, metrics.Len())
+
+	m := metricsByName(metrics)
+	assert.Equal(t, map[string]int64{"1": 96, "5": 240},
+		intPointsByLabel(m[metadata.M.MemcachedSlabChunkSize.Name()].IntGauge().DataPoints(), metadata.L.Slab))
+	assert.Equal(t, map[string]int64{"1": 4, "5": 1},
+		intPointsByLabel(m[metadata.M.MemcachedItemsCurrent.Name()].IntGauge().DataPoints(), metadata.L.Slab))
+	assert.Equal(t, map[string]int64{"1": 0, "5": 3},
+		intPointsByLabel(m[metadata.M.MemcachedItemsOutOfMemory.Name()].IntSum().DataPoints(), metadata.L.Slab))
+
+	chunks := m[metadata.M.MemcachedSlabChunks.Name()].IntGauge().DataPoints()
+	require.Equal(t, 4, chunks.Len())
+	for i := 0; i < chunks.Len(); i++ {
+		slab, _ := chunks.At(i).LabelsMap().Get(metadata.L.Slab)
+		state, _ := chunks.At(i).LabelsMap().Get(metadata.L.State)
+		if slab == "1" && state == metadata.LabelState.Used {
+			assert.Equal(t, int64(4), chunks.At(i).Value())
+		}
+	}
+}
+
+func TestScraper_Error(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	cfg.Endpoint = l.Addr().String()
+	require.NoError(t, l.Close())
+	scraper := &memcachedScraper{logger: zap.NewNop(), config: cfg}
+
+	_, err = scraper.scrape(context.Background())
+	require.Error(t, err)
+}
+
+// startFakeMemcached starts a server replaying the testdata responses to the
+// stats commands, and returns its address.
+func startFakeMemcached(t *testing.T) string {
+	responses := map[string]string{}
+	for cmd, file := range map[string]string{
+		"stats":       "stats.txt",
+		"stats slabs": "stats_slabs.txt",
+		"stats items": "stats_items.txt",
+	} {
+		b, err := ioutil.ReadFile(path.Join("testdata", file))
+		require.NoError(t, err)
+		responses[cmd] = strings.ReplaceAll(string(b), "\n", "\r\n")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					resp, ok := responses[strings.TrimSpace(line)]
+					if !ok {
+						resp = "ERROR\r\n"
+					}
+					if _, err := conn.Write([]byte(resp)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func metricsByName(metrics pdata.MetricSlice) map[string]pdata.Metric {
+	m := map[string]pdata.Metric{}
+	for i := 0; i < metrics.Len(); i++ {
+		m[metrics.At(i).Name()] = metrics.At(i)
+	}
+	return m
+}
+
+func intPointsByLabel(dps pdata.IntDataPointSlice, label string) map[string]int64 {
+	values := map[string]int64{}
+	for i := 0; i < dps.Len(); i++ {
+		v, _ := dps.At(i).LabelsMap().Get(label)
+		values[v] = dps.At(i).Value()
+	}
+	return values
+}
@@ -22,4 +22,14 @@ import (
 type Config struct {
 	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
 	confighttp.HTTPClientSettings           `mapstructure:",squash"`
+
+	// PlusEndpoint is the URL of the NGINX Plus API, including its version,
+	// e.g. http://localhost:8080/api/6. The NGINX Plus metrics are only
+	// collected when set.
+	PlusEndpoint string `mapstructure:"plus_endpoint"`
+
+	// VTSEndpoint is the URL of the JSON status of the VTS module, e.g.
+	// http://localhost:80/status/format/json. The VTS metrics are only
+	// collected when set.
+	VTSEndpoint string `mapstructure:"vts_endpoint"`
 }
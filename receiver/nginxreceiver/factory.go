@@ -26,10 +26,14 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/zap"
 )
 
 const (
 	typeStr = "nginx"
+
+	// the stub_status endpoint scraped when none of the endpoints is set
+	defaultEndpoint = "http://localhost:80/status"
 )
 
 // NewFactory creates a factory for nginx receiver.
@@ -47,8 +51,7 @@ func createDefaultConfig() config.Receiver {
 			CollectionInterval: 10 * time.Second,
 		},
 		HTTPClientSettings: confighttp.HTTPClientSettings{
-			Endpoint: "http://localhost:80/status",
-			Timeout:  10 * time.Second,
+			Timeout: 10 * time.Second,
 		},
 	}
 }
@@ -61,11 +64,55 @@ func createMetricsReceiver(
 ) (component.MetricsReceiver, error) {
 	cfg := rConf.(*Config)
 
-	ns := newNginxScraper(params.Logger, cfg)
-	scraper := scraperhelper.NewResourceMetricsScraper(cfg.ID(), ns.scrape)
+	// the stub_status, NGINX Plus and VTS endpoints are scraped independently,
+	// so that the failures of one of them don't prevent the collection of the
+	// others.
+	s := newScrapers(params.Logger, cfg)
+	var options []scraperhelper.ScraperControllerOption
+	if s.stubStatus != nil {
+		options = append(options, scraperhelper.AddResourceMetricsScraper(
+			scraperhelper.NewResourceMetricsScraper(cfg.ID(), s.stubStatus.scrape)))
+	}
+	if s.plus != nil {
+		options = append(options, scraperhelper.AddResourceMetricsScraper(
+			scraperhelper.NewResourceMetricsScraper(cfg.ID(), s.plus.scrape)))
+	}
+	if s.vts != nil {
+		options = append(options, scraperhelper.AddResourceMetricsScraper(
+			scraperhelper.NewResourceMetricsScraper(cfg.ID(), s.vts.scrape)))
+	}
 
 	return scraperhelper.NewScraperControllerReceiver(
-		&cfg.ScraperControllerSettings, params.Logger, consumer,
-		scraperhelper.AddResourceMetricsScraper(scraper),
+		&cfg.ScraperControllerSettings, params.Logger, consumer, options...,
 	)
 }
+
+// scrapers holds the scrapers of the endpoints set in the config, nil for
+// the others.
+type scrapers struct {
+	stubStatus *nginxScraper
+	plus       *nginxPlusScraper
+	vts        *nginxVTSScraper
+}
+
+// newScrapers builds the scrapers of the endpoints set in the config. The
+// stub_status endpoint is scraped when set, or at its default URL when none
+// of the endpoints is set.
+func newScrapers(logger *zap.Logger, cfg *Config) scrapers {
+	var s scrapers
+	switch {
+	case cfg.Endpoint != "":
+		s.stubStatus = newNginxScraper(logger, cfg)
+	case cfg.PlusEndpoint == "" && cfg.VTSEndpoint == "":
+		stubStatusCfg := *cfg
+		stubStatusCfg.Endpoint = defaultEndpoint
+		s.stubStatus = newNginxScraper(logger, &stubStatusCfg)
+	}
+	if cfg.PlusEndpoint != "" {
+		s.plus = newNginxPlusScraper(logger, cfg)
+	}
+	if cfg.VTSEndpoint != "" {
+		s.vts = newNginxVTSScraper(logger, cfg)
+	}
+	return s
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
@@ -54,3 +55,85 @@ func TestCreateMetricsReceiver(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, metricsReceiver)
 }
+
+func TestNewScrapers(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		cfg            Config
+		wantStubStatus string
+		wantPlus       bool
+		wantVTS        bool
+	}{
+		{
+			name:           "default",
+			wantStubStatus: defaultEndpoint,
+		},
+		{
+			name:           "stub_status",
+			cfg:            Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://localhost:8080/status"}},
+			wantStubStatus: "http://localhost:8080/status",
+		},
+		{
+			name:     "plus only",
+			cfg:      Config{PlusEndpoint: "http://localhost:8080/api/6"},
+			wantPlus: true,
+		},
+		{
+			name:    "vts only",
+			cfg:     Config{VTSEndpoint: "http://localhost:8080/status/format/json"},
+			wantVTS: true,
+		},
+		{
+			name: "plus and vts",
+			cfg: Config{
+				PlusEndpoint: "http://localhost:8080/api/6",
+				VTSEndpoint:  "http://localhost:8080/status/format/json",
+			},
+			wantPlus: true,
+			wantVTS:  true,
+		},
+		{
+			name: "all",
+			cfg: Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://localhost:8080/status"},
+				PlusEndpoint:       "http://localhost:8080/api/6",
+				VTSEndpoint:        "http://localhost:8080/status/format/json",
+			},
+			wantStubStatus: "http://localhost:8080/status",
+			wantPlus:       true,
+			wantVTS:        true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := newScrapers(zap.NewNop(), &test.cfg)
+			if test.wantStubStatus == "" {
+				require.Nil(t, s.stubStatus)
+			} else {
+				require.NotNil(t, s.stubStatus)
+				require.Equal(t, test.wantStubStatus, s.stubStatus.cfg.Endpoint)
+			}
+			require.Equal(t, test.wantPlus, s.plus != nil)
+			require.Equal(t, test.wantVTS, s.vts != nil)
+			// the default endpoint isn't set on the config
+			require.NotEqual(t, defaultEndpoint, test.cfg.Endpoint)
+		})
+	}
+}
+
+func TestCreateMetricsReceiver_PlusAndVTS(t *testing.T) {
+	factory := NewFactory()
+	metricsReceiver, err := factory.CreateMetricsReceiver(
+		context.Background(),
+		component.ReceiverCreateParams{Logger: zap.NewNop()},
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				CollectionInterval: 10 * time.Second,
+			},
+			PlusEndpoint: "http://localhost:8080/api/6",
+			VTSEndpoint:  "http://localhost:8080/status/format/json",
+		},
+		&testbed.MockMetricConsumer{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, metricsReceiver)
+}
@@ -55,27 +55,72 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	NginxConnectionsAccepted MetricIntf
-	NginxConnectionsCurrent  MetricIntf
-	NginxConnectionsHandled  MetricIntf
-	NginxRequests            MetricIntf
+	NginxCacheResponses                MetricIntf
+	NginxCacheSize                     MetricIntf
+	NginxConnectionsAccepted           MetricIntf
+	NginxConnectionsCurrent            MetricIntf
+	NginxConnectionsHandled            MetricIntf
+	NginxRequests                      MetricIntf
+	NginxServerZoneIo                  MetricIntf
+	NginxServerZoneProcessing          MetricIntf
+	NginxServerZoneRequests            MetricIntf
+	NginxServerZoneResponses           MetricIntf
+	NginxSslHandshakes                 MetricIntf
+	NginxSslSessionReuses              MetricIntf
+	NginxUpstreamPeerActiveConnections MetricIntf
+	NginxUpstreamPeerFails             MetricIntf
+	NginxUpstreamPeerHealthy           MetricIntf
+	NginxUpstreamPeerIo                MetricIntf
+	NginxUpstreamPeerRequests          MetricIntf
+	NginxUpstreamPeerResponseTime      MetricIntf
+	NginxUpstreamPeerResponses         MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
 func (m *metricStruct) Names() []string {
 	return []string{
+		"nginx.cache.responses",
+		"nginx.cache.size",
 		"nginx.connections_accepted",
 		"nginx.connections_current",
 		"nginx.connections_handled",
 		"nginx.requests",
+		"nginx.server_zone.io",
+		"nginx.server_zone.processing",
+		"nginx.server_zone.requests",
+		"nginx.server_zone.responses",
+		"nginx.ssl.handshakes",
+		"nginx.ssl.session_reuses",
+		"nginx.upstream.peer.active_connections",
+		"nginx.upstream.peer.fails",
+		"nginx.upstream.peer.healthy",
+		"nginx.upstream.peer.io",
+		"nginx.upstream.peer.requests",
+		"nginx.upstream.peer.response_time",
+		"nginx.upstream.peer.responses",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"nginx.connections_accepted": Metrics.NginxConnectionsAccepted,
-	"nginx.connections_current":  Metrics.NginxConnectionsCurrent,
-	"nginx.connections_handled":  Metrics.NginxConnectionsHandled,
-	"nginx.requests":             Metrics.NginxRequests,
+	"nginx.cache.responses":                  Metrics.NginxCacheResponses,
+	"nginx.cache.size":                       Metrics.NginxCacheSize,
+	"nginx.connections_accepted":             Metrics.NginxConnectionsAccepted,
+	"nginx.connections_current":              Metrics.NginxConnectionsCurrent,
+	"nginx.connections_handled":              Metrics.NginxConnectionsHandled,
+	"nginx.requests":                         Metrics.NginxRequests,
+	"nginx.server_zone.io":                   Metrics.NginxServerZoneIo,
+	"nginx.server_zone.processing":           Metrics.NginxServerZoneProcessing,
+	"nginx.server_zone.requests":             Metrics.NginxServerZoneRequests,
+	"nginx.server_zone.responses":            Metrics.NginxServerZoneResponses,
+	"nginx.ssl.handshakes":                   Metrics.NginxSslHandshakes,
+	"nginx.ssl.session_reuses":               Metrics.NginxSslSessionReuses,
+	"nginx.upstream.peer.active_connections": Metrics.NginxUpstreamPeerActiveConnections,
+	"nginx.upstream.peer.fails":              Metrics.NginxUpstreamPeerFails,
+	"nginx.upstream.peer.healthy":            Metrics.NginxUpstreamPeerHealthy,
+	"nginx.upstream.peer.io":                 Metrics.NginxUpstreamPeerIo,
+	"nginx.upstream.peer.requests":           Metrics.NginxUpstreamPeerRequests,
+	"nginx.upstream.peer.response_time":      Metrics.NginxUpstreamPeerResponseTime,
+	"nginx.upstream.peer.responses":          Metrics.NginxUpstreamPeerResponses,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -84,16 +129,51 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.NginxConnectionsAccepted.Name(): Metrics.NginxConnectionsAccepted.Init,
-		Metrics.NginxConnectionsCurrent.Name():  Metrics.NginxConnectionsCurrent.Init,
-		Metrics.NginxConnectionsHandled.Name():  Metrics.NginxConnectionsHandled.Init,
-		Metrics.NginxRequests.Name():            Metrics.NginxRequests.Init,
+		Metrics.NginxCacheResponses.Name():                Metrics.NginxCacheResponses.Init,
+		Metrics.NginxCacheSize.Name():                     Metrics.NginxCacheSize.Init,
+		Metrics.NginxConnectionsAccepted.Name():           Metrics.NginxConnectionsAccepted.Init,
+		Metrics.NginxConnectionsCurrent.Name():            Metrics.NginxConnectionsCurrent.Init,
+		Metrics.NginxConnectionsHandled.Name():            Metrics.NginxConnectionsHandled.Init,
+		Metrics.NginxRequests.Name():                      Metrics.NginxRequests.Init,
+		Metrics.NginxServerZoneIo.Name():                  Metrics.NginxServerZoneIo.Init,
+		Metrics.NginxServerZoneProcessing.Name():          Metrics.NginxServerZoneProcessing.Init,
+		Metrics.NginxServerZoneRequests.Name():            Metrics.NginxServerZoneRequests.Init,
+		Metrics.NginxServerZoneResponses.Name():           Metrics.NginxServerZoneResponses.Init,
+		Metrics.NginxSslHandshakes.Name():                 Metrics.NginxSslHandshakes.Init,
+		Metrics.NginxSslSessionReuses.Name():              Metrics.NginxSslSessionReuses.Init,
+		Metrics.NginxUpstreamPeerActiveConnections.Name(): Metrics.NginxUpstreamPeerActiveConnections.Init,
+		Metrics.NginxUpstreamPeerFails.Name():             Metrics.NginxUpstreamPeerFails.Init,
+		Metrics.NginxUpstreamPeerHealthy.Name():           Metrics.NginxUpstreamPeerHealthy.Init,
+		Metrics.NginxUpstreamPeerIo.Name():                Metrics.NginxUpstreamPeerIo.Init,
+		Metrics.NginxUpstreamPeerRequests.Name():          Metrics.NginxUpstreamPeerRequests.Init,
+		Metrics.NginxUpstreamPeerResponseTime.Name():      Metrics.NginxUpstreamPeerResponseTime.Init,
+		Metrics.NginxUpstreamPeerResponses.Name():         Metrics.NginxUpstreamPeerResponses.Init,
 	}
 }
 
 // Metrics contains a set of methods for each metric that help with
 // manipulating those metrics.
 var Metrics = &metricStruct{
+	&metricImpl{
+		"nginx.cache.responses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.cache.responses")
+			metric.SetDescription("The total number of responses served by the cache, by cache status")
+			metric.SetUnit("responses")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.cache.size",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.cache.size")
+			metric.SetDescription("The current size of the cache")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"nginx.connections_accepted",
 		func(metric pdata.Metric) {
@@ -136,6 +216,141 @@ var Metrics = &metricStruct{
 			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"nginx.server_zone.io",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.io")
+			metric.SetDescription("The total number of bytes exchanged with the clients by the server zone")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.processing",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.processing")
+			metric.SetDescription("The number of client requests that are currently being processed by the server zone (NGINX Plus only)")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.requests",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.requests")
+			metric.SetDescription("The total number of client requests received by the server zone")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.responses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.responses")
+			metric.SetDescription("The total number of responses sent to clients by the server zone, by status code class")
+			metric.SetUnit("responses")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.ssl.handshakes",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.ssl.handshakes")
+			metric.SetDescription("The total number of SSL handshakes, by result (NGINX Plus only)")
+			metric.SetUnit("handshakes")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.ssl.session_reuses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.ssl.session_reuses")
+			metric.SetDescription("The total number of session reuses during SSL handshakes (NGINX Plus only)")
+			metric.SetUnit("sessions")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.active_connections",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.active_connections")
+			metric.SetDescription("The current number of active connections to the upstream peer (NGINX Plus only)")
+			metric.SetUnit("connections")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.fails",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.fails")
+			metric.SetDescription("The total number of unsuccessful attempts to communicate with the upstream peer (NGINX Plus only)")
+			metric.SetUnit("fails")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.healthy",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.healthy")
+			metric.SetDescription("Whether the upstream peer is up (1), or down, unavailable, unhealthy or draining (0)")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.io",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.io")
+			metric.SetDescription("The total number of bytes exchanged with the upstream peer")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.requests",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.requests")
+			metric.SetDescription("The total number of client requests forwarded to the upstream peer")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.response_time",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.response_time")
+			metric.SetDescription("The average time to get the full response from the upstream peer")
+			metric.SetUnit("ms")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.responses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.responses")
+			metric.SetDescription("The total number of responses obtained from the upstream peer, by status code class")
+			metric.SetUnit("responses")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
 }
 
 // M contains a set of methods for each metric that help with
@@ -144,16 +359,77 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
+	// Cache (The name of the cache)
+	Cache string
+	// CacheStatus (The status of the responses served by the cache)
+	CacheStatus string
+	// Direction (Direction of the network traffic)
+	Direction string
+	// Peer (The address of the upstream peer)
+	Peer string
+	// Result (The result of the SSL handshakes)
+	Result string
 	// State (The state of a connection)
 	State string
+	// StatusCode (The class of the response status code, from 1xx to 5xx)
+	StatusCode string
+	// Upstream (The name of the upstream group)
+	Upstream string
+	// Zone (The name of the server zone)
+	Zone string
 }{
+	"cache",
+	"cache_status",
+	"direction",
+	"peer",
+	"result",
 	"state",
+	"status_code",
+	"upstream",
+	"zone",
 }
 
 // L contains the possible metric labels that can be used. L is an alias for
 // Labels.
 var L = Labels
 
+// LabelCacheStatus are the possible values that the label "cache_status" can have.
+var LabelCacheStatus = struct {
+	Hit         string
+	Miss        string
+	Stale       string
+	Updating    string
+	Revalidated string
+	Expired     string
+	Bypass      string
+}{
+	"hit",
+	"miss",
+	"stale",
+	"updating",
+	"revalidated",
+	"expired",
+	"bypass",
+}
+
+// LabelDirection are the possible values that the label "direction" can have.
+var LabelDirection = struct {
+	Received string
+	Sent     string
+}{
+	"received",
+	"sent",
+}
+
+// LabelResult are the possible values that the label "result" can have.
+var LabelResult = struct {
+	Successful string
+	Failed     string
+}{
+	"successful",
+	"failed",
+}
+
 // LabelState are the possible values that the label "state" can have.
 var LabelState = struct {
 	Active  string
@@ -0,0 +1,268 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver/internal/metadata"
+)
+
+// The number of metrics collected from each section of the API, reported as
+// failed when the section can't be fetched.
+const (
+	plusUpstreamMetricsCount   = 7
+	plusServerZoneMetricsCount = 4
+	plusCacheMetricsCount      = 2
+	plusSSLMetricsCount        = 2
+)
+
+// nginxPlusScraper scrapes the upstream peers, server zones, caches and SSL
+// statistics of the NGINX Plus API.
+// https://nginx.org/en/docs/http/ngx_http_api_module.html
+type nginxPlusScraper struct {
+	httpClient *http.Client
+
+	logger *zap.Logger
+	cfg    *Config
+}
+
+func newNginxPlusScraper(
+	logger *zap.Logger,
+	cfg *Config,
+) *nginxPlusScraper {
+	return &nginxPlusScraper{
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+func (r *nginxPlusScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, error) {
+	if r.httpClient == nil {
+		httpClient, err := r.cfg.ToClient()
+		if err != nil {
+			return pdata.ResourceMetricsSlice{}, err
+		}
+		r.httpClient = httpClient
+	}
+
+	metrics := simple.Metrics{
+		Metrics:                    pdata.NewMetrics(),
+		Timestamp:                  time.Now(),
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/nginx",
+	}
+
+	// the sections of the API are scraped independently, e.g. the caches
+	// of a server without a cache are empty but the other sections are not
+	var errs scrapererror.ScrapeErrors
+
+	var upstreams map[string]plusUpstream
+	if err := r.get("http/upstreams", &upstreams); err != nil {
+		errs.AddPartial(plusUpstreamMetricsCount, err)
+	} else {
+		addPlusUpstreamMetrics(&metrics, upstreams)
+	}
+
+	var serverZones map[string]plusServerZone
+	if err := r.get("http/server_zones", &serverZones); err != nil {
+		errs.AddPartial(plusServerZoneMetricsCount, err)
+	} else {
+		addPlusServerZoneMetrics(&metrics, serverZones)
+	}
+
+	var caches map[string]plusCache
+	if err := r.get("http/caches", &caches); err != nil {
+		errs.AddPartial(plusCacheMetricsCount, err)
+	} else {
+		addPlusCacheMetrics(&metrics, caches)
+	}
+
+	var ssl plusSSL
+	if err := r.get("ssl", &ssl); err != nil {
+		errs.AddPartial(plusSSLMetricsCount, err)
+	} else {
+		metrics.WithLabels(map[string]string{metadata.L.Result: metadata.LabelResult.Successful}).
+			AddSumDataPoint(metadata.M.NginxSslHandshakes.Name(), ssl.Handshakes)
+		metrics.WithLabels(map[string]string{metadata.L.Result: metadata.LabelResult.Failed}).
+			AddSumDataPoint(metadata.M.NginxSslHandshakes.Name(), ssl.HandshakesFailed)
+		metrics.AddSumDataPoint(metadata.M.NginxSslSessionReuses.Name(), ssl.SessionReuses)
+	}
+
+	err := errs.Combine()
+	if err != nil {
+		r.logger.Error("Failed to fetch nginx plus stats", zap.Error(err))
+	}
+	return metrics.Metrics.ResourceMetrics(), err
+}
+
+// get decodes the JSON of the given path of the NGINX Plus API.
+func (r *nginxPlusScraper) get(path string, v interface{}) error {
+	return getJSON(r.httpClient, strings.TrimSuffix(r.cfg.PlusEndpoint, "/")+"/"+path, v)
+}
+
+func addPlusUpstreamMetrics(metrics *simple.Metrics, upstreams map[string]plusUpstream) {
+	for name, upstream := range upstreams {
+		for _, peer := range upstream.Peers {
+			peerMetrics := metrics.WithLabels(map[string]string{
+				metadata.L.Upstream: name,
+				metadata.L.Peer:     peer.Server,
+			})
+			healthy := int64(0)
+			if peer.State == "up" {
+				healthy = 1
+			}
+			peerMetrics.AddGaugeDataPoint(metadata.M.NginxUpstreamPeerHealthy.Name(), healthy)
+			peerMetrics.AddSumDataPoint(metadata.M.NginxUpstreamPeerRequests.Name(), peer.Requests)
+			addResponseMetrics(peerMetrics, metadata.M.NginxUpstreamPeerResponses.Name(), peer.Responses)
+			addIOMetrics(peerMetrics, metadata.M.NginxUpstreamPeerIo.Name(), peer.Received, peer.Sent)
+			peerMetrics.AddGaugeDataPoint(metadata.M.NginxUpstreamPeerResponseTime.Name(), peer.ResponseTime)
+			peerMetrics.AddGaugeDataPoint(metadata.M.NginxUpstreamPeerActiveConnections.Name(), peer.Active)
+			peerMetrics.AddSumDataPoint(metadata.M.NginxUpstreamPeerFails.Name(), peer.Fails)
+		}
+	}
+}
+
+func addPlusServerZoneMetrics(metrics *simple.Metrics, serverZones map[string]plusServerZone) {
+	for name, zone := range serverZones {
+		zoneMetrics := metrics.WithLabels(map[string]string{metadata.L.Zone: name})
+		zoneMetrics.AddSumDataPoint(metadata.M.NginxServerZoneRequests.Name(), zone.Requests)
+		addResponseMetrics(zoneMetrics, metadata.M.NginxServerZoneResponses.Name(), zone.Responses)
+		addIOMetrics(zoneMetrics, metadata.M.NginxServerZoneIo.Name(), zone.Received, zone.Sent)
+		zoneMetrics.AddGaugeDataPoint(metadata.M.NginxServerZoneProcessing.Name(), zone.Processing)
+	}
+}
+
+func addPlusCacheMetrics(metrics *simple.Metrics, caches map[string]plusCache) {
+	for name, cache := range caches {
+		cacheMetrics := metrics.WithLabels(map[string]string{metadata.L.Cache: name})
+		cacheMetrics.AddGaugeDataPoint(metadata.M.NginxCacheSize.Name(), cache.Size)
+		addCacheResponseMetrics(cacheMetrics, map[string]int64{
+			metadata.LabelCacheStatus.Hit:         cache.Hit.Responses,
+			metadata.LabelCacheStatus.Miss:        cache.Miss.Responses,
+			metadata.LabelCacheStatus.Stale:       cache.Stale.Responses,
+			metadata.LabelCacheStatus.Updating:    cache.Updating.Responses,
+			metadata.LabelCacheStatus.Revalidated: cache.Revalidated.Responses,
+			metadata.LabelCacheStatus.Expired:     cache.Expired.Responses,
+			metadata.LabelCacheStatus.Bypass:      cache.Bypass.Responses,
+		})
+	}
+}
+
+// addResponseMetrics adds a data point for each of the status code classes.
+func addResponseMetrics(metrics *simple.Metrics, name string, responses responseCodes) {
+	for statusCode, count := range map[string]int64{
+		"1xx": responses.Class1xx,
+		"2xx": responses.Class2xx,
+		"3xx": responses.Class3xx,
+		"4xx": responses.Class4xx,
+		"5xx": responses.Class5xx,
+	} {
+		metrics.WithLabels(map[string]string{metadata.L.StatusCode: statusCode}).AddSumDataPoint(name, count)
+	}
+}
+
+func addIOMetrics(metrics *simple.Metrics, name string, received, sent int64) {
+	metrics.WithLabels(map[string]string{metadata.L.Direction: metadata.LabelDirection.Received}).AddSumDataPoint(name, received)
+	metrics.WithLabels(map[string]string{metadata.L.Direction: metadata.LabelDirection.Sent}).AddSumDataPoint(name, sent)
+}
+
+func addCacheResponseMetrics(metrics *simple.Metrics, responses map[string]int64) {
+	for status, count := range responses {
+		metrics.WithLabels(map[string]string{metadata.L.CacheStatus: status}).
+			AddSumDataPoint(metadata.M.NginxCacheResponses.Name(), count)
+	}
+}
+
+// getJSON decodes the JSON response of a GET request to the given URL.
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected 200 response from %s, got %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode the response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// responseCodes are the numbers of responses by status code class, of both
+// the NGINX Plus API and the VTS module.
+type responseCodes struct {
+	Class1xx int64 `json:"1xx"`
+	Class2xx int64 `json:"2xx"`
+	Class3xx int64 `json:"3xx"`
+	Class4xx int64 `json:"4xx"`
+	Class5xx int64 `json:"5xx"`
+}
+
+type plusUpstream struct {
+	Peers []plusPeer `json:"peers"`
+}
+
+type plusPeer struct {
+	Server       string        `json:"server"`
+	State        string        `json:"state"`
+	Active       int64         `json:"active"`
+	Requests     int64         `json:"requests"`
+	Responses    responseCodes `json:"responses"`
+	Sent         int64         `json:"sent"`
+	Received     int64         `json:"received"`
+	Fails        int64         `json:"fails"`
+	ResponseTime int64         `json:"response_time"`
+}
+
+type plusServerZone struct {
+	Processing int64         `json:"processing"`
+	Requests   int64         `json:"requests"`
+	Responses  responseCodes `json:"responses"`
+	Received   int64         `json:"received"`
+	Sent       int64         `json:"sent"`
+}
+
+type plusCache struct {
+	Size        int64             `json:"size"`
+	Hit         plusCacheResponse `json:"hit"`
+	Stale       plusCacheResponse `json:"stale"`
+	Updating    plusCacheResponse `json:"updating"`
+	Revalidated plusCacheResponse `json:"revalidated"`
+	Miss        plusCacheResponse `json:"miss"`
+	Expired     plusCacheResponse `json:"expired"`
+	Bypass      plusCacheResponse `json:"bypass"`
+}
+
+type plusCacheResponse struct {
+	Responses int64 `json:"responses"`
+}
+
+type plusSSL struct {
+	Handshakes       int64 `json:"handshakes"`
+	HandshakesFailed int64 `json:"handshakes_failed"`
+	SessionReuses    int64 `json:"session_reuses"`
+}
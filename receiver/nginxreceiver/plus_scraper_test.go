@@ -0,0 +1,155 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+func TestPlusScraper(t *testing.T) {
+	plusMock := newPlusMock(t, nil)
+	defer plusMock.Close()
+	sc := newNginxPlusScraper(zap.NewNop(), &Config{
+		PlusEndpoint: plusMock.URL + "/api/6/",
+	})
+	rms, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	values := metricValues(t, rms)
+	assert.Equal(t, 15, len(metricNames(rms)))
+
+	peer1 := "{peer=10.0.0.1:8080,upstream=backend}"
+	peer2 := "{peer=10.0.0.2:8080,upstream=backend}"
+	assert.Equal(t, int64(1), values["nginx.upstream.peer.healthy"+peer1])
+	assert.Equal(t, int64(0), values["nginx.upstream.peer.healthy"+peer2])
+	assert.Equal(t, int64(10452), values["nginx.upstream.peer.requests"+peer1])
+	assert.Equal(t, int64(253), values["nginx.upstream.peer.responses{peer=10.0.0.2:8080,status_code=5xx,upstream=backend}"])
+	assert.Equal(t, int64(98710239), values["nginx.upstream.peer.io{direction=received,peer=10.0.0.1:8080,upstream=backend}"])
+	assert.Equal(t, int64(4398012), values["nginx.upstream.peer.io{direction=sent,peer=10.0.0.2:8080,upstream=backend}"])
+	assert.Equal(t, int64(25), values["nginx.upstream.peer.response_time"+peer1])
+	assert.Equal(t, int64(3), values["nginx.upstream.peer.active_connections"+peer1])
+	assert.Equal(t, int64(41), values["nginx.upstream.peer.fails"+peer2])
+
+	assert.Equal(t, int64(736395), values["nginx.server_zone.requests{zone=site1}"])
+	assert.Equal(t, int64(727290), values["nginx.server_zone.responses{status_code=2xx,zone=site1}"])
+	assert.Equal(t, int64(20183175459), values["nginx.server_zone.io{direction=sent,zone=site1}"])
+	assert.Equal(t, int64(2), values["nginx.server_zone.processing{zone=site1}"])
+
+	assert.Equal(t, int64(530915328), values["nginx.cache.size{cache=http_cache}"])
+	assert.Equal(t, int64(254032), values["nginx.cache.responses{cache=http_cache,cache_status=hit}"])
+	assert.Equal(t, int64(200187), values["nginx.cache.responses{cache=http_cache,cache_status=bypass}"])
+
+	assert.Equal(t, int64(79572), values["nginx.ssl.handshakes{result=successful}"])
+	assert.Equal(t, int64(21025), values["nginx.ssl.handshakes{result=failed}"])
+	assert.Equal(t, int64(15762), values["nginx.ssl.session_reuses"])
+}
+
+func TestPlusScraper_PartialError(t *testing.T) {
+	// the API of a server without the ssl section
+	plusMock := newPlusMock(t, map[string]bool{"ssl": true})
+	defer plusMock.Close()
+	sc := newNginxPlusScraper(zap.NewNop(), &Config{
+		PlusEndpoint: plusMock.URL + "/api/6",
+	})
+	rms, err := sc.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, 13, len(metricNames(rms)))
+}
+
+func TestPlusScraper_Error(t *testing.T) {
+	plusMock := newPlusMock(t, nil)
+	plusMock.Close()
+	sc := newNginxPlusScraper(zap.NewNop(), &Config{
+		PlusEndpoint: plusMock.URL + "/api/6",
+	})
+	_, err := sc.scrape(context.Background())
+	require.Error(t, err)
+}
+
+// newPlusMock serves the testdata/plus responses of the NGINX Plus API, except
+// for the missing sections.
+func newPlusMock(t *testing.T, missing map[string]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		section := strings.TrimPrefix(req.URL.Path, "/api/6/")
+		if section == req.URL.Path || missing[section] {
+			rw.WriteHeader(404)
+			return
+		}
+		b, err := ioutil.ReadFile(path.Join("testdata", "plus", path.Base(section)+".json"))
+		if err != nil {
+			rw.WriteHeader(404)
+			return
+		}
+		_, err = rw.Write(b)
+		require.NoError(t, err)
+	}))
+}
+
+// metricValues returns the values of the int data points by metric name and
+// labels, e.g. "nginx.server_zone.requests{zone=site1}".
+func metricValues(t *testing.T, rms pdata.ResourceMetricsSlice) map[string]int64 {
+	values := map[string]int64{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		var dps pdata.IntDataPointSlice
+		switch m.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dps = m.IntGauge().DataPoints()
+		case pdata.MetricDataTypeIntSum:
+			dps = m.IntSum().DataPoints()
+		default:
+			t.Fatalf("unexpected data type of %s", m.Name())
+		}
+		for j := 0; j < dps.Len(); j++ {
+			var labels []string
+			dps.At(j).LabelsMap().Range(func(k, v string) bool {
+				labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+				return true
+			})
+			key := m.Name()
+			if len(labels) > 0 {
+				sort.Strings(labels)
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			values[key] = dps.At(j).Value()
+		}
+	}
+	return values
+}
+
+func metricNames(rms pdata.ResourceMetricsSlice) map[string]bool {
+	names := map[string]bool{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()] = true
+	}
+	return names
+}
@@ -0,0 +1,156 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver/internal/metadata"
+)
+
+// vtsTotalZone is the server zone of the VTS module aggregating all of the
+// server zones, skipped to not count the requests twice.
+const vtsTotalZone = "*"
+
+// nginxVTSScraper scrapes the server zones, upstream zones and cache zones of
+// the JSON status of the VTS module.
+// https://github.com/vozlt/nginx-module-vts#json
+type nginxVTSScraper struct {
+	httpClient *http.Client
+
+	logger *zap.Logger
+	cfg    *Config
+}
+
+func newNginxVTSScraper(
+	logger *zap.Logger,
+	cfg *Config,
+) *nginxVTSScraper {
+	return &nginxVTSScraper{
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+func (r *nginxVTSScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, error) {
+	if r.httpClient == nil {
+		httpClient, err := r.cfg.ToClient()
+		if err != nil {
+			return pdata.ResourceMetricsSlice{}, err
+		}
+		r.httpClient = httpClient
+	}
+
+	metrics := simple.Metrics{
+		Metrics:                    pdata.NewMetrics(),
+		Timestamp:                  time.Now(),
+		MetricFactoriesByName:      metadata.M.FactoriesByName(),
+		InstrumentationLibraryName: "otelcol/nginx",
+	}
+
+	var status vtsStatus
+	if err := getJSON(r.httpClient, r.cfg.VTSEndpoint, &status); err != nil {
+		r.logger.Error("Failed to fetch nginx vts stats", zap.Error(err))
+		return pdata.ResourceMetricsSlice{}, err
+	}
+
+	for name, zone := range status.ServerZones {
+		if name == vtsTotalZone {
+			continue
+		}
+		zoneMetrics := metrics.WithLabels(map[string]string{metadata.L.Zone: name})
+		zoneMetrics.AddSumDataPoint(metadata.M.NginxServerZoneRequests.Name(), zone.RequestCounter)
+		addResponseMetrics(zoneMetrics, metadata.M.NginxServerZoneResponses.Name(), zone.Responses)
+		addIOMetrics(zoneMetrics, metadata.M.NginxServerZoneIo.Name(), zone.InBytes, zone.OutBytes)
+	}
+
+	for name, peers := range status.UpstreamZones {
+		for _, peer := range peers {
+			peerMetrics := metrics.WithLabels(map[string]string{
+				metadata.L.Upstream: name,
+				metadata.L.Peer:     peer.Server,
+			})
+			healthy := int64(1)
+			if peer.Down {
+				healthy = 0
+			}
+			peerMetrics.AddGaugeDataPoint(metadata.M.NginxUpstreamPeerHealthy.Name(), healthy)
+			peerMetrics.AddSumDataPoint(metadata.M.NginxUpstreamPeerRequests.Name(), peer.RequestCounter)
+			addResponseMetrics(peerMetrics, metadata.M.NginxUpstreamPeerResponses.Name(), peer.Responses)
+			addIOMetrics(peerMetrics, metadata.M.NginxUpstreamPeerIo.Name(), peer.InBytes, peer.OutBytes)
+			peerMetrics.AddGaugeDataPoint(metadata.M.NginxUpstreamPeerResponseTime.Name(), peer.ResponseMsec)
+		}
+	}
+
+	for name, cache := range status.CacheZones {
+		cacheMetrics := metrics.WithLabels(map[string]string{metadata.L.Cache: name})
+		cacheMetrics.AddGaugeDataPoint(metadata.M.NginxCacheSize.Name(), cache.UsedSize)
+		addCacheResponseMetrics(cacheMetrics, map[string]int64{
+			metadata.LabelCacheStatus.Hit:         cache.Responses.Hit,
+			metadata.LabelCacheStatus.Miss:        cache.Responses.Miss,
+			metadata.LabelCacheStatus.Stale:       cache.Responses.Stale,
+			metadata.LabelCacheStatus.Updating:    cache.Responses.Updating,
+			metadata.LabelCacheStatus.Revalidated: cache.Responses.Revalidated,
+			metadata.LabelCacheStatus.Expired:     cache.Responses.Expired,
+			metadata.LabelCacheStatus.Bypass:      cache.Responses.Bypass,
+		})
+	}
+
+	return metrics.Metrics.ResourceMetrics(), nil
+}
+
+type vtsStatus struct {
+	ServerZones   map[string]vtsServerZone `json:"serverZones"`
+	UpstreamZones map[string][]vtsPeer     `json:"upstreamZones"`
+	CacheZones    map[string]vtsCacheZone  `json:"cacheZones"`
+}
+
+type vtsServerZone struct {
+	RequestCounter int64         `json:"requestCounter"`
+	InBytes        int64         `json:"inBytes"`
+	OutBytes       int64         `json:"outBytes"`
+	Responses      responseCodes `json:"responses"`
+}
+
+type vtsPeer struct {
+	Server         string        `json:"server"`
+	RequestCounter int64         `json:"requestCounter"`
+	InBytes        int64         `json:"inBytes"`
+	OutBytes       int64         `json:"outBytes"`
+	Responses      responseCodes `json:"responses"`
+	ResponseMsec   int64         `json:"responseMsec"`
+	Down           bool          `json:"down"`
+}
+
+type vtsCacheZone struct {
+	UsedSize  int64                 `json:"usedSize"`
+	Responses vtsCacheZoneResponses `json:"responses"`
+}
+
+type vtsCacheZoneResponses struct {
+	Miss        int64 `json:"miss"`
+	Bypass      int64 `json:"bypass"`
+	Expired     int64 `json:"expired"`
+	Stale       int64 `json:"stale"`
+	Updating    int64 `json:"updating"`
+	Revalidated int64 `json:"revalidated"`
+	Hit         int64 `json:"hit"`
+}
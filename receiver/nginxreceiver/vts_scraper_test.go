@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVTSScraper(t *testing.T) {
+	vtsMock := newVTSMock(t)
+	defer vtsMock.Close()
+	sc := newNginxVTSScraper(zap.NewNop(), &Config{
+		VTSEndpoint: vtsMock.URL + "/status/format/json",
+	})
+	rms, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	values := metricValues(t, rms)
+	assert.Equal(t, 10, len(metricNames(rms)))
+
+	// the "*" zone totals the other zones
+	assert.Equal(t, int64(48001), values["nginx.server_zone.requests{zone=example.com}"])
+	_, found := values["nginx.server_zone.requests{zone=*}"]
+	assert.False(t, found)
+	assert.Equal(t, int64(110), values["nginx.server_zone.responses{status_code=5xx,zone=example.com}"])
+	assert.Equal(t, int64(12408321), values["nginx.server_zone.io{direction=received,zone=example.com}"])
+
+	assert.Equal(t, int64(1), values["nginx.upstream.peer.healthy{peer=10.0.0.1:8080,upstream=backend}"])
+	assert.Equal(t, int64(0), values["nginx.upstream.peer.healthy{peer=10.0.0.2:8080,upstream=backend}"])
+	assert.Equal(t, int64(2011), values["nginx.upstream.peer.requests{peer=10.0.0.2:8080,upstream=backend}"])
+	assert.Equal(t, int64(19), values["nginx.upstream.peer.response_time{peer=10.0.0.1:8080,upstream=backend}"])
+	assert.Equal(t, int64(1320433), values["nginx.upstream.peer.io{direction=sent,peer=10.0.0.1:8080,upstream=backend}"])
+
+	assert.Equal(t, int64(201326592), values["nginx.cache.size{cache=static_cache}"])
+	assert.Equal(t, int64(40125), values["nginx.cache.responses{cache=static_cache,cache_status=hit}"])
+	assert.Equal(t, int64(3101), values["nginx.cache.responses{cache=static_cache,cache_status=miss}"])
+}
+
+func TestVTSScraper_Error(t *testing.T) {
+	vtsMock := newVTSMock(t)
+	defer vtsMock.Close()
+	sc := newNginxVTSScraper(zap.NewNop(), &Config{
+		VTSEndpoint: vtsMock.URL + "/missing",
+	})
+	_, err := sc.scrape(context.Background())
+	require.Error(t, err)
+}
+
+func newVTSMock(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/status/format/json" {
+			rw.WriteHeader(404)
+			return
+		}
+		b, err := ioutil.ReadFile("testdata/vts.json")
+		require.NoError(t, err)
+		_, err = rw.Write(b)
+		require.NoError(t, err)
+	}))
+}
@@ -32,4 +32,16 @@ type Config struct {
 	Port int `mapstructure:"port"`
 	// SubprocessConfig is the configuration needed for the subprocess
 	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+	// RestartPolicy is how the subprocess is restarted when it exits
+	RestartPolicy RestartPolicy `mapstructure:"restart_policy"`
+}
+
+// RestartPolicy definition for the restarts of the subprocess
+type RestartPolicy struct {
+	// MaxRestarts is the number of times the subprocess is restarted before the receiver gives up, 0 meaning no limit
+	MaxRestarts int `mapstructure:"max_restarts"`
+	// InitialDelay is the delay before the subprocess is restarted, it grows exponentially once the subprocess keeps crashing
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	// MaxDelay caps the delay before the subprocess is restarted, 0 meaning no cap
+	MaxDelay time.Duration `mapstructure:"max_delay"`
 }
@@ -36,6 +36,9 @@ var (
 			Command: "mysqld_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartPolicy: RestartPolicy{
+			InitialDelay: time.Second,
+		},
 	}
 
 	wantReceiver3 = &Config{
@@ -45,6 +48,9 @@ var (
 			Command: "postgres_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartPolicy: RestartPolicy{
+			InitialDelay: time.Second,
+		},
 	}
 
 	wantReceiver4 = &Config{
@@ -64,6 +70,9 @@ var (
 				},
 			},
 		},
+		RestartPolicy: RestartPolicy{
+			InitialDelay: time.Second,
+		},
 	}
 
 	wantReceiver5 = &Config{
@@ -73,6 +82,25 @@ var (
 			Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartPolicy: RestartPolicy{
+			InitialDelay: time.Second,
+		},
+	}
+
+	wantReceiver6 = &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "restart_policy")),
+		ScrapeInterval:   60 * time.Second,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command:          "node_exporter --web.listen-address=:{{port}}",
+			Env:              []subprocessmanager.EnvConfig{},
+			WorkingDirectory: "/opt/node_exporter",
+			KillTimeout:      5 * time.Second,
+		},
+		RestartPolicy: RestartPolicy{
+			MaxRestarts:  10,
+			InitialDelay: 2 * time.Second,
+			MaxDelay:     5 * time.Minute,
+		},
 	}
 )
 
@@ -87,7 +115,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 5)
+	assert.Equal(t, len(cfg.Receivers), 6)
 
 	receiver1 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, factory.CreateDefaultConfig(), receiver1)
@@ -103,4 +131,7 @@ func TestLoadConfig(t *testing.T) {
 
 	receiver5 := cfg.Receivers[config.NewIDWithName(typeStr, "end_to_end_test/2")]
 	assert.Equal(t, wantReceiver5, receiver5)
+
+	receiver6 := cfg.Receivers[config.NewIDWithName(typeStr, "restart_policy")]
+	assert.Equal(t, wantReceiver6, receiver6)
 }
@@ -16,6 +16,7 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -32,6 +33,13 @@ const (
 	typeStr = "prometheus_exec"
 
 	defaultCollectionInterval = 60 * time.Second
+	defaultInitialDelay       = 1 * time.Second
+)
+
+// receivers are shared by the metrics and logs pipelines of a config, so that a single subprocess is run
+var (
+	receiversMu sync.Mutex
+	receivers   = map[*Config]*prometheusExecReceiver{}
 )
 
 // NewFactory creates a factory for the prometheusexec receiver
@@ -39,7 +47,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 // createDefaultConfig returns a default config
@@ -50,6 +59,9 @@ func createDefaultConfig() config.Receiver {
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Env: []subprocessmanager.EnvConfig{},
 		},
+		RestartPolicy: RestartPolicy{
+			InitialDelay: defaultInitialDelay,
+		},
 	}
 }
 
@@ -61,5 +73,45 @@ func createMetricsReceiver(
 	nextConsumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
 	rCfg := cfg.(*Config)
-	return newPromExecReceiver(params, rCfg, nextConsumer)
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	if r, ok := receivers[rCfg]; ok {
+		r.consumer = nextConsumer
+		return r, nil
+	}
+	r, err := newPromExecReceiver(params, rCfg, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	receivers[rCfg] = r
+	return r, nil
+}
+
+// removeReceiver forgets the receiver of a config once it's shut down, so that the config may be used again
+func removeReceiver(cfg *Config) {
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	delete(receivers, cfg)
+}
+
+// createLogsReceiver creates a logs receiver forwarding the stderr output of the subprocess based on provided Config.
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	nextConsumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	rCfg := cfg.(*Config)
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	r, ok := receivers[rCfg]
+	if !ok {
+		var err error
+		if r, err = newPromExecReceiver(params, rCfg, nil); err != nil {
+			return nil, err
+		}
+		receivers[rCfg] = r
+	}
+	r.logsConsumer = nextConsumer
+	return r, nil
 }
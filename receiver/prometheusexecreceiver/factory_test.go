@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
 	"go.uber.org/zap"
 
@@ -107,4 +108,30 @@ func TestCreateTraceAndMetricsReceiver(t *testing.T) {
 	}
 
 	assert.Equal(t, wantPer, metricReceiver)
+
+	// Test CreateLogsReceiver shares the subprocess of the metrics receiver
+	logsConsumer := new(consumertest.LogsSink)
+	logsReceiver, err := factory.CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, receiver, logsConsumer)
+	assert.NoError(t, err)
+	assert.Same(t, metricReceiver, logsReceiver)
+	assert.Equal(t, logsConsumer, logsReceiver.(*prometheusExecReceiver).logsConsumer)
+
+	// Test CreateLogsReceiver without a metrics pipeline
+	receiver = cfg.Receivers[config.NewIDWithName(typeStr, "test2")]
+	logsReceiver, err = factory.CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, receiver, logsConsumer)
+	assert.NoError(t, err)
+	assert.Nil(t, logsReceiver.(*prometheusExecReceiver).consumer)
+
+	// Test the config gets a new receiver once the previous one is shut down
+	assert.NoError(t, metricReceiver.Shutdown(context.Background()))
+	assert.NoError(t, logsReceiver.Shutdown(context.Background()))
+	receiversMu.Lock()
+	_, ok := receivers[cfg.Receivers[config.NewIDWithName(typeStr, "test")].(*Config)]
+	assert.False(t, ok)
+	_, ok = receivers[receiver.(*Config)]
+	assert.False(t, ok)
+	receiversMu.Unlock()
+	newReceiver, err := factory.CreateMetricsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg.Receivers[config.NewIDWithName(typeStr, "test")], nil)
+	assert.NoError(t, err)
+	assert.NotSame(t, metricReceiver, newReceiver)
 }
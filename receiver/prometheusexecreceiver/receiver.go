@@ -16,12 +16,14 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -30,7 +32,9 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
@@ -45,18 +49,19 @@ const (
 	healthyCrashCount int = 3
 	// delayMultiplier is the factor by which the delay scales
 	delayMultiplier float64 = 2.0
-	// initialDelay is the initial delay before a process is restarted
-	initialDelay = 1 * time.Second
 	// default path to scrape metrics at endpoint
 	defaultMetricsPath = "/metrics"
 	// default timeout for a scrape
 	defaultScrapeTimeout = 10 * time.Second
+	// instrumentation library of the subprocess health metrics
+	instrumentationLibraryName = "otelcol/prometheusexec"
 )
 
 type prometheusExecReceiver struct {
-	params   component.ReceiverCreateParams
-	config   *Config
-	consumer consumer.Metrics
+	params       component.ReceiverCreateParams
+	config       *Config
+	consumer     consumer.Metrics
+	logsConsumer consumer.Logs
 
 	// Prometheus receiver config
 	promReceiverConfig *prometheusreceiver.Config
@@ -70,6 +75,18 @@ type prometheusExecReceiver struct {
 
 	// Shutdown channel
 	shutdownCh chan struct{}
+
+	// The receiver is shared by the metrics and logs pipelines, but must only start and shut down once
+	startOnce    sync.Once
+	shutdownOnce sync.Once
+
+	// Subprocess health, reported as metrics
+	healthMu     sync.Mutex
+	startTime    time.Time
+	processStart time.Time
+	restarts     int64
+	exitCode     int64
+	exited       bool
 }
 
 type runResult struct {
@@ -77,6 +94,19 @@ type runResult struct {
 	subprocessErr error
 }
 
+// exitCode returns the exit code of the subprocess, or -1 if it could not be run or was terminated by a signal
+func (r runResult) exitCode() int64 {
+	if r.subprocessErr == nil {
+		return 0
+	}
+	// *exec.ExitError once the subprocess ran
+	var exitErr interface{ ExitCode() int }
+	if errors.As(r.subprocessErr, &exitErr) {
+		return int64(exitErr.ExitCode())
+	}
+	return -1
+}
+
 // newPromExecReceiver returns a prometheusExecReceiver
 func newPromExecReceiver(params component.ReceiverCreateParams, config *Config, consumer consumer.Metrics) (*prometheusExecReceiver, error) {
 	if config.SubprocessConfig.Command == "" {
@@ -103,12 +133,7 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	scrapeConfig.ScrapeTimeout = model.Duration(defaultScrapeTimeout)
 	scrapeConfig.Scheme = "http"
 	scrapeConfig.MetricsPath = defaultMetricsPath
-	jobName := cfg.ID().Name()
-	if jobName == "" {
-		// Fallback to type if no name
-		jobName = string(cfg.ID().Type())
-	}
-	scrapeConfig.JobName = jobName
+	scrapeConfig.JobName = getJobName(cfg)
 	scrapeConfig.HonorLabels = false
 	scrapeConfig.HonorTimestamps = true
 
@@ -131,22 +156,40 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	}
 }
 
+// getJobName returns the name of the Prometheus job, which is also the service name of the subprocess
+func getJobName(cfg *Config) string {
+	jobName := cfg.ID().Name()
+	if jobName == "" {
+		// Fallback to type if no name
+		jobName = string(cfg.ID().Type())
+	}
+	return jobName
+}
+
 // getSubprocessConfig returns the subprocess config
 func getSubprocessConfig(cfg *Config) *subprocessmanager.SubprocessConfig {
 	subprocessConfig := &subprocessmanager.SubprocessConfig{}
 
 	subprocessConfig.Command = cfg.SubprocessConfig.Command
 	subprocessConfig.Env = cfg.SubprocessConfig.Env
+	subprocessConfig.WorkingDirectory = cfg.SubprocessConfig.WorkingDirectory
+	subprocessConfig.KillTimeout = cfg.SubprocessConfig.KillTimeout
 
 	return subprocessConfig
 }
 
 // Start creates the configs and calls the function that handles the prometheus_exec receiver
 func (per *prometheusExecReceiver) Start(ctx context.Context, host component.Host) error {
-	// shutdown channel
-	per.shutdownCh = make(chan struct{})
-
-	go per.manageProcess(context.Background(), host)
+	per.startOnce.Do(func() {
+		// shutdown channel
+		per.shutdownCh = make(chan struct{})
+		per.startTime = time.Now()
+
+		go per.manageProcess(context.Background(), host)
+		if per.consumer != nil {
+			go per.reportHealth(context.Background())
+		}
+	})
 
 	return nil
 }
@@ -163,16 +206,27 @@ func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host compo
 			return
 		}
 
-		elapsed := per.runProcess(ctx)
+		result, shutdown := per.runProcess(ctx)
 
-		err = receiver.Shutdown(ctx)
-		if err != nil {
-			per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
+		if receiver != nil {
+			err = receiver.Shutdown(ctx)
+			if err != nil {
+				per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
+				return
+			}
+		}
+		if shutdown {
 			return
 		}
 
-		crashCount = per.computeCrashCount(elapsed, crashCount)
-		per.computeDelayAndSleep(elapsed, crashCount)
+		restarts := per.recordExit(result)
+		if maxRestarts := per.config.RestartPolicy.MaxRestarts; maxRestarts > 0 && restarts > int64(maxRestarts) {
+			per.params.Logger.Error("Subprocess exceeded the maximum number of restarts, not restarting it", zap.Int("max_restarts", maxRestarts))
+			return
+		}
+
+		crashCount = per.computeCrashCount(result.elapsed, crashCount)
+		per.computeDelayAndSleep(result.elapsed, crashCount)
 
 		// Exit loop if shutdown was signaled
 		select {
@@ -201,6 +255,13 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 		}
 	}
 
+	per.subprocessConfig = per.fillPortPlaceholders(currentPort)
+
+	// Only the stderr output of the subprocess is needed without a metrics pipeline
+	if per.consumer == nil {
+		return nil, nil
+	}
+
 	// Create and start the underlying Prometheus receiver
 	factory := prometheusreceiver.NewFactory()
 	receiver, err := factory.CreateMetricsReceiver(ctx, per.params, per.promReceiverConfig, per.consumer)
@@ -208,8 +269,6 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 		return nil, fmt.Errorf("unable to create Prometheus receiver - killing this single process/receiver: %w", err)
 	}
 
-	per.subprocessConfig = per.fillPortPlaceholders(currentPort)
-
 	err = receiver.Start(ctx, host)
 	if err != nil {
 		return nil, fmt.Errorf("could not start receiver - killing this single process/receiver: %w", err)
@@ -218,11 +277,15 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 	return receiver, nil
 }
 
-// runProcess will run the process and return runtime, or handle a shutdown if one is triggered while the subprocess is running
-func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration {
+// runProcess will run the process and return its result, or handle a shutdown if one is triggered while the subprocess is running
+func (per *prometheusExecReceiver) runProcess(ctx context.Context) (runResult, bool) {
 	childCtx, cancel := context.WithCancel(ctx)
 	run := make(chan runResult, 1)
 
+	per.healthMu.Lock()
+	per.processStart = time.Now()
+	per.healthMu.Unlock()
+
 	go per.handleProcessResult(childCtx, run)
 
 	select {
@@ -232,23 +295,132 @@ func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration
 			per.params.Logger.Info("Subprocess error", zap.String("error", result.subprocessErr.Error()))
 		}
 		cancel()
-		return result.elapsed
+		return result, false
 
 	case <-per.shutdownCh:
 		cancel()
-		return 0
+		// Wait for the subprocess to be interrupted or killed
+		<-run
+		return runResult{}, true
 	}
 }
 
 // handleProcessResult calls the process manager's run function and pipes the return value into the channel
 func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, run chan<- runResult) {
-	elapsed, subprocessErr := per.subprocessConfig.Run(childCtx, per.params.Logger)
+	elapsed, subprocessErr := per.subprocessConfig.Run(childCtx, per.params.Logger, per.forwardStderr)
 	run <- runResult{elapsed, subprocessErr}
 }
 
+// recordExit updates the health of the subprocess after it exited and returns the number of restarts
+func (per *prometheusExecReceiver) recordExit(result runResult) int64 {
+	per.healthMu.Lock()
+	defer per.healthMu.Unlock()
+
+	per.processStart = time.Time{}
+	per.exitCode = result.exitCode()
+	per.exited = true
+	per.restarts++
+	return per.restarts
+}
+
+// forwardStderr sends a line written by the subprocess on stderr as a log record to the logs pipeline, if any
+func (per *prometheusExecReceiver) forwardStderr(line string) {
+	if per.logsConsumer == nil {
+		return
+	}
+
+	logs := pdata.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, getJobName(per.config))
+	ill := rl.InstrumentationLibraryLogs().AppendEmpty()
+	ill.InstrumentationLibrary().SetName(instrumentationLibraryName)
+	lr := ill.Logs().AppendEmpty()
+	lr.SetTimestamp(pdata.TimestampFromTime(time.Now()))
+	lr.Body().SetStringVal(line)
+	lr.Attributes().InsertString("log.iostream", "stderr")
+
+	if err := per.logsConsumer.ConsumeLogs(context.Background(), logs); err != nil {
+		per.params.Logger.Debug("could not forward subprocess output line", zap.Error(err))
+	}
+}
+
+// reportHealth sends the health metrics of the subprocess every scrape interval until shutdown
+func (per *prometheusExecReceiver) reportHealth(ctx context.Context) {
+	interval := per.config.ScrapeInterval
+	if interval <= 0 {
+		interval = defaultCollectionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := per.consumer.ConsumeMetrics(ctx, per.healthMetrics(now)); err != nil {
+				per.params.Logger.Debug("could not send subprocess health metrics", zap.Error(err))
+			}
+
+		case <-per.shutdownCh:
+			return
+		}
+	}
+}
+
+// healthMetrics returns the number of restarts, uptime and last exit code of the subprocess
+func (per *prometheusExecReceiver) healthMetrics(now time.Time) pdata.Metrics {
+	per.healthMu.Lock()
+	defer per.healthMu.Unlock()
+
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString(conventions.AttributeServiceName, getJobName(per.config))
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(instrumentationLibraryName)
+	ms := ilm.Metrics()
+	timestamp := pdata.TimestampFromTime(now)
+
+	restarts := ms.AppendEmpty()
+	restarts.SetName("prometheus_exec.subprocess.restarts")
+	restarts.SetDescription("Number of times the subprocess exited and was restarted")
+	restarts.SetUnit("1")
+	restarts.SetDataType(pdata.MetricDataTypeIntSum)
+	restarts.IntSum().SetIsMonotonic(true)
+	restarts.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	restartsDP := restarts.IntSum().DataPoints().AppendEmpty()
+	restartsDP.SetStartTimestamp(pdata.TimestampFromTime(per.startTime))
+	restartsDP.SetTimestamp(timestamp)
+	restartsDP.SetValue(per.restarts)
+
+	var uptime float64
+	if !per.processStart.IsZero() {
+		uptime = now.Sub(per.processStart).Seconds()
+	}
+	uptimeMetric := ms.AppendEmpty()
+	uptimeMetric.SetName("prometheus_exec.subprocess.uptime")
+	uptimeMetric.SetDescription("Time since the subprocess was last started, 0 while it is not running")
+	uptimeMetric.SetUnit("s")
+	uptimeMetric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	uptimeDP := uptimeMetric.DoubleGauge().DataPoints().AppendEmpty()
+	uptimeDP.SetTimestamp(timestamp)
+	uptimeDP.SetValue(uptime)
+
+	if per.exited {
+		exitCode := ms.AppendEmpty()
+		exitCode.SetName("prometheus_exec.subprocess.exit_code")
+		exitCode.SetDescription("Exit code of the subprocess the last time it exited, -1 if it was terminated by a signal or could not be run")
+		exitCode.SetUnit("1")
+		exitCode.SetDataType(pdata.MetricDataTypeIntGauge)
+		exitCodeDP := exitCode.IntGauge().DataPoints().AppendEmpty()
+		exitCodeDP.SetTimestamp(timestamp)
+		exitCodeDP.SetValue(per.exitCode)
+	}
+
+	return md
+}
+
 // computeDelayAndSleep will compute how long the process should delay before restarting and handle a shutdown while this goroutine waits
 func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, crashCount int) {
-	sleepTime := getDelay(elapsed, healthyProcessTime, crashCount, healthyCrashCount)
+	sleepTime := getDelay(elapsed, healthyProcessTime, crashCount, healthyCrashCount, per.config.RestartPolicy)
 	per.params.Logger.Info("Subprocess start delay", zap.String("time until process restarts", sleepTime.String()))
 
 	select {
@@ -295,19 +467,28 @@ func generateRandomPort() (int, error) {
 	return listener.Addr().(*net.TCPAddr).Port, nil
 }
 
-// getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm
-func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int) time.Duration {
-	// Return the initialDelay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
+// getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm, capped by the restart policy
+func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int, policy RestartPolicy) time.Duration {
+	// Return the initial delay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
 	if elapsed > healthyProcessDuration || crashCount <= healthyCrashCount {
-		return initialDelay
+		return policy.InitialDelay
 	}
 
-	// Return initialDelay times 2 to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
-	return initialDelay * time.Duration(math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64()))
+	// Return the initial delay times 2 to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
+	delay := time.Duration(float64(policy.InitialDelay) * math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64()))
+	if policy.MaxDelay > 0 && (delay > policy.MaxDelay || delay < 0) {
+		return policy.MaxDelay
+	}
+	return delay
 }
 
-// Shutdown stops the underlying Prometheus receiver.
+// Shutdown stops the underlying Prometheus receiver and forgets the receiver, so that its config may be used again.
 func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
-	close(per.shutdownCh)
+	per.shutdownOnce.Do(func() {
+		if per.shutdownCh != nil {
+			close(per.shutdownCh)
+		}
+		removeReceiver(per.config)
+	})
 	return nil
 }
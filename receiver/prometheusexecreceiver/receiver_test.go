@@ -16,6 +16,8 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path"
 	"testing"
 	"time"
@@ -91,7 +93,7 @@ func endToEndScrapeTest(t *testing.T, receiverConfig config.Receiver, testName s
 	const waitFor = 20 * time.Second
 	const tick = 100 * time.Millisecond
 	require.Eventuallyf(t, func() bool {
-		got := sink.AllMetrics()
+		got := scrapedMetrics(sink.AllMetrics())
 		if len(got) < 2 {
 			return false
 		}
@@ -102,6 +104,17 @@ func endToEndScrapeTest(t *testing.T, receiverConfig config.Receiver, testName s
 	assertTwoUniqueValuesScraped(t, metrics)
 }
 
+// scrapedMetrics returns the metrics scraped from the subprocess, without its health metrics
+func scrapedMetrics(metricsSlice []pdata.Metrics) []pdata.Metrics {
+	var scraped []pdata.Metrics
+	for _, md := range metricsSlice {
+		if md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).InstrumentationLibrary().Name() != instrumentationLibraryName {
+			scraped = append(scraped, md)
+		}
+	}
+	return scraped
+}
+
 // assertTwoUniqueValuesScraped iterates over the found metrics and returns true if it finds at least 2 unique metrics, meaning the endpoint
 // was successfully scraped twice AND the subprocess being handled was stopped and restarted
 func assertTwoUniqueValuesScraped(t *testing.T, metricsSlice []pdata.Metrics) {
@@ -509,7 +522,7 @@ func TestGetDelayAndComputeCrashCount(t *testing.T) {
 	// getDelay() test
 	t.Run("GetDelay test", func(t *testing.T) {
 		for _, test := range getDelayAndComputeCrashCountTests {
-			got := getDelay(test.elapsed, test.healthyProcessTime, test.crashCount, test.healthyCrashCount)
+			got := getDelay(test.elapsed, test.healthyProcessTime, test.crashCount, test.healthyCrashCount, RestartPolicy{InitialDelay: time.Second})
 
 			if test.wantDelay > 0 {
 				assert.Equalf(t, test.wantDelay, got, "getDelay() '%v', got = %v, want %v", test.name, got, test.wantDelay)
@@ -533,3 +546,62 @@ func TestGetDelayAndComputeCrashCount(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDelayMaxDelay(t *testing.T) {
+	policy := RestartPolicy{InitialDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	assert.Equal(t, time.Second, getDelay(15*time.Second, 30*time.Minute, 3, 3, policy))
+	assert.Equal(t, 10*time.Second, getDelay(15*time.Second, 30*time.Minute, 20, 3, policy))
+	assert.Equal(t, 10*time.Second, getDelay(15*time.Second, 30*time.Minute, 100, 3, policy))
+
+	policy.MaxDelay = 0
+	assert.Greater(t, int64(getDelay(15*time.Second, 30*time.Minute, 20, 3, policy)), int64(10*time.Second))
+}
+
+func TestHealthMetrics(t *testing.T) {
+	start := time.Now()
+	per := &prometheusExecReceiver{
+		config:    &Config{ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "mysql"))},
+		startTime: start,
+	}
+
+	// the subprocess is running and never exited
+	per.processStart = start.Add(time.Second)
+	md := per.healthMetrics(start.Add(3 * time.Second))
+	rm := md.ResourceMetrics().At(0)
+	serviceName, _ := rm.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "mysql", serviceName.StringVal())
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+	assert.Equal(t, "prometheus_exec.subprocess.restarts", ms.At(0).Name())
+	assert.Equal(t, int64(0), ms.At(0).IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, pdata.TimestampFromTime(start), ms.At(0).IntSum().DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, "prometheus_exec.subprocess.uptime", ms.At(1).Name())
+	assert.Equal(t, 2.0, ms.At(1).DoubleGauge().DataPoints().At(0).Value())
+
+	// the subprocess crashed and was not restarted yet
+	assert.Equal(t, int64(1), per.recordExit(runResult{subprocessErr: exitError(2)}))
+	ms = per.healthMetrics(start.Add(4 * time.Second)).ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, ms.Len())
+	assert.Equal(t, int64(1), ms.At(0).IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, 0.0, ms.At(1).DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, "prometheus_exec.subprocess.exit_code", ms.At(2).Name())
+	assert.Equal(t, int64(2), ms.At(2).IntGauge().DataPoints().At(0).Value())
+}
+
+func TestRunResultExitCode(t *testing.T) {
+	assert.Equal(t, int64(0), runResult{}.exitCode())
+	assert.Equal(t, int64(3), runResult{subprocessErr: fmt.Errorf("%w", exitError(3))}.exitCode())
+	assert.Equal(t, int64(-1), runResult{subprocessErr: errors.New("process could not start")}.exitCode())
+}
+
+// exitError is the error of a subprocess exiting with a given code
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e exitError) ExitCode() int {
+	return int(e)
+}
@@ -14,12 +14,18 @@
 
 package subprocessmanager
 
+import "time"
+
 // SubprocessConfig is the config definition for the subprocess manager
 type SubprocessConfig struct {
 	// Command is the command to be run (binary + flags, separated by commas)
 	Command string `mapstructure:"exec"`
 	// Env is a list of env variables to pass to a specific command
 	Env []EnvConfig `mapstructure:"env"`
+	// WorkingDirectory is the directory the command is run in, defaults to the Collector's working directory
+	WorkingDirectory string `mapstructure:"working_directory"`
+	// KillTimeout is how long the subprocess is given to exit after being interrupted before it is killed, 0 meaning it is killed right away
+	KillTimeout time.Duration `mapstructure:"kill_timeout"`
 }
 
 // EnvConfig is the config definition of each key-value pair for environment variables
@@ -28,8 +28,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// Run will start the process and keep track of running time
-func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time.Duration, error) {
+// Run will start the process and keep track of running time, passing every line written by the process on stderr to onStderr if it is not nil
+func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger, onStderr func(line string)) (time.Duration, error) {
 	// Parse the command line string into arguments
 	args, err := shellquote.Split(proc.Command)
 	if err != nil {
@@ -39,19 +39,20 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 	// Create the command object and attach current os environment + environment variables defined by the user
 	childProcess := exec.Command(args[0], args[1:]...) // #nosec
 	childProcess.Env = append(os.Environ(), formatEnvSlice(&proc.Env)...)
+	childProcess.Dir = proc.WorkingDirectory
 
 	// Handle the subprocess standard and error outputs in goroutines
 	stdoutReader, stdoutErr := childProcess.StdoutPipe()
 	if stdoutErr != nil {
 		return 0, fmt.Errorf("could not get the command's stdout pipe, err: %w", stdoutErr)
 	}
-	go proc.pipeSubprocessOutput(bufio.NewReader(stdoutReader), logger, true)
+	go proc.pipeSubprocessOutput(bufio.NewReader(stdoutReader), logger, true, nil)
 
 	stderrReader, stderrErr := childProcess.StderrPipe()
 	if stderrErr != nil {
 		return 0, fmt.Errorf("could not get the command's stderr pipe, err: %w", stderrErr)
 	}
-	go proc.pipeSubprocessOutput(bufio.NewReader(stderrReader), logger, false)
+	go proc.pipeSubprocessOutput(bufio.NewReader(stderrReader), logger, false, onStderr)
 
 	// Start and stop timer (elapsed) right before and after executing the command
 	processErrCh := make(chan error, 1)
@@ -77,18 +78,28 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 		return elapsed, nil
 
 	case <-ctx.Done():
+		// Give the subprocess a chance to exit gracefully before killing it (interrupting is not supported on Windows)
+		if proc.KillTimeout > 0 && childProcess.Process.Signal(os.Interrupt) == nil {
+			select {
+			case <-processErrCh:
+				return time.Since(start), nil
+			case <-time.After(proc.KillTimeout):
+				logger.Info("subprocess did not exit after being interrupted, killing it", zap.Duration("kill_timeout", proc.KillTimeout))
+			}
+		}
+
 		elapsed := time.Since(start)
 
 		err := childProcess.Process.Kill()
 		if err != nil {
-			return elapsed, fmt.Errorf("couldn't kill subprocess: %w", errProcess)
+			return elapsed, fmt.Errorf("couldn't kill subprocess: %w", err)
 		}
 		return elapsed, nil
 	}
 }
 
-// Log every line of the subprocesse's output using zap, until pipe is closed (EOF)
-func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger *zap.Logger, isStdout bool) {
+// Log every line of the subprocesse's output using zap and pass it to the handler if any, until pipe is closed (EOF)
+func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger *zap.Logger, isStdout bool, handler func(line string)) {
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil && err != io.EOF {
@@ -103,6 +114,9 @@ func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger
 			} else {
 				logger.Error("subprocess output line", zap.String("output", line))
 			}
+			if handler != nil {
+				handler(line)
+			}
 		}
 
 		// Leave this function when error is EOF (stderr/stdout pipe was closed)
@@ -16,11 +16,19 @@ package subprocessmanager
 
 import (
 	"context"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestFormatEnvSlice(t *testing.T) {
@@ -137,7 +145,7 @@ func TestRun(t *testing.T) {
 	for _, test := range runTests {
 		t.Run(test.name, func(t *testing.T) {
 			logger, _ := zap.NewProduction()
-			got, err := test.process.Run(context.Background(), logger)
+			got, err := test.process.Run(context.Background(), logger, nil)
 			if test.wantErr && err == nil {
 				t.Errorf("Run() got = %v, wantErr %v", got, test.wantErr)
 				return
@@ -148,3 +156,138 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+// buildTestInterrupt builds testdata/test_interrupt.go, so that the tests signal the program itself rather than go run.
+func buildTestInterrupt(t *testing.T) string {
+	program := filepath.Join(t.TempDir(), "test_interrupt")
+	if runtime.GOOS == "windows" {
+		program += ".exe"
+	}
+	out, err := exec.Command("go", "build", "-o", program, filepath.Join("testdata", "test_interrupt.go")).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return program
+}
+
+// stderrRecorder records the lines written by the subprocess on stderr.
+type stderrRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *stderrRecorder) onStderr(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, line)
+}
+
+func (r *stderrRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+func (r *stderrRecorder) has(line string) bool {
+	for _, l := range r.get() {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRunStderrAndWorkingDirectory(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	process := &SubprocessConfig{
+		Command:          buildTestInterrupt(t),
+		Env:              []EnvConfig{},
+		WorkingDirectory: dir,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := &stderrRecorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := process.Run(ctx, zap.NewNop(), recorder.onStderr)
+		assert.NoError(t, err)
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(recorder.get()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	lines := recorder.get()
+	const prefix = "working directory: "
+	require.True(t, strings.HasPrefix(lines[0], prefix), lines[0])
+	wd, err := filepath.EvalSymlinks(strings.TrimPrefix(lines[0], prefix))
+	require.NoError(t, err)
+	assert.Equal(t, dir, wd)
+}
+
+func TestRunKillTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupting the subprocess is not supported on Windows")
+	}
+	program := buildTestInterrupt(t)
+
+	const killTimeout = 2 * time.Second
+	tests := []struct {
+		name        string
+		command     string
+		interrupted bool
+	}{
+		{
+			name:        "exits after being interrupted",
+			command:     program,
+			interrupted: true,
+		},
+		{
+			name:        "killed after the timeout",
+			command:     program + " -ignore-interrupt",
+			interrupted: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			process := &SubprocessConfig{
+				Command:     tt.command,
+				Env:         []EnvConfig{},
+				KillTimeout: killTimeout,
+			}
+			core, logs := observer.New(zap.InfoLevel)
+			recorder := &stderrRecorder{}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var canceled time.Time
+			go func() {
+				// the program handles the interrupt once it has written its working directory
+				if assert.Eventually(t, func() bool {
+					return len(recorder.get()) > 0
+				}, 5*time.Second, 10*time.Millisecond) {
+					canceled = time.Now()
+				}
+				cancel()
+			}()
+			_, err := process.Run(ctx, zap.New(core), recorder.onStderr)
+			require.NoError(t, err)
+			stopped := time.Since(canceled)
+
+			killed := logs.FilterMessage("subprocess did not exit after being interrupted, killing it").Len() > 0
+			assert.Equal(t, !tt.interrupted, killed)
+			if tt.interrupted {
+				assert.Less(t, int64(stopped), int64(killTimeout))
+				assert.Eventually(t, func() bool {
+					return recorder.has("interrupted")
+				}, 5*time.Second, 10*time.Millisecond)
+			} else {
+				assert.GreaterOrEqual(t, int64(stopped), int64(killTimeout))
+				assert.False(t, recorder.has("interrupted"))
+			}
+		})
+	}
+}
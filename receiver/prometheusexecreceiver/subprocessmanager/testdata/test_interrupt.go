@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const defaultSleepTime = time.Minute
+
+// test_interrupt writes its working directory on stderr, then exits when it is
+// interrupted, or keeps running until it is killed with -ignore-interrupt.
+func main() {
+	ignoreInterrupt := flag.Bool("ignore-interrupt", false, "keep running when interrupted")
+	flag.Parse()
+
+	interrupted := make(chan os.Signal, 1)
+	if *ignoreInterrupt {
+		signal.Ignore(os.Interrupt)
+	} else {
+		signal.Notify(interrupted, os.Interrupt)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "working directory:", wd)
+
+	select {
+	case <-interrupted:
+		fmt.Fprintln(os.Stderr, "interrupted")
+	case <-time.After(defaultSleepTime):
+	}
+}
@@ -15,6 +15,8 @@
 package redisreceiver
 
 import (
+	"fmt"
+
 	"github.com/go-redis/redis/v7"
 )
 
@@ -22,6 +24,15 @@ import (
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
 	retrieveInfo() (string, error)
+	// retrieves a string of key/value pairs of redis cluster state, only
+	// available when cluster mode is enabled
+	retrieveClusterInfo() (string, error)
+	// retrieves a string of key/value pairs of the per command statistics
+	retrieveCommandStats() (string, error)
+	// retrieves the reply of LATENCY LATEST, an array of latency events
+	retrieveLatencyLatest() ([]interface{}, error)
+	// retrieves the reply of SLOWLOG GET, an array of slow log entries
+	retrieveSlowLog(count int) ([]interface{}, error)
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
@@ -50,3 +61,36 @@ func (c *redisClient) delimiter() string {
 func (c *redisClient) retrieveInfo() (string, error) {
 	return c.client.Info().Result()
 }
+
+// Retrieve Redis CLUSTER INFO.
+func (c *redisClient) retrieveClusterInfo() (string, error) {
+	return c.client.ClusterInfo().Result()
+}
+
+// Retrieve the commandstats section of Redis INFO, which isn't part of the
+// default sections.
+func (c *redisClient) retrieveCommandStats() (string, error) {
+	return c.client.Info("commandstats").Result()
+}
+
+// Retrieve Redis LATENCY LATEST.
+func (c *redisClient) retrieveLatencyLatest() ([]interface{}, error) {
+	return c.doArray("latency", "latest")
+}
+
+// Retrieve the `count` most recent entries of Redis SLOWLOG.
+func (c *redisClient) retrieveSlowLog(count int) ([]interface{}, error) {
+	return c.doArray("slowlog", "get", count)
+}
+
+func (c *redisClient) doArray(args ...interface{}) ([]interface{}, error) {
+	res, err := c.client.Do(args...).Result()
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply type %T to %v", res, args)
+	}
+	return arr, nil
+}
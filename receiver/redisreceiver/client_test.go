@@ -26,10 +26,18 @@ import (
 
 var _ client = (*fakeClient)(nil)
 
-type fakeClient struct{}
+// fakeClient replays the INFO of testdata/<infoFile>.txt, and the CLUSTER INFO
+// of testdata/cluster_info.txt.
+type fakeClient struct {
+	infoFile string
+}
 
 func newFakeClient() *fakeClient {
-	return &fakeClient{}
+	return newFakeClientWithInfo("info")
+}
+
+func newFakeClientWithInfo(infoFile string) *fakeClient {
+	return &fakeClient{infoFile: infoFile}
 }
 
 func (c fakeClient) delimiter() string {
@@ -40,8 +48,37 @@ func (c fakeClient) delimiter() string {
 	return "\n"
 }
 
-func (fakeClient) retrieveInfo() (string, error) {
-	return readFile("info")
+func (c fakeClient) retrieveInfo() (string, error) {
+	return readFile(c.infoFile)
+}
+
+func (fakeClient) retrieveClusterInfo() (string, error) {
+	return readFile("cluster_info")
+}
+
+func (fakeClient) retrieveCommandStats() (string, error) {
+	return readFile("commandstats")
+}
+
+// the replies as decoded by the go-redis client
+func (fakeClient) retrieveLatencyLatest() ([]interface{}, error) {
+	return []interface{}{
+		[]interface{}{"command", int64(1622035461), int64(251), int64(1001)},
+		[]interface{}{"fast-command", int64(1622035423), int64(12), int64(37)},
+	}, nil
+}
+
+func (fakeClient) retrieveSlowLog(count int) ([]interface{}, error) {
+	reply := []interface{}{
+		[]interface{}{int64(14), int64(1622035461), int64(12087), []interface{}{"KEYS", "*"}, "127.0.0.1:58217", "worker-1"},
+		[]interface{}{int64(13), int64(1622035400), int64(10550), []interface{}{"SMEMBERS", "users"}, "127.0.0.1:58217", ""},
+		// before Redis 4.0
+		[]interface{}{int64(12), int64(1622035300), int64(10020), []interface{}{"FLUSHDB"}},
+	}
+	if count < len(reply) {
+		reply = reply[:count]
+	}
+	return reply, nil
 }
 
 func readFile(fname string) (string, error) {
@@ -53,8 +90,15 @@ func readFile(fname string) (string, error) {
 }
 
 func TestRetrieveInfo(t *testing.T) {
-	g := fakeClient{}
+	g := newFakeClient()
 	res, err := g.retrieveInfo()
 	require.Nil(t, err)
 	require.True(t, strings.HasPrefix(res, "# Server"))
 }
+
+func TestRetrieveClusterInfo(t *testing.T) {
+	g := newFakeClient()
+	res, err := g.retrieveClusterInfo()
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(res, "cluster_state:ok"))
+}
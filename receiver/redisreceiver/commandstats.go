@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Holds fields returned for each command by the Commandstats section of the
+// INFO command: e.g. "cmdstat_get:calls=21,usec=175,usec_per_call=8.33"
+type commandStats struct {
+	cmd   string
+	calls int64
+	usec  int64
+}
+
+// Turns a commandstats value (the part after the colon e.g.
+// "calls=21,usec=175,usec_per_call=8.33") into a commandStats struct
+func parseCommandStatsString(cmd string, str string) (*commandStats, error) {
+	cs := commandStats{cmd: cmd}
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(
+				"unexpected commandstats pair '%s'",
+				pairStr,
+			)
+		}
+		var field *int64
+		switch pair[0] {
+		case "calls":
+			field = &cs.calls
+		case "usec":
+			field = &cs.usec
+		}
+		if field != nil {
+			val, err := strconv.ParseInt(pair[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			*field = val
+		}
+	}
+	return &cs, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	cs, err := parseCommandStatsString("client|list", "calls=3,usec=96,usec_per_call=32.00,rejected_calls=0,failed_calls=0")
+	require.Nil(t, err)
+	require.Equal(t, "client|list", cs.cmd)
+	require.Equal(t, int64(3), cs.calls)
+	require.Equal(t, int64(96), cs.usec)
+}
+
+func TestParseMalformedCommandStats(t *testing.T) {
+	tests := []struct{ name, commandStats string }{
+		{"missing value", "calls=3,usec="},
+		{"missing equals", "calls=3,usec"},
+		{"bad calls", "calls=x,usec=96"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseCommandStatsString("get", test.commandStats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestBuildCommandStatsMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.commandStats()
+	require.Nil(t, err)
+	ms, warnings := info.buildCommandStatsMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 8, ms.Len())
+
+	// sorted by command
+	calls := ms.At(0)
+	require.Equal(t, "redis/commands/calls", calls.Name())
+	pt := calls.IntSum().DataPoints().At(0)
+	cmd, _ := pt.LabelsMap().Get("cmd")
+	require.Equal(t, "client|list", cmd)
+	require.Equal(t, int64(3), pt.Value())
+
+	usec := ms.At(3)
+	require.Equal(t, "redis/commands/time", usec.Name())
+	require.Equal(t, "us", usec.Unit())
+	pt = usec.IntSum().DataPoints().At(0)
+	cmd, _ = pt.LabelsMap().Get("cmd")
+	require.Equal(t, "get", cmd)
+	require.Equal(t, int64(5296), pt.Value())
+}
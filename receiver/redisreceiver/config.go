@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 type Config struct {
@@ -33,7 +34,27 @@ type Config struct {
 
 	// TODO allow users to add additional resource key value pairs?
 
+	// Optional username, to authenticate as one of the users of the Redis 6
+	// ACL system. When empty, the password authenticates the default user.
+	Username string `mapstructure:"username"`
+
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the user.
 	Password string `mapstructure:"password"`
+
+	// TLS settings of the connection to Redis. Plaintext by default, TLS is
+	// enabled by setting insecure to false or by setting a CA file.
+	TLS configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Whether to collect the per command statistics returned by the INFO
+	// commandstats command.
+	CommandStats bool `mapstructure:"command_stats"`
+
+	// Whether to collect the latency spikes returned by the LATENCY LATEST
+	// command. Requires the latency monitor of Redis to be enabled.
+	Latency bool `mapstructure:"latency"`
+
+	// The maximum number of slow log entries retrieved on each run, when the
+	// receiver is part of a logs pipeline.
+	SlowLogMaxEntries int `mapstructure:"slowlog_max_entries"`
 }
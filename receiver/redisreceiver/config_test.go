@@ -0,0 +1,66 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(cfg.Receivers))
+
+	r := cfg.Receivers[config.NewID(typeStr)].(*Config)
+	assert.Equal(t, "localhost:6379", r.Endpoint)
+	assert.Equal(t, 10*time.Second, r.CollectionInterval)
+	tlsConfig, err := r.TLS.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	r = cfg.Receivers[config.NewIDWithName(typeStr, "tls")].(*Config)
+	assert.Equal(t, &Config{
+		ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "tls")),
+		Endpoint:           "redis.example.com:6380",
+		CollectionInterval: 30 * time.Second,
+		ServiceName:        "my-tls-redis",
+		Username:           "otel",
+		Password:           "secret",
+		TLS: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{
+				CAFile: "/etc/redis/ca.pem",
+			},
+			Insecure:   true,
+			ServerName: "redis.example.com",
+		},
+		CommandStats:      true,
+		Latency:           true,
+		SlowLogMaxEntries: 32,
+	}, r)
+}
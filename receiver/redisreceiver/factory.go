@@ -16,10 +16,12 @@ package redisreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -33,13 +35,20 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver),
+	)
 }
 
 func createDefaultConfig() config.Receiver {
 	return &Config{
 		ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
 		CollectionInterval: 10 * time.Second,
+		TLS: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		// the default slowlog-max-len of Redis
+		SlowLogMaxEntries: 128,
 	}
 }
 
@@ -49,7 +58,40 @@ func createMetricsReceiver(
 	cfg config.Receiver,
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
-	oCfg := cfg.(*Config)
+	r := getOrCreateReceiver(cfg.(*Config), params)
+	r.consumer = consumer
+	return r, nil
+}
+
+// createLogsReceiver creates a receiver sending the Redis slow log entries as
+// logs, shared with the metrics receiver of the same config.
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	r := getOrCreateReceiver(cfg.(*Config), params)
+	r.logsConsumer = consumer
+	return r, nil
+}
 
-	return newRedisReceiver(params.Logger, oCfg, consumer), nil
+func getOrCreateReceiver(cfg *Config, params component.ReceiverCreateParams) *redisReceiver {
+	receiverLock.Lock()
+	defer receiverLock.Unlock()
+	r := receivers[cfg]
+	if r == nil {
+		r = newRedisReceiver(params.Logger, cfg)
+		receivers[cfg] = r
+	}
+	return r
 }
+
+func removeReceiver(cfg *Config) {
+	receiverLock.Lock()
+	defer receiverLock.Unlock()
+	delete(receivers, cfg)
+}
+
+var receiverLock sync.Mutex
+var receivers = map[*Config]*redisReceiver{}
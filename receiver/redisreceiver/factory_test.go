@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateReceivers(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	mr, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lr, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	// the slow log entries are collected by the same receiver as the metrics
+	require.Same(t, mr, lr)
+
+	other, err := f.CreateLogsReceiver(context.Background(), params, f.CreateDefaultConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotSame(t, mr, other)
+}
+
+func TestSharedReceiverLifecycle(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	mr, err := f.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lr, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// each pipeline starts and shuts down the shared receiver
+	require.NoError(t, mr.Start(context.Background(), componenttest.NewNopHost()))
+	runner := mr.(*redisReceiver).intervalRunner
+	require.NoError(t, lr.Start(context.Background(), componenttest.NewNopHost()))
+	require.Same(t, runner, mr.(*redisReceiver).intervalRunner)
+	require.NoError(t, mr.Shutdown(context.Background()))
+	require.NoError(t, lr.Shutdown(context.Background()))
+
+	receiverLock.Lock()
+	_, ok := receivers[cfg]
+	receiverLock.Unlock()
+	require.False(t, ok)
+}
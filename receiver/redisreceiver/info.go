@@ -17,7 +17,9 @@ package redisreceiver
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
@@ -81,3 +83,80 @@ func (i info) getUptimeInSeconds() (int, error) {
 	}
 	return strconv.Atoi(uptimeStr)
 }
+
+// Builds metrics from the replication state in Redis INFO: on a master, the
+// offset and lag of each of the replicas, e.g.
+// "slave0:ip=10.0.0.2,port=6379,state=online,offset=3862,lag=0", and on a
+// replica, the state of the link to its master. Returns metrics and parsing
+// errors, to be treated as warnings, if there were any.
+func (i info) buildReplicationMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	for n := 0; ; n++ {
+		str, ok := i["slave"+strconv.Itoa(n)]
+		if !ok {
+			break
+		}
+		r, parsingError := parseReplicaString(str)
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		ms := buildReplicaPair(r, t)
+		ms.MoveAndAppendTo(outMS)
+	}
+
+	if i["role"] != "slave" {
+		return outMS, warnings
+	}
+	initMasterLinkMetric(i["master_link_status"] == "up", t, outMS.AppendEmpty())
+	ms, fixedWarnings := i.buildFixedMetrics(getReplicaRedisMetrics(), t)
+	ms.MoveAndAppendTo(outMS)
+	return outMS, append(warnings, fixedWarnings...)
+}
+
+// Builds metrics from the result of the Redis CLUSTER INFO command, which has
+// the same format as INFO. Returns metrics and parsing errors, to be treated
+// as warnings, if there were any.
+func (i info) buildClusterMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	if state, ok := i["cluster_state"]; ok {
+		initClusterStateMetric(state == "ok", t, outMS.AppendEmpty())
+	} else {
+		warnings = append(warnings, errors.New("info key not found: cluster_state"))
+	}
+	ms, fixedWarnings := i.buildFixedMetrics(getClusterRedisMetrics(), t)
+	ms.MoveAndAppendTo(outMS)
+	return outMS, append(warnings, fixedWarnings...)
+}
+
+// Builds metrics from the result of the Redis INFO commandstats command: e.g.
+// "cmdstat_get:calls=21,usec=175,usec_per_call=8.33". Returns metrics and
+// parsing errors, to be treated as warnings, if there were any.
+func (i info) buildCommandStatsMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	const prefix = "cmdstat_"
+	outMS = pdata.NewMetricSlice()
+	// sorted so that the metrics are in a stable order
+	var keys []string
+	for key := range i {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		cs, parsingError := parseCommandStatsString(strings.TrimPrefix(key, prefix), i[key])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		ms := buildCommandStatsPair(cs, t)
+		ms.MoveAndAppendTo(outMS)
+	}
+	return outMS, warnings
+}
+
+// Whether cluster mode is enabled, in which case the cluster state is
+// retrieved with the CLUSTER INFO command.
+func (i info) clusterEnabled() bool {
+	return i["cluster_enabled"] == "1"
+}
@@ -16,8 +16,10 @@ package redisreceiver
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestGetUptime(t *testing.T) {
@@ -27,3 +29,89 @@ func TestGetUptime(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 104946, uptime)
 }
+
+func TestBuildReplicationMetrics_Master(t *testing.T) {
+	svc := newRedisSvc(newFakeClientWithInfo("info_cluster_master"))
+	info, err := svc.info()
+	require.Nil(t, err)
+	ms, warnings := info.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 4, ms.Len())
+
+	expected := []struct {
+		name, replica string
+		value         int64
+	}{
+		{"redis/replication/replica/offset", "10.0.0.2:6379", 3862},
+		{"redis/replication/replica/lag", "10.0.0.2:6379", 0},
+		{"redis/replication/replica/offset", "[2001:db8::3]:6380", 3848},
+		{"redis/replication/replica/lag", "[2001:db8::3]:6380", 1},
+	}
+	for i, e := range expected {
+		m := ms.At(i)
+		require.Equal(t, e.name, m.Name())
+		pt := m.IntGauge().DataPoints().At(0)
+		require.Equal(t, e.value, pt.Value())
+		replica, _ := pt.LabelsMap().Get("replica")
+		require.Equal(t, e.replica, replica)
+	}
+}
+
+func TestBuildReplicationMetrics_Replica(t *testing.T) {
+	svc := newRedisSvc(newFakeClientWithInfo("info_replica"))
+	info, err := svc.info()
+	require.Nil(t, err)
+	ms, warnings := info.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 2, ms.Len())
+	require.Equal(t, "redis/replication/master/link_up", ms.At(0).Name())
+	require.Equal(t, int64(1), ms.At(0).IntGauge().DataPoints().At(0).Value())
+	require.Equal(t, "redis/replication/master/last_io", ms.At(1).Name())
+	require.Equal(t, int64(3), ms.At(1).IntGauge().DataPoints().At(0).Value())
+}
+
+func TestBuildReplicationMetrics_Standalone(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.info()
+	require.Nil(t, err)
+	ms, warnings := info.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 0, ms.Len())
+	require.False(t, info.clusterEnabled())
+}
+
+func TestBuildReplicationMetrics_Malformed(t *testing.T) {
+	info := info{"slave0": "ip=10.0.0.2,port=6379,offset=x", "slave1": "ip=10.0.0.3,port=6379,offset=1,lag=0"}
+	ms, warnings := info.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	require.Equal(t, 1, len(warnings))
+	require.Equal(t, 2, ms.Len())
+}
+
+func TestBuildClusterMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.clusterInfo()
+	require.Nil(t, err)
+	ms, warnings := info.buildClusterMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		require.Equal(t, pdata.MetricDataTypeIntGauge, m.DataType())
+		pt := m.IntGauge().DataPoints().At(0)
+		name := m.Name()
+		if state, ok := pt.LabelsMap().Get("state"); ok {
+			name += "/" + state
+		}
+		values[name] = pt.Value()
+	}
+	require.Equal(t, map[string]int64{
+		"redis/cluster/state":          1,
+		"redis/cluster/slots/assigned": 16384,
+		"redis/cluster/slots/ok":       16380,
+		"redis/cluster/slots/pfail":    3,
+		"redis/cluster/slots/fail":     1,
+		"redis/cluster/known_nodes":    6,
+		"redis/cluster/size":           3,
+	}, values)
+}
@@ -0,0 +1,49 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import "fmt"
+
+// Holds the fields returned for each event by the LATENCY LATEST command: the
+// event name, the unix time of the latest latency spike, and the latest and
+// all-time maximum latencies in milliseconds, e.g. ["command", 1405067976,
+// 251, 1001].
+type latencyEvent struct {
+	event  string
+	latest int64
+	max    int64
+}
+
+// Turns the reply of LATENCY LATEST into latencyEvents.
+func parseLatencyLatest(reply []interface{}) ([]latencyEvent, error) {
+	events := make([]latencyEvent, 0, len(reply))
+	for _, r := range reply {
+		fields, ok := r.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected latency event '%v'", r)
+		}
+		event, ok := fields[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected latency event name '%v'", fields[0])
+		}
+		latest, ok1 := fields[2].(int64)
+		max, ok2 := fields[3].(int64)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("unexpected latencies of event '%s'", event)
+		}
+		events = append(events, latencyEvent{event: event, latest: latest, max: max})
+	}
+	return events, nil
+}
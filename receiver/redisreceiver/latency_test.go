@@ -0,0 +1,49 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyLatest(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	events, err := svc.latencyLatest()
+	require.Nil(t, err)
+	require.Equal(t, []latencyEvent{
+		{event: "command", latest: 251, max: 1001},
+		{event: "fast-command", latest: 12, max: 37},
+	}, events)
+}
+
+func TestParseMalformedLatencyLatest(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []interface{}
+	}{
+		{"not an array", []interface{}{"command"}},
+		{"missing fields", []interface{}{[]interface{}{"command", int64(1622035461), int64(251)}}},
+		{"bad event name", []interface{}{[]interface{}{int64(1), int64(1622035461), int64(251), int64(1001)}}},
+		{"bad latency", []interface{}{[]interface{}{"command", int64(1622035461), "251", int64(1001)}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseLatencyLatest(test.reply)
+			require.NotNil(t, err)
+		})
+	}
+}
@@ -70,6 +70,28 @@ func getDefaultRedisMetrics() []*redisMetric {
 	}
 }
 
+// Called on each run of a replica. Returns the metrics of the link to the
+// master we want to extract from Redis INFO, only reported by replicas.
+func getReplicaRedisMetrics() []*redisMetric {
+	return []*redisMetric{
+		masterLastIOSecondsAgo(),
+	}
+}
+
+// Called on each run when cluster mode is enabled. Returns the metrics we
+// want to extract from Redis CLUSTER INFO.
+func getClusterRedisMetrics() []*redisMetric {
+	return []*redisMetric{
+		clusterSlotsAssigned(),
+		clusterSlotsOK(),
+		clusterSlotsPFail(),
+		clusterSlotsFail(),
+
+		clusterKnownNodes(),
+		clusterSize(),
+	}
+}
+
 func uptimeInSeconds() *redisMetric {
 	return &redisMetric{
 		key:         "uptime_in_seconds",
@@ -353,3 +375,71 @@ func masterReplOffset() *redisMetric {
 		desc:   "The server's current replication offset",
 	}
 }
+
+func masterLastIOSecondsAgo() *redisMetric {
+	return &redisMetric{
+		key:    "master_last_io_seconds_ago",
+		name:   "redis/replication/master/last_io",
+		units:  "s",
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Number of seconds since the last interaction with the master",
+	}
+}
+
+func clusterSlotsAssigned() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_slots_assigned",
+		name:   "redis/cluster/slots",
+		pdType: pdata.MetricDataTypeIntGauge,
+		labels: map[string]string{"state": "assigned"},
+		desc:   "Number of slots associated to some node",
+	}
+}
+
+func clusterSlotsOK() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_slots_ok",
+		name:   "redis/cluster/slots",
+		pdType: pdata.MetricDataTypeIntGauge,
+		labels: map[string]string{"state": "ok"},
+		desc:   "Number of slots mapped to nodes not in FAIL or PFAIL state",
+	}
+}
+
+func clusterSlotsPFail() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_slots_pfail",
+		name:   "redis/cluster/slots",
+		pdType: pdata.MetricDataTypeIntGauge,
+		labels: map[string]string{"state": "pfail"},
+		desc:   "Number of slots mapped to nodes in PFAIL state",
+	}
+}
+
+func clusterSlotsFail() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_slots_fail",
+		name:   "redis/cluster/slots",
+		pdType: pdata.MetricDataTypeIntGauge,
+		labels: map[string]string{"state": "fail"},
+		desc:   "Number of slots mapped to nodes in FAIL state",
+	}
+}
+
+func clusterKnownNodes() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_known_nodes",
+		name:   "redis/cluster/known_nodes",
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Number of nodes known by the cluster, including the nodes in handshake state",
+	}
+}
+
+func clusterSize() *redisMetric {
+	return &redisMetric{
+		key:    "cluster_size",
+		name:   "redis/cluster/size",
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Number of master nodes serving at least one slot",
+	}
+}
@@ -23,7 +23,11 @@ import (
 )
 
 func TestDefaultMetrics(t *testing.T) {
-	for _, metric := range getDefaultRedisMetrics() {
+	var metrics []*redisMetric
+	metrics = append(metrics, getDefaultRedisMetrics()...)
+	metrics = append(metrics, getReplicaRedisMetrics()...)
+	metrics = append(metrics, getClusterRedisMetrics()...)
+	for _, metric := range metrics {
 		require.True(t, len(metric.key) > 0)
 		require.True(t, len(metric.name) > 0)
 		require.True(t, strings.HasPrefix(metric.name, "redis/"))
@@ -109,3 +109,118 @@ func redisMetricToPDM(m *redisMetric, dest pdata.Metric) {
 	dest.SetDescription(m.desc)
 	dest.SetUnit(m.units)
 }
+
+func buildReplicaPair(r *replica, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	ms.Resize(2)
+	initReplicaOffsetMetric(r, t, ms.At(0))
+	initReplicaLagMetric(r, t, ms.At(1))
+	return ms
+}
+
+func initReplicaOffsetMetric(r *replica, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/replication/replica/offset",
+		labels: map[string]string{"replica": r.addr},
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "The replication offset acknowledged by the replica",
+	}
+	initIntMetric(m, r.offset, t, dest)
+}
+
+func initReplicaLagMetric(r *replica, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/replication/replica/lag",
+		units:  "s",
+		labels: map[string]string{"replica": r.addr},
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Number of seconds since the last acknowledgement of the replica",
+	}
+	initIntMetric(m, r.lag, t, dest)
+}
+
+func initMasterLinkMetric(up bool, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/replication/master/link_up",
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Whether the link of the replica to its master is up (1) or down (0)",
+	}
+	initIntMetric(m, boolToInt64(up), t, dest)
+}
+
+func initClusterStateMetric(ok bool, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/cluster/state",
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Whether the cluster is able to serve queries (1) or not (0)",
+	}
+	initIntMetric(m, boolToInt64(ok), t, dest)
+}
+
+func buildCommandStatsPair(cs *commandStats, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	ms.Resize(2)
+	initCommandCallsMetric(cs, t, ms.At(0))
+	initCommandTimeMetric(cs, t, ms.At(1))
+	return ms
+}
+
+func initCommandCallsMetric(cs *commandStats, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:        "redis/commands/calls",
+		labels:      map[string]string{"cmd": cs.cmd},
+		pdType:      pdata.MetricDataTypeIntSum,
+		isMonotonic: true,
+		desc:        "Number of calls of the command",
+	}
+	initIntMetric(m, cs.calls, t, dest)
+}
+
+func initCommandTimeMetric(cs *commandStats, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:        "redis/commands/time",
+		units:       "us",
+		labels:      map[string]string{"cmd": cs.cmd},
+		pdType:      pdata.MetricDataTypeIntSum,
+		isMonotonic: true,
+		desc:        "Total CPU time consumed by the calls of the command",
+	}
+	initIntMetric(m, cs.usec, t, dest)
+}
+
+func buildLatencyPair(e *latencyEvent, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	ms.Resize(2)
+	initLatencyLatestMetric(e, t, ms.At(0))
+	initLatencyMaxMetric(e, t, ms.At(1))
+	return ms
+}
+
+func initLatencyLatestMetric(e *latencyEvent, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/latency/latest",
+		units:  "ms",
+		labels: map[string]string{"event": e.event},
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Latency of the latest latency spike of the event",
+	}
+	initIntMetric(m, e.latest, t, dest)
+}
+
+func initLatencyMaxMetric(e *latencyEvent, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   "redis/latency/max",
+		units:  "ms",
+		labels: map[string]string{"event": e.event},
+		pdType: pdata.MetricDataTypeIntGauge,
+		desc:   "Maximum latency of the latency spikes of the event",
+	}
+	initIntMetric(m, e.max, t, dest)
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
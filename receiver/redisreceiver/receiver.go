@@ -16,6 +16,7 @@ package redisreceiver
 
 import (
 	"context"
+	"sync"
 
 	"github.com/go-redis/redis/v7"
 	"go.opentelemetry.io/collector/component"
@@ -25,32 +26,50 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
+// The receiver is shared by the metrics and logs pipelines, the slow log
+// entries being sent as logs, so it's started and shut down once whatever the
+// number of pipelines.
 type redisReceiver struct {
 	logger         *zap.Logger
 	config         *Config
 	consumer       consumer.Metrics
+	logsConsumer   consumer.Logs
 	intervalRunner *interval.Runner
+	startOnce      sync.Once
+	startErr       error
+	shutdownOnce   sync.Once
 }
 
 func newRedisReceiver(
 	logger *zap.Logger,
 	config *Config,
-	consumer consumer.Metrics,
 ) *redisReceiver {
 	return &redisReceiver{
-		logger:   logger,
-		config:   config,
-		consumer: consumer,
+		logger: logger,
+		config: config,
 	}
 }
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
+	r.startOnce.Do(func() {
+		r.startErr = r.start(ctx, host)
+	})
+	return r.startErr
+}
+
+func (r *redisReceiver) start(ctx context.Context, host component.Host) error {
+	tlsConfig, err := r.config.TLS.LoadTLSConfig()
+	if err != nil {
+		return err
+	}
 	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
+		Addr:      r.config.Endpoint,
+		Username:  r.config.Username,
+		Password:  r.config.Password,
+		TLSConfig: tlsConfig,
 	})
-	redisRunnable := newRedisRunnable(ctx, r.config.ID(), c, r.config.ServiceName, r.consumer, r.logger)
+	redisRunnable := newRedisRunnable(ctx, r.config, c, r.consumer, r.logsConsumer, r.logger)
 	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
 
 	go func() {
@@ -62,7 +81,14 @@ func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// Stop the interval runner and forget the receiver, so that its config may
+// be used again.
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
-	r.intervalRunner.Stop()
+	r.shutdownOnce.Do(func() {
+		if r.intervalRunner != nil {
+			r.intervalRunner.Stop()
+		}
+		removeReceiver(r.config)
+	})
 	return nil
 }
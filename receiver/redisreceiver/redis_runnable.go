@@ -29,34 +29,52 @@ import (
 
 var _ interval.Runnable = (*redisRunnable)(nil)
 
+const (
+	dataFormat = "redis"
+	transport  = "http" // todo verify this
+)
+
 // Runs intermittently, fetching info from Redis, creating metrics/datapoints,
-// and feeding them to a metricsConsumer.
+// and feeding them to a metricsConsumer, and fetching the slow log entries
+// and feeding them to a logsConsumer. Either consumer may be nil, when the
+// receiver isn't part of a pipeline of that type.
 type redisRunnable struct {
-	id              config.ComponentID
-	ctx             context.Context
-	metricsConsumer consumer.Metrics
-	redisSvc        *redisSvc
-	redisMetrics    []*redisMetric
-	logger          *zap.Logger
-	timeBundle      *timeBundle
-	serviceName     string
+	id                config.ComponentID
+	ctx               context.Context
+	metricsConsumer   consumer.Metrics
+	logsConsumer      consumer.Logs
+	redisSvc          *redisSvc
+	redisMetrics      []*redisMetric
+	logger            *zap.Logger
+	timeBundle        *timeBundle
+	serviceName       string
+	commandStats      bool
+	latency           bool
+	slowLogMaxEntries int
+	slowLogStarted    bool
+	lastSlowLogID     int64
 }
 
 func newRedisRunnable(
 	ctx context.Context,
-	id config.ComponentID,
+	cfg *Config,
 	client client,
-	serviceName string,
 	metricsConsumer consumer.Metrics,
+	logsConsumer consumer.Logs,
 	logger *zap.Logger,
 ) *redisRunnable {
 	return &redisRunnable{
-		id:              id,
-		ctx:             ctx,
-		serviceName:     serviceName,
-		redisSvc:        newRedisSvc(client),
-		metricsConsumer: metricsConsumer,
-		logger:          logger,
+		id:                cfg.ID(),
+		ctx:               ctx,
+		serviceName:       cfg.ServiceName,
+		redisSvc:          newRedisSvc(client),
+		metricsConsumer:   metricsConsumer,
+		logsConsumer:      logsConsumer,
+		logger:            logger,
+		commandStats:      cfg.CommandStats,
+		latency:           cfg.Latency,
+		slowLogMaxEntries: cfg.SlowLogMaxEntries,
+		lastSlowLogID:     -1,
 	}
 }
 
@@ -67,26 +85,37 @@ func (r *redisRunnable) Setup() error {
 	return nil
 }
 
-// Run is called periodically, querying Redis and building Metrics to send to
-// the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
+// Run is called periodically, querying Redis and sending the metrics and the
+// slow log entries to the next consumers.
+func (r *redisRunnable) Run() error {
+	if r.metricsConsumer != nil {
+		r.collectMetrics()
+	}
+	if r.logsConsumer != nil {
+		r.collectSlowLog()
+	}
+	return nil
+}
+
+// Builds Metrics and sends them to the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
 // defined at startup time. Then builds 'keyspace' metrics if there are any
 // keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
-func (r *redisRunnable) Run() error {
-	const dataFormat = "redis"
-	const transport = "http" // todo verify this
+// active Redis database, of which there can be 16. Then builds the
+// replication metrics, the cluster metrics when cluster mode is enabled, and
+// the optional command statistics and latency metrics.
+func (r *redisRunnable) collectMetrics() {
 	ctx := obsreport.StartMetricsReceiveOp(r.ctx, r.id, transport)
 
 	inf, err := r.redisSvc.info()
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, err)
-		return nil
+		return
 	}
 
 	uptime, err := inf.getUptimeInSeconds()
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, err)
-		return nil
+		return
 	}
 
 	if r.timeBundle == nil {
@@ -119,9 +148,126 @@ func (r *redisRunnable) Run() error {
 	}
 	keyspaceMS.MoveAndAppendTo(ilm.Metrics())
 
+	replicationMS, warnings := inf.buildReplicationMetrics(r.timeBundle)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing replication string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	replicationMS.MoveAndAppendTo(ilm.Metrics())
+
+	if inf.clusterEnabled() {
+		r.buildClusterMetrics().MoveAndAppendTo(ilm.Metrics())
+	}
+	if r.commandStats {
+		r.buildCommandStatsMetrics().MoveAndAppendTo(ilm.Metrics())
+	}
+	if r.latency {
+		r.buildLatencyMetrics().MoveAndAppendTo(ilm.Metrics())
+	}
+
 	err = r.metricsConsumer.ConsumeMetrics(r.ctx, pdm)
 	_, numPoints := pdm.MetricAndDataPointCount()
 	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, err)
+}
 
-	return nil
+// Queries the Redis CLUSTER INFO and builds the cluster metrics. Failures are
+// logged so that the metrics from INFO are still sent.
+func (r *redisRunnable) buildClusterMetrics() pdata.MetricSlice {
+	clusterInf, err := r.redisSvc.clusterInfo()
+	if err != nil {
+		r.logger.Warn("failed to retrieve redis cluster info", zap.Error(err))
+		return pdata.NewMetricSlice()
+	}
+	clusterMS, warnings := clusterInf.buildClusterMetrics(r.timeBundle)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing cluster string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	return clusterMS
+}
+
+// Queries the Redis INFO commandstats and builds the per command metrics.
+// Failures are logged so that the other metrics are still sent.
+func (r *redisRunnable) buildCommandStatsMetrics() pdata.MetricSlice {
+	commandStatsInf, err := r.redisSvc.commandStats()
+	if err != nil {
+		r.logger.Warn("failed to retrieve redis command stats", zap.Error(err))
+		return pdata.NewMetricSlice()
+	}
+	commandStatsMS, warnings := commandStatsInf.buildCommandStatsMetrics(r.timeBundle)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing commandstats string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	return commandStatsMS
+}
+
+// Queries the Redis LATENCY LATEST and builds the latency metrics of each of
+// the events. Failures are logged so that the other metrics are still sent.
+func (r *redisRunnable) buildLatencyMetrics() pdata.MetricSlice {
+	outMS := pdata.NewMetricSlice()
+	events, err := r.redisSvc.latencyLatest()
+	if err != nil {
+		r.logger.Warn("failed to retrieve redis latency", zap.Error(err))
+		return outMS
+	}
+	for i := range events {
+		ms := buildLatencyPair(&events[i], r.timeBundle)
+		ms.MoveAndAppendTo(outMS)
+	}
+	return outMS
+}
+
+// Queries the Redis SLOWLOG and sends the entries added since the previous
+// run to the next logs consumer.
+func (r *redisRunnable) collectSlowLog() {
+	ctx := obsreport.StartLogsReceiveOp(r.ctx, r.id, transport)
+
+	entries, err := r.redisSvc.slowLog(r.slowLogMaxEntries)
+	if err != nil {
+		obsreport.EndLogsReceiveOp(ctx, dataFormat, 0, err)
+		return
+	}
+	entries = r.newSlowLogEntries(entries)
+	if len(entries) == 0 {
+		obsreport.EndLogsReceiveOp(ctx, dataFormat, 0, nil)
+		return
+	}
+
+	err = r.logsConsumer.ConsumeLogs(r.ctx, buildSlowLogs(entries, r.serviceName))
+	obsreport.EndLogsReceiveOp(ctx, dataFormat, len(entries), err)
+}
+
+// Returns the slow log entries, ordered from the most recent, whose IDs are
+// greater than the ones of the previous run. The entries already in the slow
+// log on the first run are skipped, only the most recent ID being recorded.
+// The IDs start over from zero when Redis restarts, in which case all of the
+// entries are new.
+func (r *redisRunnable) newSlowLogEntries(entries []slowLogEntry) []slowLogEntry {
+	if !r.slowLogStarted {
+		r.slowLogStarted = true
+		if len(entries) > 0 {
+			r.lastSlowLogID = entries[0].id
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	lastID := r.lastSlowLogID
+	r.lastSlowLogID = entries[0].id
+	if entries[0].id < lastID {
+		return entries
+	}
+	n := 0
+	for n < len(entries) && entries[n].id > lastID {
+		n++
+	}
+	return entries[:n]
 }
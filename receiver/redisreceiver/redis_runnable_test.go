@@ -19,7 +19,6 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
 )
@@ -27,7 +26,7 @@ import (
 func TestRedisRunnable(t *testing.T) {
 	consumer := new(consumertest.MetricsSink)
 	logger, _ := zap.NewDevelopment()
-	runner := newRedisRunnable(context.Background(), config.NewID(typeStr), newFakeClient(), "", consumer, logger)
+	runner := newRedisRunnable(context.Background(), createDefaultConfig().(*Config), newFakeClient(), consumer, nil, logger)
 	err := runner.Setup()
 	require.Nil(t, err)
 	err = runner.Run()
@@ -35,3 +34,80 @@ func TestRedisRunnable(t *testing.T) {
 	// + 6 because there are two keyspace entries each of which has three metrics
 	require.Equal(t, len(getDefaultRedisMetrics())+6, consumer.MetricsCount())
 }
+
+func TestRedisRunnable_ClusterMaster(t *testing.T) {
+	consumer := new(consumertest.MetricsSink)
+	runner := newRedisRunnable(context.Background(), createDefaultConfig().(*Config), newFakeClientWithInfo("info_cluster_master"), consumer, nil, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	require.Nil(t, runner.Run())
+	// + 6 keyspace metrics, + 4 because there are two replicas each of which
+	// has two metrics, + 7 cluster metrics
+	require.Equal(t, len(getDefaultRedisMetrics())+6+4+7, consumer.MetricsCount())
+}
+
+func TestRedisRunnable_CommandStatsAndLatency(t *testing.T) {
+	consumer := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.CommandStats = true
+	cfg.Latency = true
+	runner := newRedisRunnable(context.Background(), cfg, newFakeClient(), consumer, nil, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	require.Nil(t, runner.Run())
+	// + 6 keyspace metrics, + 8 because there are four commands each of which
+	// has two metrics, + 4 because there are two latency events each of which
+	// has two metrics
+	require.Equal(t, len(getDefaultRedisMetrics())+6+8+4, consumer.MetricsCount())
+}
+
+func TestRedisRunnable_SlowLog(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SlowLogMaxEntries = 2
+	runner := newRedisRunnable(context.Background(), cfg, newFakeClient(), nil, sink, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	// the entries already in the slow log aren't sent
+	require.Nil(t, runner.Run())
+	require.Equal(t, 0, sink.LogRecordsCount())
+	require.Equal(t, int64(14), runner.lastSlowLogID)
+
+	runner.lastSlowLogID = 12
+	require.Nil(t, runner.Run())
+	require.Equal(t, 2, sink.LogRecordsCount())
+	require.Equal(t, int64(14), runner.lastSlowLogID)
+
+	// the entries are only sent once
+	require.Nil(t, runner.Run())
+	require.Equal(t, 2, sink.LogRecordsCount())
+}
+
+func TestNewSlowLogEntries(t *testing.T) {
+	runner := newRedisRunnable(context.Background(), createDefaultConfig().(*Config), newFakeClient(), nil, nil, zap.NewNop())
+	ids := func(entries []slowLogEntry) []int64 {
+		var ids []int64
+		for _, e := range entries {
+			ids = append(ids, e.id)
+		}
+		return ids
+	}
+	entries := func(ids ...int64) []slowLogEntry {
+		var entries []slowLogEntry
+		for _, id := range ids {
+			entries = append(entries, slowLogEntry{id: id})
+		}
+		return entries
+	}
+
+	// the first run only records the most recent ID
+	require.Nil(t, runner.newSlowLogEntries(entries(1, 0)))
+	require.Equal(t, int64(1), runner.lastSlowLogID)
+	require.Nil(t, runner.newSlowLogEntries(nil))
+	require.Equal(t, []int64{3, 2}, ids(runner.newSlowLogEntries(entries(3, 2, 1))))
+	require.Empty(t, runner.newSlowLogEntries(entries(3, 2, 1)))
+	// Redis restarted
+	require.Equal(t, []int64{0}, ids(runner.newSlowLogEntries(entries(0))))
+
+	// the slow log was empty on the first run
+	runner = newRedisRunnable(context.Background(), createDefaultConfig().(*Config), newFakeClient(), nil, nil, zap.NewNop())
+	require.Nil(t, runner.newSlowLogEntries(nil))
+	require.Equal(t, []int64{0}, ids(runner.newSlowLogEntries(entries(0))))
+}
@@ -39,16 +39,61 @@ func (p *redisSvc) info() (info, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.parse(str), nil
+}
+
+// Calls the Redis CLUSTER INFO command on the client and returns an `info`
+// map, CLUSTER INFO having the same format as INFO.
+func (p *redisSvc) clusterInfo() (info, error) {
+	str, err := p.client.retrieveClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return p.parse(str), nil
+}
+
+// Calls the Redis INFO commandstats command on the client and returns an
+// `info` map of the per command statistics.
+func (p *redisSvc) commandStats() (info, error) {
+	str, err := p.client.retrieveCommandStats()
+	if err != nil {
+		return nil, err
+	}
+	return p.parse(str), nil
+}
+
+// Calls the Redis LATENCY LATEST command on the client and returns the latency
+// events.
+func (p *redisSvc) latencyLatest() ([]latencyEvent, error) {
+	reply, err := p.client.retrieveLatencyLatest()
+	if err != nil {
+		return nil, err
+	}
+	return parseLatencyLatest(reply)
+}
+
+// Calls the Redis SLOWLOG GET command on the client and returns at most
+// `count` slow log entries, the most recent first.
+func (p *redisSvc) slowLog(count int) ([]slowLogEntry, error) {
+	reply, err := p.client.retrieveSlowLog(count)
+	if err != nil {
+		return nil, err
+	}
+	return parseSlowLog(reply)
+}
+
+func (p *redisSvc) parse(str string) info {
 	lines := strings.Split(str, p.delimiter)
 	attrs := make(map[string]string)
 	for _, line := range lines {
 		if len(line) == 0 || strings.HasPrefix(line, "#") {
 			continue
 		}
-		pair := strings.Split(line, ":")
+		// values may contain colons, e.g. the IPv6 addresses of the replicas
+		pair := strings.SplitN(line, ":", 2)
 		if len(pair) == 2 { // defensive, should always == 2
 			attrs[pair[0]] = pair[1]
 		}
 	}
-	return attrs, nil
+	return attrs
 }
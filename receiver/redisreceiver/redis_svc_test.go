@@ -21,7 +21,7 @@ import (
 )
 
 func newFakeAPIParser() *redisSvc {
-	return newRedisSvc(fakeClient{})
+	return newRedisSvc(newFakeClient())
 }
 
 func TestParser(t *testing.T) {
@@ -31,3 +31,19 @@ func TestParser(t *testing.T) {
 	require.Equal(t, 123, len(info))
 	require.Equal(t, "1.24", info["allocator_frag_ratio"]) // spot check
 }
+
+func TestParserReplicas(t *testing.T) {
+	s := newRedisSvc(newFakeClientWithInfo("info_cluster_master"))
+	info, err := s.info()
+	require.Nil(t, err)
+	// the colons of the IPv6 address are kept
+	require.Equal(t, "ip=2001:db8::3,port=6380,state=wait_bgsave,offset=3848,lag=1", info["slave1"])
+}
+
+func TestClusterInfoParser(t *testing.T) {
+	s := newFakeAPIParser()
+	info, err := s.clusterInfo()
+	require.Nil(t, err)
+	require.Equal(t, 15, len(info))
+	require.Equal(t, "ok", info["cluster_state"])
+}
@@ -0,0 +1,71 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Holds fields returned for each replica by the Replication section of the
+// INFO command of a master: e.g.
+// "slave0:ip=10.0.0.2,port=6379,state=online,offset=3862,lag=0"
+type replica struct {
+	addr   string
+	offset int64
+	lag    int64
+}
+
+// Turns a replica value (the part after the colon e.g.
+// "ip=10.0.0.2,port=6379,state=online,offset=3862,lag=0") into a replica
+// struct. The lag is only reported by Redis 3.0 or later.
+func parseReplicaString(str string) (*replica, error) {
+	var ip, port string
+	var r replica
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.SplitN(pairStr, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(
+				"unexpected replica pair '%s'",
+				pairStr,
+			)
+		}
+		var field *int64
+		switch pair[0] {
+		case "ip":
+			ip = pair[1]
+		case "port":
+			port = pair[1]
+		case "offset":
+			field = &r.offset
+		case "lag":
+			field = &r.lag
+		}
+		if field != nil {
+			val, err := strconv.ParseInt(pair[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			*field = val
+		}
+	}
+	if ip == "" || port == "" {
+		return nil, fmt.Errorf("replica address missing from '%s'", str)
+	}
+	r.addr = net.JoinHostPort(ip, port)
+	return &r, nil
+}
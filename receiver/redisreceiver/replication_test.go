@@ -0,0 +1,44 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReplica(t *testing.T) {
+	r, err := parseReplicaString("ip=10.0.0.2,port=6379,state=online,offset=3862,lag=1")
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.2:6379", r.addr)
+	require.Equal(t, int64(3862), r.offset)
+	require.Equal(t, int64(1), r.lag)
+}
+
+func TestParseMalformedReplica(t *testing.T) {
+	tests := []struct{ name, replica string }{
+		{"missing value", "ip=10.0.0.2,port=6379,state=online,offset="},
+		{"missing equals", "ip=10.0.0.2,port=6379,state=online,offset"},
+		{"bad offset", "ip=10.0.0.2,port=6379,state=online,offset=x"},
+		{"missing address", "state=online,offset=3862,lag=1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseReplicaString(test.replica)
+			require.NotNil(t, err)
+		})
+	}
+}
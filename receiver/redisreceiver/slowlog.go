@@ -0,0 +1,108 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	slowLogName = "slowlog"
+
+	attributeSlowLogID       = "redis.slowlog.id"
+	attributeSlowLogDuration = "redis.slowlog.duration_us"
+	attributeClientAddress   = "redis.client.address"
+	attributeClientName      = "redis.client.name"
+)
+
+// Holds the fields returned for each entry by the SLOWLOG GET command: the
+// unique ID of the entry, the unix time at which the command was processed,
+// its execution time in microseconds, its arguments, and since Redis 4.0, the
+// address and name of the client, e.g.
+// [14, 1309448221, 15, ["ping"], "127.0.0.1:58217", "worker-123"].
+type slowLogEntry struct {
+	id         int64
+	time       time.Time
+	usec       int64
+	args       []string
+	clientAddr string
+	clientName string
+}
+
+// Turns the reply of SLOWLOG GET into slowLogEntries.
+func parseSlowLog(reply []interface{}) ([]slowLogEntry, error) {
+	entries := make([]slowLogEntry, 0, len(reply))
+	for _, r := range reply {
+		fields, ok := r.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected slow log entry '%v'", r)
+		}
+		id, ok1 := fields[0].(int64)
+		ts, ok2 := fields[1].(int64)
+		usec, ok3 := fields[2].(int64)
+		args, ok4 := fields[3].([]interface{})
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("unexpected slow log entry '%v'", r)
+		}
+		e := slowLogEntry{
+			id:   id,
+			time: time.Unix(ts, 0),
+			usec: usec,
+			args: make([]string, 0, len(args)),
+		}
+		for _, arg := range args {
+			e.args = append(e.args, fmt.Sprint(arg))
+		}
+		if len(fields) >= 6 {
+			e.clientAddr, _ = fields[4].(string)
+			e.clientName, _ = fields[5].(string)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Builds a log record for each of the slow log entries, the command being
+// the body of the record.
+func buildSlowLogs(entries []slowLogEntry, serviceName string) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("service.name", serviceName)
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, e := range entries {
+		statement := strings.Join(e.args, " ")
+		lr := logs.AppendEmpty()
+		lr.SetName(slowLogName)
+		lr.SetTimestamp(pdata.TimestampFromTime(e.time))
+		lr.Body().SetStringVal(statement)
+		attrs := lr.Attributes()
+		attrs.InsertString(conventions.AttributeDBSystem, "redis")
+		attrs.InsertString(conventions.AttributeDBStatement, statement)
+		attrs.InsertInt(attributeSlowLogID, e.id)
+		attrs.InsertInt(attributeSlowLogDuration, e.usec)
+		if e.clientAddr != "" {
+			attrs.InsertString(attributeClientAddress, e.clientAddr)
+		}
+		if e.clientName != "" {
+			attrs.InsertString(attributeClientName, e.clientName)
+		}
+	}
+	return ld
+}
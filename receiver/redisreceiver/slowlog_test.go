@@ -0,0 +1,97 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestParseSlowLog(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	entries, err := svc.slowLog(10)
+	require.Nil(t, err)
+	require.Equal(t, []slowLogEntry{
+		{
+			id:         14,
+			time:       time.Unix(1622035461, 0),
+			usec:       12087,
+			args:       []string{"KEYS", "*"},
+			clientAddr: "127.0.0.1:58217",
+			clientName: "worker-1",
+		},
+		{
+			id:         13,
+			time:       time.Unix(1622035400, 0),
+			usec:       10550,
+			args:       []string{"SMEMBERS", "users"},
+			clientAddr: "127.0.0.1:58217",
+		},
+		{
+			id:   12,
+			time: time.Unix(1622035300, 0),
+			usec: 10020,
+			args: []string{"FLUSHDB"},
+		},
+	}, entries)
+}
+
+func TestParseMalformedSlowLog(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []interface{}
+	}{
+		{"not an array", []interface{}{int64(14)}},
+		{"missing fields", []interface{}{[]interface{}{int64(14), int64(1622035461), int64(12087)}}},
+		{"bad arguments", []interface{}{[]interface{}{int64(14), int64(1622035461), int64(12087), "KEYS *"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseSlowLog(test.reply)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestBuildSlowLogs(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	entries, err := svc.slowLog(2)
+	require.Nil(t, err)
+	ld := buildSlowLogs(entries, "my-redis")
+	require.Equal(t, 2, ld.LogRecordCount())
+
+	rl := ld.ResourceLogs().At(0)
+	serviceName, _ := rl.Resource().Attributes().Get("service.name")
+	require.Equal(t, "my-redis", serviceName.StringVal())
+
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	lr := logs.At(0)
+	require.Equal(t, "slowlog", lr.Name())
+	require.Equal(t, "KEYS *", lr.Body().StringVal())
+	require.Equal(t, time.Unix(1622035461, 0).UnixNano(), int64(lr.Timestamp()))
+	attrs := lr.Attributes()
+	statement, _ := attrs.Get(conventions.AttributeDBStatement)
+	require.Equal(t, "KEYS *", statement.StringVal())
+	duration, _ := attrs.Get("redis.slowlog.duration_us")
+	require.Equal(t, int64(12087), duration.IntVal())
+	clientName, _ := attrs.Get("redis.client.name")
+	require.Equal(t, "worker-1", clientName.StringVal())
+
+	_, ok := logs.At(1).Attributes().Get("redis.client.name")
+	require.False(t, ok)
+}
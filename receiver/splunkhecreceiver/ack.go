@@ -0,0 +1,152 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ackManager keeps the state of the HEC indexer acknowledgements, see
+// https://docs.splunk.com/Documentation/Splunk/8.0.5/Data/AboutHECIDXAck.
+//
+// Each request received on a channel is assigned an ack ID, which becomes
+// acknowledged once the data was accepted by the next consumer. The IDs are
+// unique across the channels, so that a channel which was dropped and then
+// used again never reports an ID it assigned before as acknowledged. Clients poll
+// the ack endpoint with the IDs they are waiting for, and acknowledged IDs
+// are forgotten once reported. The state is bounded: when a channel holds
+// too many IDs the oldest ones are dropped, and when there are too many
+// channels the least recently used one is dropped. Clients treat unknown IDs
+// as not acknowledged and eventually resend the data.
+type ackManager struct {
+	mu                sync.Mutex
+	maxChannels       int
+	maxAcksPerChannel int
+	channels          map[string]*list.Element
+	// lru holds *ackChannel values, the most recently used at the front.
+	lru       *list.List
+	nextAckID uint64
+}
+
+type ackChannel struct {
+	id   string
+	acks map[uint64]*list.Element
+	// order holds *ackState values, the oldest at the front.
+	order *list.List
+}
+
+type ackState struct {
+	id    uint64
+	acked bool
+}
+
+func newAckManager(maxChannels, maxAcksPerChannel int) *ackManager {
+	return &ackManager{
+		maxChannels:       maxChannels,
+		maxAcksPerChannel: maxAcksPerChannel,
+		channels:          make(map[string]*list.Element),
+		lru:               list.New(),
+	}
+}
+
+// newAckID assigns the next ack ID to a request received on the channel that
+// was not yet consumed.
+func (am *ackManager) newAckID(channelID string) uint64 {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	ch := am.channel(channelID)
+	id := am.nextAckID
+	am.nextAckID++
+	ch.acks[id] = ch.order.PushBack(&ackState{id: id})
+
+	for ch.order.Len() > am.maxAcksPerChannel {
+		oldest := ch.order.Remove(ch.order.Front()).(*ackState)
+		delete(ch.acks, oldest.id)
+	}
+	return id
+}
+
+// complete records the result of consuming the request with the given ack
+// ID. Failed requests are forgotten since the client is told to retry them.
+func (am *ackManager) complete(channelID string, ackID uint64, success bool) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	elem, ok := am.channels[channelID]
+	if !ok {
+		return
+	}
+	ch := elem.Value.(*ackChannel)
+	ackElem, ok := ch.acks[ackID]
+	if !ok {
+		return
+	}
+	if success {
+		ackElem.Value.(*ackState).acked = true
+		return
+	}
+	ch.order.Remove(ackElem)
+	delete(ch.acks, ackID)
+}
+
+// query reports which of the given ack IDs were acknowledged. Acknowledged
+// IDs are removed, so each one is reported as acknowledged only once.
+func (am *ackManager) query(channelID string, ackIDs []uint64) map[uint64]bool {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	result := make(map[uint64]bool, len(ackIDs))
+	var ch *ackChannel
+	if elem, ok := am.channels[channelID]; ok {
+		am.lru.MoveToFront(elem)
+		ch = elem.Value.(*ackChannel)
+	}
+	for _, id := range ackIDs {
+		result[id] = false
+		if ch == nil {
+			continue
+		}
+		if ackElem, ok := ch.acks[id]; ok && ackElem.Value.(*ackState).acked {
+			result[id] = true
+			ch.order.Remove(ackElem)
+			delete(ch.acks, id)
+		}
+	}
+	return result
+}
+
+// channel returns the state of the channel, creating it if needed. It must
+// be called with the lock held.
+func (am *ackManager) channel(channelID string) *ackChannel {
+	if elem, ok := am.channels[channelID]; ok {
+		am.lru.MoveToFront(elem)
+		return elem.Value.(*ackChannel)
+	}
+
+	ch := &ackChannel{
+		id:    channelID,
+		acks:  make(map[uint64]*list.Element),
+		order: list.New(),
+	}
+	am.channels[channelID] = am.lru.PushFront(ch)
+
+	for am.lru.Len() > am.maxChannels {
+		oldest := am.lru.Remove(am.lru.Back()).(*ackChannel)
+		delete(am.channels, oldest.id)
+	}
+	return ch
+}
@@ -0,0 +1,80 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAckManager(t *testing.T) {
+	am := newAckManager(10, 10)
+
+	id0 := am.newAckID("ch")
+	id1 := am.newAckID("ch")
+	id2 := am.newAckID("ch")
+	assert.Equal(t, []uint64{0, 1, 2}, []uint64{id0, id1, id2})
+	// The IDs are unique across the channels.
+	assert.Equal(t, uint64(3), am.newAckID("other"))
+
+	am.complete("ch", id0, true)
+	am.complete("ch", id1, false)
+
+	assert.Equal(t, map[uint64]bool{0: true, 1: false, 2: false}, am.query("ch", []uint64{0, 1, 2}))
+	// Acknowledged IDs are reported only once.
+	assert.Equal(t, map[uint64]bool{0: false}, am.query("ch", []uint64{0}))
+
+	am.complete("ch", id2, true)
+	assert.Equal(t, map[uint64]bool{2: true}, am.query("ch", []uint64{2}))
+	assert.Equal(t, map[uint64]bool{2: false}, am.query("unknown", []uint64{2}))
+}
+
+func TestAckManagerMaxAcksPerChannel(t *testing.T) {
+	am := newAckManager(10, 2)
+
+	for i := 0; i < 3; i++ {
+		am.complete("ch", am.newAckID("ch"), true)
+	}
+	assert.Equal(t, map[uint64]bool{0: false, 1: true, 2: true}, am.query("ch", []uint64{0, 1, 2}))
+}
+
+func TestAckManagerMaxChannels(t *testing.T) {
+	am := newAckManager(2, 10)
+
+	am.complete("a", am.newAckID("a"), true)
+	am.complete("b", am.newAckID("b"), true)
+	// Using "a" makes "b" the least recently used channel.
+	am.complete("a", am.newAckID("a"), true)
+	am.complete("c", am.newAckID("c"), true)
+
+	assert.Equal(t, map[uint64]bool{0: true, 2: true}, am.query("a", []uint64{0, 2}))
+	assert.Equal(t, map[uint64]bool{1: false}, am.query("b", []uint64{1}))
+	assert.Equal(t, map[uint64]bool{3: true}, am.query("c", []uint64{3}))
+}
+
+func TestAckManagerEvictedChannel(t *testing.T) {
+	am := newAckManager(1, 10)
+
+	old := am.newAckID("a")
+	// "a" is dropped, the client still waiting for its ID.
+	am.complete("b", am.newAckID("b"), true)
+	// "a" is used again, for a request which is acknowledged.
+	id := am.newAckID("a")
+	am.complete("a", id, true)
+
+	assert.NotEqual(t, old, id)
+	assert.Equal(t, map[uint64]bool{old: false, id: true}, am.query("a", []uint64{old, id}))
+}
@@ -35,6 +35,29 @@ type Config struct {
 	// Path we will listen on, defaults to `*` (anything matches)
 	Path     string `mapstructure:"path"`
 	pathGlob glob.Glob
+	// RawPath is the path of the raw endpoint, accepting newline-delimited
+	// text as log records, defaults to `/services/collector/raw`. An empty
+	// value disables the raw endpoint.
+	RawPath string `mapstructure:"raw_path"`
+	// Ack configures the HEC indexer acknowledgement.
+	Ack AckConfig `mapstructure:"ack"`
+}
+
+// AckConfig defines configuration for the HEC indexer acknowledgement, see
+// https://docs.splunk.com/Documentation/Splunk/8.0.5/Data/AboutHECIDXAck.
+type AckConfig struct {
+	// Enabled turns on the acknowledgement: requests must then identify their
+	// channel and receive an ack ID that can be queried on Path.
+	Enabled bool `mapstructure:"enabled"`
+	// Path of the endpoint serving the acknowledgement queries, defaults to
+	// `/services/collector/ack`.
+	Path string `mapstructure:"path"`
+	// MaxChannels is the maximum number of channels tracked, when exceeded
+	// the least recently used channel is dropped.
+	MaxChannels int `mapstructure:"max_channels"`
+	// MaxAcksPerChannel is the maximum number of ack IDs tracked per channel,
+	// when exceeded the oldest ack ID is dropped.
+	MaxAcksPerChannel int `mapstructure:"max_acks_per_channel"`
 }
 
 // initialize and initialize the configuration
@@ -48,6 +71,14 @@ func (c *Config) initialize() error {
 		return err
 	}
 	c.pathGlob = glob
+	if c.Ack.Enabled {
+		if c.Ack.MaxChannels <= 0 {
+			return fmt.Errorf("ack max_channels must be positive, got %d", c.Ack.MaxChannels)
+		}
+		if c.Ack.MaxAcksPerChannel <= 0 {
+			return fmt.Errorf("ack max_acks_per_channel must be positive, got %d", c.Ack.MaxAcksPerChannel)
+		}
+	}
 	_, err = extractPortFromEndpoint(c.Endpoint)
 	return err
 }
@@ -114,7 +114,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 4)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, r0, createDefaultConfig())
@@ -129,7 +129,13 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: true,
 			},
-			Path: "/foo",
+			Path:    "/foo",
+			RawPath: "/foo/raw",
+			Ack: AckConfig{
+				Path:              defaultAckPath,
+				MaxChannels:       defaultAckMaxChannels,
+				MaxAcksPerChannel: defaultAckMaxAcksPerChannel,
+			},
 		})
 
 	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "tls")].(*Config)
@@ -148,6 +154,33 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: false,
 			},
-			Path: "",
+			Path:    "",
+			RawPath: defaultRawPath,
+			Ack: AckConfig{
+				Path:              defaultAckPath,
+				MaxChannels:       defaultAckMaxChannels,
+				MaxAcksPerChannel: defaultAckMaxAcksPerChannel,
+			},
 		})
+
+	r3 := cfg.Receivers[config.NewIDWithName(typeStr, "ack")].(*Config)
+	assert.Equal(t,
+		AckConfig{
+			Enabled:           true,
+			Path:              "/ack",
+			MaxChannels:       10,
+			MaxAcksPerChannel: 100,
+		}, r3.Ack)
+}
+
+func TestInvalidAckConfig(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Ack.Enabled = true
+	c.Ack.MaxChannels = 0
+	assert.EqualError(t, c.initialize(), "ack max_channels must be positive, got 0")
+
+	c = createDefaultConfig().(*Config)
+	c.Ack.Enabled = true
+	c.Ack.MaxAcksPerChannel = -1
+	assert.EqualError(t, c.initialize(), "ack max_acks_per_channel must be positive, got -1")
 }
@@ -34,6 +34,14 @@ const (
 
 	// Default endpoints to bind to.
 	defaultEndpoint = ":8088"
+
+	// Default path of the raw endpoint.
+	defaultRawPath = "/services/collector/raw"
+
+	// Defaults of the HEC indexer acknowledgement.
+	defaultAckPath              = "/services/collector/ack"
+	defaultAckMaxChannels       = 1000
+	defaultAckMaxAcksPerChannel = 1000
 )
 
 // NewFactory creates a factory for SignalFx receiver.
@@ -54,6 +62,12 @@ func createDefaultConfig() config.Receiver {
 		},
 		AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{},
 		Path:                         "",
+		RawPath:                      defaultRawPath,
+		Ack: AckConfig{
+			Path:              defaultAckPath,
+			MaxChannels:       defaultAckMaxChannels,
+			MaxAcksPerChannel: defaultAckMaxAcksPerChannel,
+		},
 	}
 }
 
@@ -15,13 +15,17 @@
 package splunkhecreceiver
 
 import (
+	"bufio"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -49,10 +53,20 @@ const (
 	responseErrInternalServerError    = "Internal Server Error"
 	responseErrUnsupportedMetricEvent = "Unsupported metric event"
 	responseErrUnsupportedLogEvent    = "Unsupported log event"
+	responseErrDataChannelMissing     = "Data channel is missing"
+	responseSuccess                   = "Success"
 
 	// Centralizing some HTTP and related string constants.
 	gzipEncoding              = "gzip"
 	httpContentEncodingHeader = "Content-Encoding"
+	splunkChannelHeader       = "X-Splunk-Request-Channel"
+	splunkChannelQueryParam   = "channel"
+
+	// Query parameters holding the metadata of the raw endpoint events.
+	queryParamHost       = "host"
+	queryParamSource     = "source"
+	queryParamSourceType = "sourcetype"
+	queryParamIndex      = "index"
 )
 
 var (
@@ -69,8 +83,26 @@ var (
 	errInternalServerError    = initJSONResponse(responseErrInternalServerError)
 	errUnsupportedMetricEvent = initJSONResponse(responseErrUnsupportedMetricEvent)
 	errUnsupportedLogEvent    = initJSONResponse(responseErrUnsupportedLogEvent)
+	errDataChannelMissing     = initJSONResponse(responseErrDataChannelMissing)
 )
 
+// ackIDResponse is the response to a request accepted with indexer
+// acknowledgement enabled.
+type ackIDResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID uint64 `json:"ackId"`
+}
+
+// ackRequest and ackResponse are the bodies of the ack endpoint.
+type ackRequest struct {
+	Acks []uint64 `json:"acks"`
+}
+
+type ackResponse struct {
+	Acks map[uint64]bool `json:"acks"`
+}
+
 // splunkReceiver implements the component.MetricsReceiver for Splunk HEC metric protocol.
 type splunkReceiver struct {
 	sync.Mutex
@@ -79,6 +111,8 @@ type splunkReceiver struct {
 	logsConsumer    consumer.Logs
 	metricsConsumer consumer.Metrics
 	server          *http.Server
+	// acks is nil unless the indexer acknowledgement is enabled.
+	acks *ackManager
 }
 
 var _ component.MetricsReceiver = (*splunkReceiver)(nil)
@@ -109,6 +143,9 @@ func NewMetricsReceiver(
 			WriteTimeout:      defaultServerTimeout,
 		},
 	}
+	if config.Ack.Enabled {
+		r.acks = newAckManager(config.Ack.MaxChannels, config.Ack.MaxAcksPerChannel)
+	}
 
 	return r, nil
 }
@@ -139,6 +176,9 @@ func NewLogsReceiver(
 			WriteTimeout:      defaultServerTimeout,
 		},
 	}
+	if config.Ack.Enabled {
+		r.acks = newAckManager(config.Ack.MaxChannels, config.Ack.MaxAcksPerChannel)
+	}
 
 	return r, nil
 }
@@ -188,6 +228,10 @@ func (r *splunkReceiver) Shutdown(context.Context) error {
 }
 
 func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
+	if r.acks != nil && req.URL.Path == r.config.Ack.Path {
+		r.handleAck(resp, req)
+		return
+	}
 
 	transport := "http"
 	if r.config.TLSSetting != nil {
@@ -199,7 +243,8 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.ID(), transport)
 	}
 	reqPath := req.URL.Path
-	if !r.config.pathGlob.Match(reqPath) {
+	isRaw := r.config.RawPath != "" && reqPath == r.config.RawPath
+	if !isRaw && !r.config.pathGlob.Match(reqPath) {
 		r.failRequest(ctx, resp, http.StatusNotFound, notFoundRespBody, nil)
 		return
 	}
@@ -225,11 +270,31 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		}
 	}
 
+	channelID := requestChannel(req)
+	if r.acks != nil && channelID == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errDataChannelMissing, nil)
+		return
+	}
+
 	if req.ContentLength == 0 {
 		resp.Write(okRespBody)
 		return
 	}
 
+	if isRaw {
+		if r.logsConsumer == nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnsupportedLogEvent, nil)
+			return
+		}
+		events, err := splitRawEvents(bodyReader, req.URL.Query())
+		if err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+			return
+		}
+		r.consumeLogs(ctx, events, channelID, resp, req)
+		return
+	}
+
 	dec := json.NewDecoder(bodyReader)
 
 	var events []*splunk.Event
@@ -254,12 +319,75 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		events = append(events, &msg)
 	}
 	if r.logsConsumer != nil {
-		r.consumeLogs(ctx, events, resp, req)
+		r.consumeLogs(ctx, events, channelID, resp, req)
 	} else {
-		r.consumeMetrics(ctx, events, resp, req)
+		r.consumeMetrics(ctx, events, channelID, resp, req)
 	}
 }
 
+// handleAck serves the queries of the ack IDs acknowledged on a channel.
+func (r *splunkReceiver) handleAck(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return
+	}
+
+	channelID := requestChannel(req)
+	if channelID == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errDataChannelMissing, nil)
+		return
+	}
+
+	var ackReq ackRequest
+	if err := json.NewDecoder(req.Body).Decode(&ackReq); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	respBody, err := json.Marshal(ackResponse{Acks: r.acks.query(channelID, ackReq.Acks)})
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, err)
+		return
+	}
+	resp.WriteHeader(http.StatusOK)
+	resp.Write(respBody)
+}
+
+// splitRawEvents splits the body of a raw endpoint request into one event per
+// line, the event metadata is taken from the query parameters.
+func splitRawEvents(body io.Reader, query url.Values) ([]*splunk.Event, error) {
+	var events []*splunk.Event
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			events = append(events, &splunk.Event{
+				Host:       query.Get(queryParamHost),
+				Source:     query.Get(queryParamSource),
+				SourceType: query.Get(queryParamSourceType),
+				Index:      query.Get(queryParamIndex),
+				Event:      line,
+			})
+		}
+		if err == io.EOF {
+			return events, nil
+		}
+	}
+}
+
+// requestChannel returns the HEC channel of the request, which clients
+// set either as a header or as a query parameter.
+func requestChannel(req *http.Request) string {
+	if channelID := req.Header.Get(splunkChannelHeader); channelID != "" {
+		return channelID
+	}
+	return req.URL.Query().Get(splunkChannelQueryParam)
+}
+
 func (r *splunkReceiver) createResourceCustomizer(req *http.Request) func(pdata.Resource) {
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.HECTokenHeader); accessToken != "" {
@@ -271,35 +399,68 @@ func (r *splunkReceiver) createResourceCustomizer(req *http.Request) func(pdata.
 	return func(resource pdata.Resource) {}
 }
 
-func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
+func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Event, channelID string, resp http.ResponseWriter, req *http.Request) {
 	md, _ := SplunkHecToMetricsData(r.logger, events, r.createResourceCustomizer(req))
 
+	ackID := r.startAck(channelID)
 	decodeErr := r.metricsConsumer.ConsumeMetrics(ctx, md)
 	obsreport.EndMetricsReceiveOp(ctx, typeStr, len(events), decodeErr)
+	r.endAck(channelID, ackID, decodeErr)
 
 	if decodeErr != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, decodeErr)
 	} else {
-		resp.WriteHeader(http.StatusAccepted)
-		resp.Write(okRespBody)
+		r.writeSuccess(resp, ackID)
 	}
 }
 
-func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
+func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event, channelID string, resp http.ResponseWriter, req *http.Request) {
 	ld, err := SplunkHecToLogData(r.logger, events, r.createResourceCustomizer(req))
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
+	ackID := r.startAck(channelID)
 	decodeErr := r.logsConsumer.ConsumeLogs(ctx, ld)
+	r.endAck(channelID, ackID, decodeErr)
 
 	if decodeErr != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, decodeErr)
 	} else {
+		r.writeSuccess(resp, ackID)
+	}
+}
+
+// startAck assigns an ack ID to the request if the indexer acknowledgement
+// is enabled, it must be followed by endAck once the data was consumed.
+func (r *splunkReceiver) startAck(channelID string) uint64 {
+	if r.acks == nil {
+		return 0
+	}
+	return r.acks.newAckID(channelID)
+}
+
+func (r *splunkReceiver) endAck(channelID string, ackID uint64, err error) {
+	if r.acks == nil {
+		return
+	}
+	r.acks.complete(channelID, ackID, err == nil)
+}
+
+func (r *splunkReceiver) writeSuccess(resp http.ResponseWriter, ackID uint64) {
+	if r.acks == nil {
 		resp.WriteHeader(http.StatusAccepted)
 		resp.Write(okRespBody)
+		return
+	}
+
+	respBody, err := json.Marshal(ackIDResponse{Text: responseSuccess, AckID: ackID})
+	if err != nil {
+		r.logger.Warn("Error marshaling ack ID response", zap.Error(err))
 	}
+	resp.WriteHeader(http.StatusAccepted)
+	resp.Write(respBody)
 }
 
 func (r *splunkReceiver) failRequest(
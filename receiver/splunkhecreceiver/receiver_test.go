@@ -39,6 +39,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter"
@@ -384,6 +385,166 @@ func Test_consumer_err_metrics(t *testing.T) {
 	assert.Equal(t, "Internal Server Error", bodyStr)
 }
 
+func Test_splunkhecReceiver_Raw(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Path = "/foo"
+	require.NoError(t, config.initialize())
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewLogsReceiver(zap.NewNop(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	body := "first line\r\nsecond line\n\nthird line"
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST",
+		"http://localhost/services/collector/raw?host=myhost&source=mysource&sourcetype=mysourcetype&index=myindex",
+		bytes.NewReader([]byte(body))))
+	resp := w.Result()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var bodyStr string
+	require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, responseOK, bodyStr)
+
+	require.Len(t, sink.AllLogs(), 1)
+	logs := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 3, logs.Len())
+	for i, want := range []string{"first line", "second line", "third line"} {
+		lr := logs.At(i)
+		assert.Equal(t, want, lr.Body().StringVal())
+		assert.Equal(t, "mysourcetype", lr.Name())
+		attrs := lr.Attributes()
+		for k, v := range map[string]string{
+			conventions.AttributeHostName:    "myhost",
+			conventions.AttributeServiceName: "mysource",
+			splunk.SourcetypeLabel:           "mysourcetype",
+			splunk.IndexLabel:                "myindex",
+		} {
+			attr, ok := attrs.Get(k)
+			require.True(t, ok, k)
+			assert.Equal(t, v, attr.StringVal(), k)
+		}
+	}
+}
+
+func Test_splunkhecReceiver_Raw_metrics(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	require.NoError(t, config.initialize())
+	rcv, err := NewMetricsReceiver(zap.NewNop(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", bytes.NewReader([]byte("line"))))
+	resp := w.Result()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var bodyStr string
+	require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, responseErrUnsupportedLogEvent, bodyStr)
+}
+
+func Test_splunkhecReceiver_Ack(t *testing.T) {
+	currentTime := float64(time.Now().UnixNano()) / 1e6
+	msgBytes, err := json.Marshal(buildSplunkHecMsg(currentTime, 3))
+	require.NoError(t, err)
+
+	config := createDefaultConfig().(*Config)
+	config.Ack.Enabled = true
+	require.NoError(t, config.initialize())
+	sink := new(consumertest.LogsSink)
+	rcv, err := NewLogsReceiver(zap.NewNop(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	// Requests without a channel are rejected.
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", bytes.NewReader(msgBytes)))
+	resp := w.Result()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var bodyStr string
+	require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, responseErrDataChannelMissing, bodyStr)
+	assert.Equal(t, 0, sink.LogRecordsCount())
+
+	// The channel can be set either as header or as query parameter.
+	var ackIDs []uint64
+	for _, req := range []*http.Request{
+		func() *http.Request {
+			req := httptest.NewRequest("POST", "http://localhost/services/collector", bytes.NewReader(msgBytes))
+			req.Header.Set(splunkChannelHeader, "ch1")
+			return req
+		}(),
+		httptest.NewRequest("POST", "http://localhost/services/collector?channel=ch1", bytes.NewReader(msgBytes)),
+	} {
+		w = httptest.NewRecorder()
+		r.handleReq(w, req)
+		resp = w.Result()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		var ackResp ackIDResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ackResp))
+		assert.Equal(t, responseSuccess, ackResp.Text)
+		ackIDs = append(ackIDs, ackResp.AckID)
+	}
+	assert.Equal(t, []uint64{0, 1}, ackIDs)
+	assert.Equal(t, 2, sink.LogRecordsCount())
+
+	query := func(channelID string, body string) (int, string) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "http://localhost/services/collector/ack", bytes.NewReader([]byte(body)))
+		if channelID != "" {
+			req.Header.Set(splunkChannelHeader, channelID)
+		}
+		r.handleReq(w, req)
+		resp := w.Result()
+		respBytes, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBytes)
+	}
+
+	status, body := query("ch1", `{"acks":[0,1,2]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"acks":{"0":true,"1":true,"2":false}}`, body)
+
+	status, body = query("ch1", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"acks":{"0":false}}`, body)
+
+	status, body = query("", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `"Data channel is missing"`, body)
+
+	status, body = query("ch1", `{"acks":`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `"Failed to unmarshal message body"`, body)
+}
+
+func Test_splunkhecReceiver_Ack_consumer_err(t *testing.T) {
+	currentTime := float64(time.Now().UnixNano()) / 1e6
+	msgBytes, err := json.Marshal(buildSplunkHecMsg(currentTime, 3))
+	require.NoError(t, err)
+
+	config := createDefaultConfig().(*Config)
+	config.Ack.Enabled = true
+	require.NoError(t, config.initialize())
+	rcv, err := NewLogsReceiver(zap.NewNop(), *config, consumertest.NewErr(errors.New("bad consumer")))
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "http://localhost/services/collector", bytes.NewReader(msgBytes))
+	req.Header.Set(splunkChannelHeader, "ch1")
+	r.handleReq(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+
+	// The failed request is never acknowledged.
+	assert.Equal(t, map[uint64]bool{0: false}, r.acks.query("ch1", []uint64{0}))
+}
+
 func Test_splunkhecReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := createDefaultConfig().(*Config)
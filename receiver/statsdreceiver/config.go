@@ -32,6 +32,20 @@ type Config struct {
 	AggregationInterval     time.Duration                    `mapstructure:"aggregation_interval"`
 	EnableMetricType        bool                             `mapstructure:"enable_metric_type"`
 	TimerHistogramMapping   []protocol.TimerHistogramMapping `mapstructure:"timer_histogram_mapping"`
+	// AggregateBySourceAddress aggregates metrics separately for each client
+	// IP address and records the address as resource attribute.
+	AggregateBySourceAddress bool `mapstructure:"aggregate_by_source_address"`
+	// MaxSeriesPerInterval bounds the number of distinct series aggregated per
+	// interval (per client when AggregateBySourceAddress is set). Additional
+	// series are dropped and counted. Zero means unlimited.
+	MaxSeriesPerInterval int `mapstructure:"max_series_per_interval"`
+	// MaxTotalSeriesPerInterval bounds the number of distinct series
+	// aggregated per interval across all clients. Zero means unlimited.
+	MaxTotalSeriesPerInterval int `mapstructure:"max_total_series_per_interval"`
+	// MaxClientsPerInterval bounds the number of clients aggregated per
+	// interval when AggregateBySourceAddress is set. The lines of additional
+	// clients are dropped and counted. Zero means unlimited.
+	MaxClientsPerInterval int `mapstructure:"max_clients_per_interval"`
 }
 
 func (c *Config) validate() error {
@@ -44,6 +58,18 @@ func (c *Config) validate() error {
 		errors = append(errors, fmt.Errorf("aggregation_interval must be a positive duration"))
 	}
 
+	if c.MaxSeriesPerInterval < 0 {
+		errors = append(errors, fmt.Errorf("max_series_per_interval must not be negative"))
+	}
+
+	if c.MaxTotalSeriesPerInterval < 0 {
+		errors = append(errors, fmt.Errorf("max_total_series_per_interval must not be negative"))
+	}
+
+	if c.MaxClientsPerInterval < 0 {
+		errors = append(errors, fmt.Errorf("max_clients_per_interval must not be negative"))
+	}
+
 	var TimerHistogramMappingMissingObjectName bool
 	for _, eachMap := range c.TimerHistogramMapping {
 
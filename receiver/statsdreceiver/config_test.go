@@ -55,8 +55,12 @@ func TestLoadConfig(t *testing.T) {
 			Endpoint:  "localhost:12345",
 			Transport: "custom_transport",
 		},
-		AggregationInterval:   70 * time.Second,
-		TimerHistogramMapping: []protocol.TimerHistogramMapping{{StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "timing", ObserverType: "gauge"}},
+		AggregationInterval:       70 * time.Second,
+		TimerHistogramMapping:     []protocol.TimerHistogramMapping{{StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "timing", ObserverType: "gauge"}},
+		AggregateBySourceAddress:  true,
+		MaxSeriesPerInterval:      10000,
+		MaxTotalSeriesPerInterval: 100000,
+		MaxClientsPerInterval:     100,
 	}, r1)
 }
 
@@ -69,6 +73,9 @@ func TestValidate(t *testing.T) {
 
 	const (
 		negativeAggregationIntervalErr = "aggregation_interval must be a positive duration"
+		negativeMaxSeriesErr           = "max_series_per_interval must not be negative"
+		negativeMaxTotalSeriesErr      = "max_total_series_per_interval must not be negative"
+		negativeMaxClientsErr          = "max_clients_per_interval must not be negative"
 		noObjectNameErr                = "must specify object id for all TimerHistogramMappings"
 		statsdTypeNotSupportErr        = "statsd_type is not supported: %s"
 		observerTypeNotSupportErr      = "observer_type is not supported: %s"
@@ -85,6 +92,39 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: negativeAggregationIntervalErr,
 		},
+		{
+			name: "negativeMaxSeriesPerInterval",
+			cfg: &Config{
+				AggregationInterval:  10,
+				MaxSeriesPerInterval: -1,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timing", ObserverType: "gauge"},
+				},
+			},
+			expectedErr: negativeMaxSeriesErr,
+		},
+		{
+			name: "negativeMaxTotalSeriesPerInterval",
+			cfg: &Config{
+				AggregationInterval:       10,
+				MaxTotalSeriesPerInterval: -1,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timing", ObserverType: "gauge"},
+				},
+			},
+			expectedErr: negativeMaxTotalSeriesErr,
+		},
+		{
+			name: "negativeMaxClientsPerInterval",
+			cfg: &Config{
+				AggregationInterval:   10,
+				MaxClientsPerInterval: -1,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timing", ObserverType: "gauge"},
+				},
+			},
+			expectedErr: negativeMaxClientsErr,
+		},
 		{
 			name: "emptyStatsdType",
 			cfg: &Config{
@@ -42,6 +42,42 @@ func buildCounterMetric(parsedMetric statsDMetric, timeNow time.Time) pdata.Inst
 	return ilm
 }
 
+// buildDroppedSeriesMetric reports how many series were rejected because the
+// max_series_per_interval limit had been reached.
+func buildDroppedSeriesMetric(dropped int64, timeNow time.Time) pdata.InstrumentationLibraryMetrics {
+	ilm := pdata.NewInstrumentationLibraryMetrics()
+	nm := ilm.Metrics().AppendEmpty()
+	nm.SetName(droppedSeriesMetricName)
+	nm.SetDescription("Number of series dropped because the max series per aggregation interval was exceeded")
+	nm.SetDataType(pdata.MetricDataTypeIntSum)
+	nm.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	nm.IntSum().SetIsMonotonic(true)
+
+	dp := nm.IntSum().DataPoints().AppendEmpty()
+	dp.SetValue(dropped)
+	dp.SetTimestamp(pdata.TimestampFromTime(timeNow))
+
+	return ilm
+}
+
+// buildDroppedClientLinesMetric reports how many lines were rejected because
+// they were sent by clients beyond the max_clients_per_interval limit.
+func buildDroppedClientLinesMetric(dropped int64, timeNow time.Time) pdata.InstrumentationLibraryMetrics {
+	ilm := pdata.NewInstrumentationLibraryMetrics()
+	nm := ilm.Metrics().AppendEmpty()
+	nm.SetName(droppedClientLinesMetricName)
+	nm.SetDescription("Number of lines dropped because the max clients per aggregation interval was exceeded")
+	nm.SetDataType(pdata.MetricDataTypeIntSum)
+	nm.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	nm.IntSum().SetIsMonotonic(true)
+
+	dp := nm.IntSum().DataPoints().AppendEmpty()
+	dp.SetValue(dropped)
+	dp.SetTimestamp(pdata.TimestampFromTime(timeNow))
+
+	return ilm
+}
+
 func buildGaugeMetric(parsedMetric statsDMetric, timeNow time.Time) pdata.InstrumentationLibraryMetrics {
 	ilm := pdata.NewInstrumentationLibraryMetrics()
 	nm := ilm.Metrics().AppendEmpty()
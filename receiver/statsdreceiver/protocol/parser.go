@@ -15,12 +15,14 @@
 package protocol
 
 import (
+	"net"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 // Parser is something that can map input StatsD strings to OTLP Metric representations.
 type Parser interface {
-	Initialize(enableMetricType bool, sendTimerHistogram []TimerHistogramMapping) error
+	Initialize(enableMetricType bool, sendTimerHistogram []TimerHistogramMapping, aggregateBySourceAddr bool, maxSeriesPerInterval int, maxTotalSeriesPerInterval int, maxClientsPerInterval int) error
 	GetMetrics() pdata.Metrics
-	Aggregate(line string) error
+	Aggregate(line string, addr net.Addr) error
 }
@@ -17,11 +17,13 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -40,6 +42,9 @@ const (
 	statsdGauge     = "g"
 	statsdHistogram = "h"
 	statsdTiming    = "ms"
+
+	droppedSeriesMetricName      = "statsd.receiver.dropped_series"
+	droppedClientLinesMetricName = "statsd.receiver.dropped_client_lines"
 )
 
 type TimerHistogramMapping struct {
@@ -49,13 +54,77 @@ type TimerHistogramMapping struct {
 
 // StatsDParser supports the Parse method for parsing StatsD messages with Tags.
 type StatsDParser struct {
+	instrumentsByAddress      map[netAddr]*instruments
+	enableMetricType          bool
+	aggregateBySourceAddr     bool
+	maxSeriesPerInterval      int
+	maxTotalSeriesPerInterval int
+	maxClientsPerInterval     int
+	observeTimer              string
+	observeHistogram          string
+	// totalSeries is the number of series aggregated in this interval across
+	// all clients, and droppedClientLines the number of lines dropped because
+	// they were sent by clients beyond maxClientsPerInterval.
+	totalSeries        int
+	droppedClientLines int64
+}
+
+// instruments holds the state aggregated for a single source during one
+// aggregation interval.
+type instruments struct {
+	addr                   netAddr
 	gauges                 map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics
 	counters               map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics
 	summaries              map[statsDMetricdescription]summaryMetric
 	timersAndDistributions []pdata.InstrumentationLibraryMetrics
-	enableMetricType       bool
-	observeTimer           string
-	observeHistogram       string
+	series                 map[statsDMetricdescription]struct{}
+	droppedSeries          int64
+}
+
+// netAddr identifies a client by the network and IP of its address, used as
+// map key. The port is left out since clients may use a new ephemeral port
+// for each packet.
+type netAddr struct {
+	Network string
+	IP      string
+}
+
+func newNetAddr(addr net.Addr) netAddr {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return netAddr{addr.Network(), host}
+}
+
+func newInstruments(addr netAddr) *instruments {
+	return &instruments{
+		addr:                   addr,
+		gauges:                 make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics),
+		counters:               make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics),
+		summaries:              make(map[statsDMetricdescription]summaryMetric),
+		timersAndDistributions: make([]pdata.InstrumentationLibraryMetrics, 0),
+		series:                 make(map[statsDMetricdescription]struct{}),
+	}
+}
+
+// admit records the series described by desc for the instrument and reports
+// whether it may be aggregated. Series already seen in this interval are
+// always admitted, new ones only while neither the limit of the instrument
+// nor the limit across all of the instruments has been reached. A limit <= 0
+// disables the check.
+func (p *StatsDParser) admit(i *instruments, desc statsDMetricdescription) bool {
+	if _, ok := i.series[desc]; ok {
+		return true
+	}
+	if (p.maxSeriesPerInterval > 0 && len(i.series) >= p.maxSeriesPerInterval) ||
+		(p.maxTotalSeriesPerInterval > 0 && p.totalSeries >= p.maxTotalSeriesPerInterval) {
+		i.droppedSeries++
+		return false
+	}
+	i.series[desc] = struct{}{}
+	p.totalSeries++
+	return true
 }
 
 type summaryMetric struct {
@@ -84,13 +153,16 @@ type statsDMetricdescription struct {
 	labels           attribute.Distinct
 }
 
-func (p *StatsDParser) Initialize(enableMetricType bool, sendTimerHistogram []TimerHistogramMapping) error {
-	p.gauges = make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics)
-	p.counters = make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics)
-	p.timersAndDistributions = make([]pdata.InstrumentationLibraryMetrics, 0)
-	p.summaries = make(map[statsDMetricdescription]summaryMetric)
+func (p *StatsDParser) Initialize(enableMetricType bool, sendTimerHistogram []TimerHistogramMapping, aggregateBySourceAddr bool, maxSeriesPerInterval int, maxTotalSeriesPerInterval int, maxClientsPerInterval int) error {
+	p.instrumentsByAddress = make(map[netAddr]*instruments)
+	p.totalSeries = 0
+	p.droppedClientLines = 0
 
 	p.enableMetricType = enableMetricType
+	p.aggregateBySourceAddr = aggregateBySourceAddr
+	p.maxSeriesPerInterval = maxSeriesPerInterval
+	p.maxTotalSeriesPerInterval = maxTotalSeriesPerInterval
+	p.maxClientsPerInterval = maxClientsPerInterval
 	for _, eachMap := range sendTimerHistogram {
 		switch eachMap.StatsdType {
 		case "histogram":
@@ -105,28 +177,43 @@ func (p *StatsDParser) Initialize(enableMetricType bool, sendTimerHistogram []Ti
 // get the metrics preparing for flushing and reset the state
 func (p *StatsDParser) GetMetrics() pdata.Metrics {
 	metrics := pdata.NewMetrics()
-	rm := metrics.ResourceMetrics().AppendEmpty()
 
-	for _, metric := range p.gauges {
-		rm.InstrumentationLibraryMetrics().Append(metric)
-	}
+	for _, instrument := range p.instrumentsByAddress {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		if instrument.addr.IP != "" {
+			rm.Resource().Attributes().InsertString(conventions.AttributeNetPeerIP, instrument.addr.IP)
+		}
 
-	for _, metric := range p.counters {
-		rm.InstrumentationLibraryMetrics().Append(metric)
-	}
+		for _, metric := range instrument.gauges {
+			rm.InstrumentationLibraryMetrics().Append(metric)
+		}
+
+		for _, metric := range instrument.counters {
+			rm.InstrumentationLibraryMetrics().Append(metric)
+		}
+
+		for _, metric := range instrument.timersAndDistributions {
+			rm.InstrumentationLibraryMetrics().Append(metric)
+		}
+
+		for _, summaryMetric := range instrument.summaries {
+			rm.InstrumentationLibraryMetrics().Append(buildSummaryMetric(summaryMetric))
+		}
 
-	for _, metric := range p.timersAndDistributions {
-		rm.InstrumentationLibraryMetrics().Append(metric)
+		if instrument.droppedSeries > 0 {
+			rm.InstrumentationLibraryMetrics().Append(buildDroppedSeriesMetric(instrument.droppedSeries, timeNowFunc()))
+		}
 	}
 
-	for _, summaryMetric := range p.summaries {
-		metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Append(buildSummaryMetric(summaryMetric))
+	// the lines of the clients beyond the limit aren't attributed to any client
+	if p.droppedClientLines > 0 {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		rm.InstrumentationLibraryMetrics().Append(buildDroppedClientLinesMetric(p.droppedClientLines, timeNowFunc()))
 	}
 
-	p.gauges = make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics)
-	p.counters = make(map[statsDMetricdescription]pdata.InstrumentationLibraryMetrics)
-	p.timersAndDistributions = make([]pdata.InstrumentationLibraryMetrics, 0)
-	p.summaries = make(map[statsDMetricdescription]summaryMetric)
+	p.instrumentsByAddress = make(map[netAddr]*instruments)
+	p.totalSeries = 0
+	p.droppedClientLines = 0
 	return metrics
 }
 
@@ -135,92 +222,93 @@ var timeNowFunc = func() time.Time {
 }
 
 //aggregate for each metric line
-func (p *StatsDParser) Aggregate(line string) error {
+func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 	parsedMetric, err := parseMessageToMetric(line, p.enableMetricType)
 	if err != nil {
 		return err
 	}
+
+	key := netAddr{}
+	if p.aggregateBySourceAddr && addr != nil {
+		key = newNetAddr(addr)
+	}
+	instrument, ok := p.instrumentsByAddress[key]
+	if !ok {
+		if p.maxClientsPerInterval > 0 && len(p.instrumentsByAddress) >= p.maxClientsPerInterval {
+			p.droppedClientLines++
+			return nil
+		}
+		instrument = newInstruments(key)
+		p.instrumentsByAddress[key] = instrument
+	}
+
+	if !p.admit(instrument, parsedMetric.description) {
+		return nil
+	}
+
 	switch parsedMetric.description.statsdMetricType {
 	case statsdGauge:
-		_, ok := p.gauges[parsedMetric.description]
+		_, ok := instrument.gauges[parsedMetric.description]
 		if !ok {
-			p.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
+			instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
 		} else {
 			if parsedMetric.addition {
-				savedValue := p.gauges[parsedMetric.description].Metrics().At(0).DoubleGauge().DataPoints().At(0).Value()
+				savedValue := instrument.gauges[parsedMetric.description].Metrics().At(0).DoubleGauge().DataPoints().At(0).Value()
 				parsedMetric.floatvalue = parsedMetric.floatvalue + savedValue
-				p.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
+				instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
 			} else {
-				p.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
+				instrument.gauges[parsedMetric.description] = buildGaugeMetric(parsedMetric, timeNowFunc())
 			}
 		}
 
 	case statsdCounter:
-		_, ok := p.counters[parsedMetric.description]
+		_, ok := instrument.counters[parsedMetric.description]
 		if !ok {
-			p.counters[parsedMetric.description] = buildCounterMetric(parsedMetric, timeNowFunc())
+			instrument.counters[parsedMetric.description] = buildCounterMetric(parsedMetric, timeNowFunc())
 		} else {
-			savedValue := p.counters[parsedMetric.description].Metrics().At(0).IntSum().DataPoints().At(0).Value()
+			savedValue := instrument.counters[parsedMetric.description].Metrics().At(0).IntSum().DataPoints().At(0).Value()
 			parsedMetric.intvalue = parsedMetric.intvalue + savedValue
-			p.counters[parsedMetric.description] = buildCounterMetric(parsedMetric, timeNowFunc())
+			instrument.counters[parsedMetric.description] = buildCounterMetric(parsedMetric, timeNowFunc())
 		}
 
 	case statsdHistogram:
-		switch p.observeHistogram {
-		case "gauge":
-			p.timersAndDistributions = append(p.timersAndDistributions, buildGaugeMetric(parsedMetric, timeNowFunc()))
-		case "summary":
-			eachSummaryMetric, ok := p.summaries[parsedMetric.description]
-			if !ok {
-				p.summaries[parsedMetric.description] = summaryMetric{
-					name:          parsedMetric.description.name,
-					summaryPoints: []float64{parsedMetric.floatvalue},
-					labelKeys:     parsedMetric.labelKeys,
-					labelValues:   parsedMetric.labelValues,
-					timeNow:       timeNowFunc(),
-				}
-			} else {
-				points := eachSummaryMetric.summaryPoints
-				p.summaries[parsedMetric.description] = summaryMetric{
-					name:          parsedMetric.description.name,
-					summaryPoints: append(points, parsedMetric.floatvalue),
-					labelKeys:     parsedMetric.labelKeys,
-					labelValues:   parsedMetric.labelValues,
-					timeNow:       timeNowFunc(),
-				}
-			}
-		}
+		p.observe(instrument, parsedMetric, p.observeHistogram)
 
 	case statsdTiming:
-		switch p.observeTimer {
-		case "gauge":
-			p.timersAndDistributions = append(p.timersAndDistributions, buildGaugeMetric(parsedMetric, timeNowFunc()))
-		case "summary":
-			eachSummaryMetric, ok := p.summaries[parsedMetric.description]
-			if !ok {
-				p.summaries[parsedMetric.description] = summaryMetric{
-					name:          parsedMetric.description.name,
-					summaryPoints: []float64{parsedMetric.floatvalue},
-					labelKeys:     parsedMetric.labelKeys,
-					labelValues:   parsedMetric.labelValues,
-					timeNow:       timeNowFunc(),
-				}
-			} else {
-				points := eachSummaryMetric.summaryPoints
-				p.summaries[parsedMetric.description] = summaryMetric{
-					name:          parsedMetric.description.name,
-					summaryPoints: append(points, parsedMetric.floatvalue),
-					labelKeys:     parsedMetric.labelKeys,
-					labelValues:   parsedMetric.labelValues,
-					timeNow:       timeNowFunc(),
-				}
-			}
-		}
+		p.observe(instrument, parsedMetric, p.observeTimer)
 	}
 
 	return nil
 }
 
+// observe records a timing or histogram sample according to the configured observer type.
+func (p *StatsDParser) observe(instrument *instruments, parsedMetric statsDMetric, observerType string) {
+	switch observerType {
+	case "gauge":
+		instrument.timersAndDistributions = append(instrument.timersAndDistributions, buildGaugeMetric(parsedMetric, timeNowFunc()))
+	case "summary":
+		eachSummaryMetric, ok := instrument.summaries[parsedMetric.description]
+		if !ok {
+			instrument.summaries[parsedMetric.description] = summaryMetric{
+				name:          parsedMetric.description.name,
+				summaryPoints: []float64{parsedMetric.floatvalue},
+				labelKeys:     parsedMetric.labelKeys,
+				labelValues:   parsedMetric.labelValues,
+				timeNow:       timeNowFunc(),
+			}
+		} else {
+			points := eachSummaryMetric.summaryPoints
+			instrument.summaries[parsedMetric.description] = summaryMetric{
+				name:          parsedMetric.description.name,
+				summaryPoints: append(points, parsedMetric.floatvalue),
+				labelKeys:     parsedMetric.labelKeys,
+				labelValues:   parsedMetric.labelValues,
+				timeNow:       timeNowFunc(),
+			}
+		}
+	}
+}
+
 func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, error) {
 	result := statsDMetric{}
 
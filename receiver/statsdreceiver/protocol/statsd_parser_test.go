@@ -16,10 +16,12 @@ package protocol
 
 import (
 	"errors"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -732,16 +734,16 @@ func TestStatsDParser_Aggregate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}})
+			p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, false, 0, 0, 0)
 			for _, line := range tt.input {
-				err = p.Aggregate(line)
+				err = p.Aggregate(line, nil)
 			}
 			if tt.err != nil {
 				assert.Equal(t, tt.err, err)
 			} else {
-				assert.Equal(t, tt.expectedGauges, p.gauges)
-				assert.Equal(t, tt.expectedCounters, p.counters)
-				assert.Equal(t, tt.expectedTimer, p.timersAndDistributions)
+				assert.Equal(t, tt.expectedGauges, p.instrumentsByAddress[netAddr{}].gauges)
+				assert.Equal(t, tt.expectedCounters, p.instrumentsByAddress[netAddr{}].counters)
+				assert.Equal(t, tt.expectedTimer, p.instrumentsByAddress[netAddr{}].timersAndDistributions)
 			}
 		})
 	}
@@ -800,15 +802,15 @@ func TestStatsDParser_AggregateWithMetricType(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}})
+			p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, false, 0, 0, 0)
 			for _, line := range tt.input {
-				err = p.Aggregate(line)
+				err = p.Aggregate(line, nil)
 			}
 			if tt.err != nil {
 				assert.Equal(t, tt.err, err)
 			} else {
-				assert.Equal(t, tt.expectedGauges, p.gauges)
-				assert.Equal(t, tt.expectedCounters, p.counters)
+				assert.Equal(t, tt.expectedGauges, p.instrumentsByAddress[netAddr{}].gauges)
+				assert.Equal(t, tt.expectedCounters, p.instrumentsByAddress[netAddr{}].counters)
 			}
 		})
 	}
@@ -891,14 +893,14 @@ func TestStatsDParser_AggregateTimmerWithSummary(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var err error
 			p := &StatsDParser{}
-			p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "summary"}, {StatsdType: "histogram", ObserverType: "summary"}})
+			p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "summary"}, {StatsdType: "histogram", ObserverType: "summary"}}, false, 0, 0, 0)
 			for _, line := range tt.input {
-				err = p.Aggregate(line)
+				err = p.Aggregate(line, nil)
 			}
 			if tt.err != nil {
 				assert.Equal(t, tt.err, err)
 			} else {
-				assert.Equal(t, tt.expectedSummaries, p.summaries)
+				assert.Equal(t, tt.expectedSummaries, p.instrumentsByAddress[netAddr{}].summaries)
 			}
 		})
 	}
@@ -906,32 +908,35 @@ func TestStatsDParser_AggregateTimmerWithSummary(t *testing.T) {
 
 func TestStatsDParser_Initialize(t *testing.T) {
 	p := &StatsDParser{}
-	p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}})
+	p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, false, 0, 0, 0)
 	labels := attribute.Distinct{}
 	teststatsdDMetricdescription := statsDMetricdescription{
 		name:             "test",
 		statsdMetricType: "g",
 		labels:           labels}
-	p.gauges[teststatsdDMetricdescription] = pdata.InstrumentationLibraryMetrics{}
-	assert.Equal(t, 1, len(p.gauges))
+	instrument := newInstruments(netAddr{})
+	instrument.gauges[teststatsdDMetricdescription] = pdata.InstrumentationLibraryMetrics{}
+	p.instrumentsByAddress[netAddr{}] = instrument
+	assert.Equal(t, 1, len(p.instrumentsByAddress))
 	assert.Equal(t, "gauge", p.observeTimer)
 	assert.Equal(t, "gauge", p.observeHistogram)
 }
 
 func TestStatsDParser_GetMetrics(t *testing.T) {
 	p := &StatsDParser{}
-	p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}})
-	p.gauges[testDescription("statsdTestMetric1", "g",
+	p.Initialize(true, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, false, 0, 0, 0)
+	instrument := newInstruments(netAddr{})
+	instrument.gauges[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey", "metric_type"}, []string{"myvalue", "gauge"})] =
 		buildGaugeMetric(testStatsDMetric("testGauge1", "", 0, 1, false, "g", 0, []string{"mykey", "metric_type"}, []string{"myvalue", "gauge"}), time.Unix(711, 0))
-	p.gauges[testDescription("statsdTestMetric1", "g",
+	instrument.gauges[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey2", "metric_type"}, []string{"myvalue2", "gauge"})] =
 		buildGaugeMetric(testStatsDMetric("statsdTestMetric1", "", 0, 10102, false, "g", 0, []string{"mykey2", "metric_type"}, []string{"myvalue2", "gauge"}), time.Unix(711, 0))
-	p.counters[testDescription("statsdTestMetric1", "g",
+	instrument.counters[testDescription("statsdTestMetric1", "g",
 		[]string{"mykey", "metric_type"}, []string{"myvalue", "gauge"})] =
 		buildGaugeMetric(testStatsDMetric("statsdTestMetric1", "", 0, 10102, false, "g", 0, []string{"mykey", "metric_type"}, []string{"myvalue", "gauge"}), time.Unix(711, 0))
-	p.timersAndDistributions = append(p.timersAndDistributions, buildGaugeMetric(testStatsDMetric("statsdTestMetric1", "", 0, 10102, false, "ms", 0, []string{"mykey2", "metric_type"}, []string{"myvalue2", "gauge"}), time.Unix(711, 0)))
-	p.summaries = map[statsDMetricdescription]summaryMetric{
+	instrument.timersAndDistributions = append(instrument.timersAndDistributions, buildGaugeMetric(testStatsDMetric("statsdTestMetric1", "", 0, 10102, false, "ms", 0, []string{"mykey2", "metric_type"}, []string{"myvalue2", "gauge"}), time.Unix(711, 0)))
+	instrument.summaries = map[statsDMetricdescription]summaryMetric{
 		testDescription("statsdTestMetric1", "h",
 			[]string{"mykey"}, []string{"myvalue"}): {
 			name:          "statsdTestMetric1",
@@ -940,10 +945,163 @@ func TestStatsDParser_GetMetrics(t *testing.T) {
 			labelValues:   []string{"myvalue"},
 			timeNow:       timeNowFunc(),
 		}}
+	p.instrumentsByAddress[netAddr{}] = instrument
 	metrics := p.GetMetrics()
 	assert.Equal(t, 5, metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Len())
 }
 
+func TestStatsDParser_AggregateBySourceAddr(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	addr1 := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+	addr2 := &net.UDPAddr{IP: net.ParseIP("10.0.0.2"), Port: 6000}
+	// the same client as addr1, using another ephemeral port
+	addr3 := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5001}
+
+	p := &StatsDParser{}
+	p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, true, 0, 0, 0)
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:myvalue", addr1))
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:2|c|#mykey:myvalue", addr3))
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:5|c|#mykey:myvalue", addr2))
+	require.Len(t, p.instrumentsByAddress, 2)
+
+	desc := testDescription("statsdTestMetric1", "c", []string{"mykey"}, []string{"myvalue"})
+	assert.Equal(t, int64(3), p.instrumentsByAddress[newNetAddr(addr1)].counters[desc].Metrics().At(0).IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, int64(5), p.instrumentsByAddress[newNetAddr(addr2)].counters[desc].Metrics().At(0).IntSum().DataPoints().At(0).Value())
+
+	metrics := p.GetMetrics()
+	require.Equal(t, 2, metrics.ResourceMetrics().Len())
+	var peers []string
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		attrs := metrics.ResourceMetrics().At(i).Resource().Attributes()
+		ip, ok := attrs.Get("net.peer.ip")
+		require.True(t, ok)
+		peers = append(peers, ip.StringVal())
+		_, ok = attrs.Get("net.peer.port")
+		assert.False(t, ok)
+	}
+	assert.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2"}, peers)
+	assert.Len(t, p.instrumentsByAddress, 0)
+}
+
+func TestStatsDParser_MaxSeriesPerInterval(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	addr1 := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+	addr2 := &net.UDPAddr{IP: net.ParseIP("10.0.0.2"), Port: 6000}
+
+	tests := []struct {
+		name                  string
+		aggregateBySourceAddr bool
+		expectedSeries        map[netAddr]int
+		expectedDropped       map[netAddr]int64
+	}{
+		{
+			name:            "global limit",
+			expectedSeries:  map[netAddr]int{{}: 2},
+			expectedDropped: map[netAddr]int64{{}: 3},
+		},
+		{
+			name:                  "limit per source address",
+			aggregateBySourceAddr: true,
+			expectedSeries:        map[netAddr]int{newNetAddr(addr1): 2, newNetAddr(addr2): 2},
+			expectedDropped:       map[netAddr]int64{newNetAddr(addr1): 1, newNetAddr(addr2): 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StatsDParser{}
+			p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, tt.aggregateBySourceAddr, 2, 0, 0)
+			assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value1", addr1))
+			assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value2", addr1))
+			assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value3", addr1))
+			// existing series keep being aggregated once the limit is reached
+			assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value1", addr1))
+			assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value4", addr2))
+			assert.NoError(t, p.Aggregate("statsdTestMetric2:1|g", addr2))
+
+			for addr, series := range tt.expectedSeries {
+				instrument := p.instrumentsByAddress[addr]
+				require.NotNil(t, instrument)
+				assert.Len(t, instrument.series, series)
+				assert.Equal(t, tt.expectedDropped[addr], instrument.droppedSeries)
+			}
+
+			var dropped int64
+			metrics := p.GetMetrics()
+			for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+				ilms := metrics.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+				for j := 0; j < ilms.Len(); j++ {
+					metric := ilms.At(j).Metrics().At(0)
+					if metric.Name() == droppedSeriesMetricName {
+						dropped += metric.IntSum().DataPoints().At(0).Value()
+					}
+				}
+			}
+			var expectedDropped int64
+			for _, d := range tt.expectedDropped {
+				expectedDropped += d
+			}
+			assert.Equal(t, expectedDropped, dropped)
+		})
+	}
+}
+
+func TestStatsDParser_MaxTotalSeriesAndClientsPerInterval(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	addr1 := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+	addr2 := &net.UDPAddr{IP: net.ParseIP("10.0.0.2"), Port: 6000}
+	addr3 := &net.UDPAddr{IP: net.ParseIP("10.0.0.3"), Port: 7000}
+
+	p := &StatsDParser{}
+	p.Initialize(false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}, true, 2, 3, 2)
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value1", addr1))
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value2", addr1))
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value3", addr2))
+	// the limit across the clients is reached
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value4", addr2))
+	// the limit of clients is reached
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value5", addr3))
+	assert.NoError(t, p.Aggregate("statsdTestMetric1:1|c|#mykey:value6", addr3))
+
+	require.Len(t, p.instrumentsByAddress, 2)
+	assert.Len(t, p.instrumentsByAddress[newNetAddr(addr1)].series, 2)
+	assert.Len(t, p.instrumentsByAddress[newNetAddr(addr2)].series, 1)
+	assert.Equal(t, int64(1), p.instrumentsByAddress[newNetAddr(addr2)].droppedSeries)
+	assert.Equal(t, 3, p.totalSeries)
+	assert.Equal(t, int64(2), p.droppedClientLines)
+
+	metrics := p.GetMetrics()
+	require.Equal(t, 3, metrics.ResourceMetrics().Len())
+	var droppedSeries, droppedClientLines int64
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		ilms := metrics.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metric := ilms.At(j).Metrics().At(0)
+			switch metric.Name() {
+			case droppedSeriesMetricName:
+				droppedSeries += metric.IntSum().DataPoints().At(0).Value()
+			case droppedClientLinesMetricName:
+				// the lines of the clients beyond the limit aren't attributed to any client
+				_, ok := metrics.ResourceMetrics().At(i).Resource().Attributes().Get("net.peer.ip")
+				assert.False(t, ok)
+				droppedClientLines += metric.IntSum().DataPoints().At(0).Value()
+			}
+		}
+	}
+	assert.Equal(t, int64(1), droppedSeries)
+	assert.Equal(t, int64(2), droppedClientLines)
+	assert.Equal(t, 0, p.totalSeries)
+	assert.Equal(t, int64(0), p.droppedClientLines)
+}
+
 func TestTimeNowFunc(t *testing.T) {
 	timeNow := timeNowFunc()
 	assert.NotNil(t, timeNow)
@@ -92,9 +92,16 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 	defer r.Unlock()
 
 	ctx, r.cancel = context.WithCancel(ctx)
-	var transferChan = make(chan string, 10)
+	var transferChan = make(chan transport.Metric, 10)
 	ticker := time.NewTicker(r.config.AggregationInterval)
-	r.parser.Initialize(r.config.EnableMetricType, r.config.TimerHistogramMapping)
+	r.parser.Initialize(
+		r.config.EnableMetricType,
+		r.config.TimerHistogramMapping,
+		r.config.AggregateBySourceAddress,
+		r.config.MaxSeriesPerInterval,
+		r.config.MaxTotalSeriesPerInterval,
+		r.config.MaxClientsPerInterval,
+	)
 	go func() {
 		if err := r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter, transferChan); err != nil {
 			host.ReportFatalError(err)
@@ -105,11 +112,11 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 			select {
 			case <-ticker.C:
 				metrics := r.parser.GetMetrics()
-				if metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Len() > 0 {
+				if metrics.MetricCount() > 0 {
 					r.Flush(ctx, metrics, r.nextConsumer)
 				}
 			case rawMetric := <-transferChan:
-				r.parser.Aggregate(rawMetric)
+				r.parser.Aggregate(rawMetric.Raw, rawMetric.Addr)
 			case <-ctx.Done():
 				ticker.Stop()
 				return
@@ -0,0 +1,165 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
+)
+
+// Commands of the AdminServer equivalent to the mntr, ruok, wchs and cons
+// 4lw commands.
+// https://zookeeper.apache.org/doc/r3.5.5/zookeeperAdmin.html#sc_adminserver
+const (
+	monitorAdminCommand      = "monitor"
+	ruokAdminCommand         = "ruok"
+	watchSummaryAdminCommand = "watch_summary"
+	connectionsAdminCommand  = "connections"
+)
+
+type adminResponse struct {
+	Error *string `json:"error"`
+}
+
+type adminWatchSummary struct {
+	NumConnections int64 `json:"num_connections"`
+	NumPaths       int64 `json:"num_paths"`
+}
+
+type adminConnections struct {
+	Connections       []adminConnection `json:"connections"`
+	SecureConnections []adminConnection `json:"secure_connections"`
+}
+
+type adminConnection struct {
+	RemoteSocketAddress string `json:"remote_socket_address"`
+	OutstandingRequests int64  `json:"outstanding_requests"`
+	MaxLatency          int64  `json:"max_latency"`
+}
+
+// getAdminServerResourceMetrics collects the same metrics as
+// getResourceMetrics from the AdminServer of ZooKeeper 3.5+, and whether the
+// AdminServer could be queried. The server is reported as down when it
+// can't.
+func (z *zookeeperMetricsScraper) getAdminServerResourceMetrics(ctx context.Context) (pdata.ResourceMetricsSlice, bool, error) {
+	var monitor map[string]interface{}
+	if err := z.getAdminCommand(ctx, monitorAdminCommand, &monitor); err != nil {
+		z.logger.Error("failed to query the AdminServer",
+			zap.String("endpoint", z.adminEndpoint),
+			zap.String("command", monitorAdminCommand),
+			zap.Error(err),
+		)
+		rms, err := downResourceMetrics(err)
+		return rms, false, err
+	}
+
+	stats, attributes := z.getMetricsAndAttributes(monitorAdminCommand, monitorLines(monitor))
+	metrics := newMetrics(stats, attributes)
+
+	var errs scrapererror.ScrapeErrors
+
+	up := int64(0)
+	var ruok adminResponse
+	if err := z.getAdminCommand(ctx, ruokAdminCommand, &ruok); err != nil {
+		errs.AddPartial(0, err)
+	} else if ruok.Error == nil {
+		up = 1
+	}
+	metrics.AddGaugeDataPoint(metadata.M.ZookeeperUp.Name(), up)
+
+	var watchSummary adminWatchSummary
+	if err := z.getAdminCommand(ctx, watchSummaryAdminCommand, &watchSummary); err != nil {
+		errs.AddPartial(wchsMetricsCount, err)
+	} else {
+		metrics.AddGaugeDataPoint(metadata.M.ZookeeperWatchConnections.Name(), watchSummary.NumConnections)
+		metrics.AddGaugeDataPoint(metadata.M.ZookeeperWatchedPaths.Name(), watchSummary.NumPaths)
+	}
+
+	var connections adminConnections
+	if err := z.getAdminCommand(ctx, connectionsAdminCommand, &connections); err != nil {
+		errs.AddPartial(consMetricsCount, err)
+	} else {
+		clients := map[string]*clientStats{}
+		for _, conn := range append(connections.Connections, connections.SecureConnections...) {
+			addClientConnection(clients, conn.RemoteSocketAddress, conn.OutstandingRequests, conn.MaxLatency)
+		}
+		addClientMetrics(&metrics, clients)
+	}
+
+	return metrics.ResourceMetrics(), true, errs.Combine()
+}
+
+// getAdminCommand decodes the JSON response of a command of the AdminServer.
+func (z *zookeeperMetricsScraper) getAdminCommand(ctx context.Context, command string, v interface{}) error {
+	url := strings.TrimSuffix(z.adminEndpoint, "/") + "/" + command
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := z.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the body is drained so that the connection is reused by the
+		// following commands
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", command, resp.Status)
+	}
+	decoder := json.NewDecoder(resp.Body)
+	// the numbers are formatted as in the mntr lines
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// monitorLines formats the response of the monitor command as the lines of
+// the mntr command, e.g. "zk_znode_count\t5", skipping the values which
+// aren't strings or numbers.
+func monitorLines(monitor map[string]interface{}) []string {
+	keys := make([]string, 0, len(monitor))
+	for key := range monitor {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		switch v := monitor[key].(type) {
+		case string:
+			if key == "command" {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("zk_%s\t%s", key, v))
+		case json.Number:
+			lines = append(lines, fmt.Sprintf("zk_%s\t%s", key, v))
+		}
+	}
+	return lines
+}
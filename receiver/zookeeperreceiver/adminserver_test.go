@@ -0,0 +1,176 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func TestAdminServer(t *testing.T) {
+	admin := newAdminServerMock(t, nil)
+	defer admin.Close()
+
+	// none of the 4lw commands are whitelisted
+	z := newTestScraper(t, nil)
+	z.adminEndpoint = admin.URL + "/commands"
+
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	assert.True(t, z.useAdminServer)
+
+	state, _ := got.At(0).Resource().Attributes().Get("server.state")
+	assert.Equal(t, "standalone", state.StringVal())
+	version, _ := got.At(0).Resource().Attributes().Get("zk.version")
+	assert.Equal(t, "3.5.5-390fe37ea45dee01bf87dc1c042b5e3dcce88653", version.StringVal())
+
+	assert.Equal(t, 19, got.At(0).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+	values := gaugeValues(got)
+	assert.Equal(t, int64(5), values["zookeeper.znodes"])
+	assert.Equal(t, int64(3), values["zookeeper.latency.max"])
+	assert.Equal(t, int64(1), values["zookeeper.up"])
+	assert.Equal(t, int64(1), values["zookeeper.watch_connections"])
+	assert.Equal(t, int64(2), values["zookeeper.watched_paths"])
+	assert.Equal(t, int64(1), values["zookeeper.client.connections{127.0.0.1}"])
+	assert.Equal(t, int64(1), values["zookeeper.client.outstanding_requests{127.0.0.1}"])
+	assert.Equal(t, int64(4), values["zookeeper.client.latency.max{127.0.0.1}"])
+
+	// the AdminServer is kept on the next scrapes
+	_, err = z.scrape(context.Background())
+	require.NoError(t, err)
+	assert.True(t, z.useAdminServer)
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestAdminServer_PartialError(t *testing.T) {
+	admin := newAdminServerMock(t, map[string]bool{connectionsAdminCommand: true})
+	defer admin.Close()
+	z := newTestScraper(t, nil)
+	z.adminEndpoint = admin.URL + "/commands/"
+
+	got, err := z.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, 16, got.At(0).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestAdminServer_Unavailable(t *testing.T) {
+	admin := newAdminServerMock(t, nil)
+	admin.Close()
+	z := newTestScraper(t, nil)
+	z.adminEndpoint = admin.URL + "/commands"
+
+	got, err := z.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	// the server is reported as down
+	assert.Equal(t, map[string]int64{"zookeeper.up": 0}, gaugeValues(got))
+	assert.False(t, z.useAdminServer)
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestAdminServer_SwitchBack(t *testing.T) {
+	admin := newAdminServerMock(t, nil)
+	admin.Close()
+	// mntr was whitelisted and the AdminServer disabled
+	z := newTestScraper(t, map[string]string{
+		mntrCommand: "mntr-3.5.5",
+	})
+	z.adminEndpoint = admin.URL + "/commands"
+	z.useAdminServer = true
+
+	_, err := z.scrape(context.Background())
+	require.Error(t, err)
+	assert.False(t, z.useAdminServer)
+
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	assert.False(t, z.useAdminServer)
+	assert.Equal(t, int64(5), gaugeValues(got)["zookeeper.znodes"])
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestAdminServer_ReusesConnection(t *testing.T) {
+	var conns int32
+	admin := httptest.NewUnstartedServer(adminServerHandler(t, nil))
+	admin.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	admin.Start()
+	defer admin.Close()
+	z := newTestScraper(t, nil)
+	z.adminEndpoint = admin.URL + "/commands"
+	z.httpClient = &http.Client{Transport: &http.Transport{}, Timeout: defaultTimeout}
+
+	_, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	// the four commands are sent on the same connection
+	assert.Equal(t, int32(1), atomic.LoadInt32(&conns))
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestDefaultAdminEndpoint(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		":2181":          "http://localhost:8080/commands",
+		"zk-1:2181":      "http://zk-1:8080/commands",
+		"[::1]:2181":     "http://[::1]:8080/commands",
+		"10.0.0.12:2181": "http://10.0.0.12:8080/commands",
+	} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Endpoint = endpoint
+		z, err := newZookeeperMetricsScraper(nil, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, want, z.adminEndpoint)
+	}
+}
+
+// newAdminServerMock serves the testdata/admin responses of the AdminServer
+// commands, except for the missing ones.
+func newAdminServerMock(t *testing.T, missing map[string]bool) *httptest.Server {
+	return httptest.NewServer(adminServerHandler(t, missing))
+}
+
+// adminServerHandler responds to the AdminServer commands with the testdata
+// files of the responses, the missing commands being answered as not found.
+func adminServerHandler(t *testing.T, missing map[string]bool) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		command := strings.TrimPrefix(req.URL.Path, "/commands/")
+		if missing[command] {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		out, err := ioutil.ReadFile(path.Join(".", "testdata", "admin", command+".json"))
+		if err != nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err = rw.Write(out)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/simple"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
+)
+
+const ruokResponse = "imok"
+
+var (
+	// e.g. "2 connections watching 5 paths"
+	wchsFormatRE = regexp.MustCompile(`^(\d+) connections watching (\d+) paths`)
+	// e.g. " /127.0.0.1:52386[1](queued=0,recved=12,sent=12,sid=0x1000...,maxlat=2)"
+	consFormatRE = regexp.MustCompile(`^\s*/?(\S+)\[\d+\]\((.*)\)$`)
+)
+
+// ruokUp returns the health of the server, which only responds imok when
+// running in a non-error state.
+func ruokUp(lines []string) int64 {
+	if len(lines) > 0 && lines[0] == ruokResponse {
+		return 1
+	}
+	return 0
+}
+
+// addWchsMetrics adds the watch summary of the wchs command.
+func (z *zookeeperMetricsScraper) addWchsMetrics(metrics *simple.Metrics, lines []string) {
+	for _, line := range lines {
+		parts := wchsFormatRE.FindStringSubmatch(line)
+		if len(parts) != 3 {
+			continue
+		}
+		connections, _ := strconv.ParseInt(parts[1], 10, 64)
+		paths, _ := strconv.ParseInt(parts[2], 10, 64)
+		metrics.AddGaugeDataPoint(metadata.M.ZookeeperWatchConnections.Name(), connections)
+		metrics.AddGaugeDataPoint(metadata.M.ZookeeperWatchedPaths.Name(), paths)
+		return
+	}
+	z.logger.Warn("unexpected response",
+		zap.String("command", wchsCommand),
+		zap.Strings("lines", lines),
+	)
+}
+
+// addConsMetrics adds the connection details of the cons command by client
+// host. The connection of the cons command itself, which has no session, is
+// skipped.
+func (z *zookeeperMetricsScraper) addConsMetrics(metrics *simple.Metrics, lines []string) {
+	clients := map[string]*clientStats{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := consFormatRE.FindStringSubmatch(line)
+		if len(parts) != 3 {
+			z.logger.Warn("unexpected line in response",
+				zap.String("command", consCommand),
+				zap.String("line", line),
+			)
+			continue
+		}
+		fields := map[string]string{}
+		for _, field := range strings.Split(parts[2], ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) == 2 {
+				fields[kv[0]] = kv[1]
+			}
+		}
+		if _, ok := fields["sid"]; !ok {
+			continue
+		}
+		queued, _ := strconv.ParseInt(fields["queued"], 10, 64)
+		maxLatency, _ := strconv.ParseInt(fields["maxlat"], 10, 64)
+		addClientConnection(clients, parts[1], queued, maxLatency)
+	}
+	addClientMetrics(metrics, clients)
+}
+
+// clientStats aggregates the sessions of a client host.
+type clientStats struct {
+	connections         int64
+	outstandingRequests int64
+	maxLatency          int64
+}
+
+func addClientConnection(clients map[string]*clientStats, address string, outstandingRequests, maxLatency int64) {
+	// the port differs between the connections of a host
+	if i := strings.LastIndex(address, ":"); i > 0 {
+		address = address[:i]
+	}
+	address = strings.Trim(strings.TrimPrefix(address, "/"), "[]")
+	client, ok := clients[address]
+	if !ok {
+		client = &clientStats{}
+		clients[address] = client
+	}
+	client.connections++
+	client.outstandingRequests += outstandingRequests
+	if maxLatency > client.maxLatency {
+		client.maxLatency = maxLatency
+	}
+}
+
+func addClientMetrics(metrics *simple.Metrics, clients map[string]*clientStats) {
+	for address, client := range clients {
+		clientMetrics := metrics.WithLabels(map[string]string{metadata.L.ClientAddress: address})
+		clientMetrics.AddGaugeDataPoint(metadata.M.ZookeeperClientConnections.Name(), client.connections)
+		clientMetrics.AddGaugeDataPoint(metadata.M.ZookeeperClientOutstandingRequests.Name(), client.outstandingRequests)
+		clientMetrics.AddGaugeDataPoint(metadata.M.ZookeeperClientLatencyMax.Name(), client.maxLatency)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
+)
+
+func TestFourLetterWordCommands(t *testing.T) {
+	z := newTestScraper(t, map[string]string{
+		mntrCommand: "mntr-3.5.5",
+		ruokCommand: "ruok",
+		wchsCommand: "wchs",
+		consCommand: "cons",
+	})
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, z.shutdown(context.Background()))
+
+	values := gaugeValues(got)
+	assert.Equal(t, int64(1), values["zookeeper.up"])
+	assert.Equal(t, int64(3), values["zookeeper.watch_connections"])
+	assert.Equal(t, int64(5), values["zookeeper.watched_paths"])
+	// the connection of the cons command has no session
+	assert.Equal(t, int64(2), values["zookeeper.client.connections{127.0.0.1}"])
+	assert.Equal(t, int64(1), values["zookeeper.client.connections{10.0.0.5}"])
+	assert.Equal(t, int64(2), values["zookeeper.client.outstanding_requests{127.0.0.1}"])
+	assert.Equal(t, int64(9), values["zookeeper.client.latency.max{127.0.0.1}"])
+	assert.Equal(t, int64(1), values["zookeeper.client.latency.max{10.0.0.5}"])
+}
+
+func TestFourLetterWordCommands_NotWhitelisted(t *testing.T) {
+	z := newTestScraper(t, map[string]string{
+		mntrCommand: "mntr-3.5.5",
+	})
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, z.shutdown(context.Background()))
+	assert.False(t, z.useAdminServer)
+
+	values := gaugeValues(got)
+	assert.Equal(t, int64(5), values["zookeeper.znodes"])
+	// the server responded to mntr
+	assert.Equal(t, int64(1), values["zookeeper.up"])
+	_, found := values["zookeeper.watch_connections"]
+	assert.False(t, found)
+}
+
+func TestRuokNotOK(t *testing.T) {
+	assert.Equal(t, int64(0), ruokUp(nil))
+	assert.Equal(t, int64(0), ruokUp([]string{"imnotok"}))
+	assert.Equal(t, int64(1), ruokUp([]string{ruokResponse}))
+
+	// the server closes the connection without responding to ruok
+	z := newTestScraper(t, map[string]string{
+		mntrCommand: "mntr-3.5.5",
+		ruokCommand: "",
+	})
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, z.shutdown(context.Background()))
+	assert.Equal(t, int64(0), gaugeValues(got)[metadata.M.ZookeeperUp.Name()])
+}
+
+func TestServerDown(t *testing.T) {
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), &Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		Timeout: defaultTimeout,
+	})
+	require.NoError(t, err)
+
+	got, err := z.scrape(context.Background())
+	require.Error(t, err)
+	require.NoError(t, z.shutdown(context.Background()))
+	// the server is reported as down rather than its metrics missing
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, downFailedMetricsCount, err.(scrapererror.PartialScrapeError).Failed)
+	require.Equal(t, 1, got.Len())
+	assert.Equal(t, map[string]int64{metadata.M.ZookeeperUp.Name(): 0}, gaugeValues(got))
+	assert.False(t, z.useAdminServer)
+}
+
+func newTestScraper(t *testing.T, responses map[string]string) *zookeeperMetricsScraper {
+	localAddr := testutil.GetAvailableLocalAddress(t)
+	ms := mockedServer{ready: make(chan bool, 1)}
+	go ms.mockZKServer(t, localAddr, responses)
+	<-ms.ready
+
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), &Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: localAddr,
+		},
+		Timeout: defaultTimeout,
+	})
+	require.NoError(t, err)
+	return z
+}
+
+// gaugeValues returns the values of the gauges by name, followed by the
+// client address between braces for the client metrics.
+func gaugeValues(rms pdata.ResourceMetricsSlice) map[string]int64 {
+	values := map[string]int64{}
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		if m.DataType() != pdata.MetricDataTypeIntGauge {
+			continue
+		}
+		dps := m.IntGauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			key := m.Name()
+			if address, ok := dps.At(j).LabelsMap().Get(metadata.L.ClientAddress); ok {
+				key += "{" + address + "}"
+			}
+			values[key] = dps.At(j).Value()
+		}
+	}
+	return values
+}
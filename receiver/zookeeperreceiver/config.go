@@ -27,4 +27,10 @@ type Config struct {
 
 	// Timeout within which requests should be completed.
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// AdminEndpoint is the URL of the commands of the AdminServer of ZooKeeper
+	// 3.5+, e.g. http://localhost:8080/commands, used when the 4lw commands are
+	// not in the whitelist of the server. Defaults to the host of Endpoint on
+	// port 8080.
+	AdminEndpoint string `mapstructure:"admin_endpoint"`
 }
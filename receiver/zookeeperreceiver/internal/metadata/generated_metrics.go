@@ -55,29 +55,38 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	ZookeeperApproximateDateSize   MetricIntf
-	ZookeeperConnectionsAlive      MetricIntf
-	ZookeeperEphemeralNodes        MetricIntf
-	ZookeeperFollowers             MetricIntf
-	ZookeeperFsyncThresholdExceeds MetricIntf
-	ZookeeperLatencyAvg            MetricIntf
-	ZookeeperLatencyMax            MetricIntf
-	ZookeeperLatencyMin            MetricIntf
-	ZookeeperMaxFileDescriptors    MetricIntf
-	ZookeeperOpenFileDescriptors   MetricIntf
-	ZookeeperOutstandingRequests   MetricIntf
-	ZookeeperPacketsReceived       MetricIntf
-	ZookeeperPacketsSent           MetricIntf
-	ZookeeperPendingSyncs          MetricIntf
-	ZookeeperSyncedFollowers       MetricIntf
-	ZookeeperWatches               MetricIntf
-	ZookeeperZnodes                MetricIntf
+	ZookeeperApproximateDateSize       MetricIntf
+	ZookeeperClientConnections         MetricIntf
+	ZookeeperClientLatencyMax          MetricIntf
+	ZookeeperClientOutstandingRequests MetricIntf
+	ZookeeperConnectionsAlive          MetricIntf
+	ZookeeperEphemeralNodes            MetricIntf
+	ZookeeperFollowers                 MetricIntf
+	ZookeeperFsyncThresholdExceeds     MetricIntf
+	ZookeeperLatencyAvg                MetricIntf
+	ZookeeperLatencyMax                MetricIntf
+	ZookeeperLatencyMin                MetricIntf
+	ZookeeperMaxFileDescriptors        MetricIntf
+	ZookeeperOpenFileDescriptors       MetricIntf
+	ZookeeperOutstandingRequests       MetricIntf
+	ZookeeperPacketsReceived           MetricIntf
+	ZookeeperPacketsSent               MetricIntf
+	ZookeeperPendingSyncs              MetricIntf
+	ZookeeperSyncedFollowers           MetricIntf
+	ZookeeperUp                        MetricIntf
+	ZookeeperWatchConnections          MetricIntf
+	ZookeeperWatchedPaths              MetricIntf
+	ZookeeperWatches                   MetricIntf
+	ZookeeperZnodes                    MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
 func (m *metricStruct) Names() []string {
 	return []string{
 		"zookeeper.approximate_date_size",
+		"zookeeper.client.connections",
+		"zookeeper.client.latency.max",
+		"zookeeper.client.outstanding_requests",
 		"zookeeper.connections_alive",
 		"zookeeper.ephemeral_nodes",
 		"zookeeper.followers",
@@ -92,29 +101,38 @@ func (m *metricStruct) Names() []string {
 		"zookeeper.packets.sent",
 		"zookeeper.pending_syncs",
 		"zookeeper.synced_followers",
+		"zookeeper.up",
+		"zookeeper.watch_connections",
+		"zookeeper.watched_paths",
 		"zookeeper.watches",
 		"zookeeper.znodes",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"zookeeper.approximate_date_size":   Metrics.ZookeeperApproximateDateSize,
-	"zookeeper.connections_alive":       Metrics.ZookeeperConnectionsAlive,
-	"zookeeper.ephemeral_nodes":         Metrics.ZookeeperEphemeralNodes,
-	"zookeeper.followers":               Metrics.ZookeeperFollowers,
-	"zookeeper.fsync_threshold_exceeds": Metrics.ZookeeperFsyncThresholdExceeds,
-	"zookeeper.latency.avg":             Metrics.ZookeeperLatencyAvg,
-	"zookeeper.latency.max":             Metrics.ZookeeperLatencyMax,
-	"zookeeper.latency.min":             Metrics.ZookeeperLatencyMin,
-	"zookeeper.max_file_descriptors":    Metrics.ZookeeperMaxFileDescriptors,
-	"zookeeper.open_file_descriptors":   Metrics.ZookeeperOpenFileDescriptors,
-	"zookeeper.outstanding_requests":    Metrics.ZookeeperOutstandingRequests,
-	"zookeeper.packets.received":        Metrics.ZookeeperPacketsReceived,
-	"zookeeper.packets.sent":            Metrics.ZookeeperPacketsSent,
-	"zookeeper.pending_syncs":           Metrics.ZookeeperPendingSyncs,
-	"zookeeper.synced_followers":        Metrics.ZookeeperSyncedFollowers,
-	"zookeeper.watches":                 Metrics.ZookeeperWatches,
-	"zookeeper.znodes":                  Metrics.ZookeeperZnodes,
+	"zookeeper.approximate_date_size":       Metrics.ZookeeperApproximateDateSize,
+	"zookeeper.client.connections":          Metrics.ZookeeperClientConnections,
+	"zookeeper.client.latency.max":          Metrics.ZookeeperClientLatencyMax,
+	"zookeeper.client.outstanding_requests": Metrics.ZookeeperClientOutstandingRequests,
+	"zookeeper.connections_alive":           Metrics.ZookeeperConnectionsAlive,
+	"zookeeper.ephemeral_nodes":             Metrics.ZookeeperEphemeralNodes,
+	"zookeeper.followers":                   Metrics.ZookeeperFollowers,
+	"zookeeper.fsync_threshold_exceeds":     Metrics.ZookeeperFsyncThresholdExceeds,
+	"zookeeper.latency.avg":                 Metrics.ZookeeperLatencyAvg,
+	"zookeeper.latency.max":                 Metrics.ZookeeperLatencyMax,
+	"zookeeper.latency.min":                 Metrics.ZookeeperLatencyMin,
+	"zookeeper.max_file_descriptors":        Metrics.ZookeeperMaxFileDescriptors,
+	"zookeeper.open_file_descriptors":       Metrics.ZookeeperOpenFileDescriptors,
+	"zookeeper.outstanding_requests":        Metrics.ZookeeperOutstandingRequests,
+	"zookeeper.packets.received":            Metrics.ZookeeperPacketsReceived,
+	"zookeeper.packets.sent":                Metrics.ZookeeperPacketsSent,
+	"zookeeper.pending_syncs":               Metrics.ZookeeperPendingSyncs,
+	"zookeeper.synced_followers":            Metrics.ZookeeperSyncedFollowers,
+	"zookeeper.up":                          Metrics.ZookeeperUp,
+	"zookeeper.watch_connections":           Metrics.ZookeeperWatchConnections,
+	"zookeeper.watched_paths":               Metrics.ZookeeperWatchedPaths,
+	"zookeeper.watches":                     Metrics.ZookeeperWatches,
+	"zookeeper.znodes":                      Metrics.ZookeeperZnodes,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -123,23 +141,29 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.ZookeeperApproximateDateSize.Name():   Metrics.ZookeeperApproximateDateSize.Init,
-		Metrics.ZookeeperConnectionsAlive.Name():      Metrics.ZookeeperConnectionsAlive.Init,
-		Metrics.ZookeeperEphemeralNodes.Name():        Metrics.ZookeeperEphemeralNodes.Init,
-		Metrics.ZookeeperFollowers.Name():             Metrics.ZookeeperFollowers.Init,
-		Metrics.ZookeeperFsyncThresholdExceeds.Name(): Metrics.ZookeeperFsyncThresholdExceeds.Init,
-		Metrics.ZookeeperLatencyAvg.Name():            Metrics.ZookeeperLatencyAvg.Init,
-		Metrics.ZookeeperLatencyMax.Name():            Metrics.ZookeeperLatencyMax.Init,
-		Metrics.ZookeeperLatencyMin.Name():            Metrics.ZookeeperLatencyMin.Init,
-		Metrics.ZookeeperMaxFileDescriptors.Name():    Metrics.ZookeeperMaxFileDescriptors.Init,
-		Metrics.ZookeeperOpenFileDescriptors.Name():   Metrics.ZookeeperOpenFileDescriptors.Init,
-		Metrics.ZookeeperOutstandingRequests.Name():   Metrics.ZookeeperOutstandingRequests.Init,
-		Metrics.ZookeeperPacketsReceived.Name():       Metrics.ZookeeperPacketsReceived.Init,
-		Metrics.ZookeeperPacketsSent.Name():           Metrics.ZookeeperPacketsSent.Init,
-		Metrics.ZookeeperPendingSyncs.Name():          Metrics.ZookeeperPendingSyncs.Init,
-		Metrics.ZookeeperSyncedFollowers.Name():       Metrics.ZookeeperSyncedFollowers.Init,
-		Metrics.ZookeeperWatches.Name():               Metrics.ZookeeperWatches.Init,
-		Metrics.ZookeeperZnodes.Name():                Metrics.ZookeeperZnodes.Init,
+		Metrics.ZookeeperApproximateDateSize.Name():       Metrics.ZookeeperApproximateDateSize.Init,
+		Metrics.ZookeeperClientConnections.Name():         Metrics.ZookeeperClientConnections.Init,
+		Metrics.ZookeeperClientLatencyMax.Name():          Metrics.ZookeeperClientLatencyMax.Init,
+		Metrics.ZookeeperClientOutstandingRequests.Name(): Metrics.ZookeeperClientOutstandingRequests.Init,
+		Metrics.ZookeeperConnectionsAlive.Name():          Metrics.ZookeeperConnectionsAlive.Init,
+		Metrics.ZookeeperEphemeralNodes.Name():            Metrics.ZookeeperEphemeralNodes.Init,
+		Metrics.ZookeeperFollowers.Name():                 Metrics.ZookeeperFollowers.Init,
+		Metrics.ZookeeperFsyncThresholdExceeds.Name():     Metrics.ZookeeperFsyncThresholdExceeds.Init,
+		Metrics.ZookeeperLatencyAvg.Name():                Metrics.ZookeeperLatencyAvg.Init,
+		Metrics.ZookeeperLatencyMax.Name():                Metrics.ZookeeperLatencyMax.Init,
+		Metrics.ZookeeperLatencyMin.Name():                Metrics.ZookeeperLatencyMin.Init,
+		Metrics.ZookeeperMaxFileDescriptors.Name():        Metrics.ZookeeperMaxFileDescriptors.Init,
+		Metrics.ZookeeperOpenFileDescriptors.Name():       Metrics.ZookeeperOpenFileDescriptors.Init,
+		Metrics.ZookeeperOutstandingRequests.Name():       Metrics.ZookeeperOutstandingRequests.Init,
+		Metrics.ZookeeperPacketsReceived.Name():           Metrics.ZookeeperPacketsReceived.Init,
+		Metrics.ZookeeperPacketsSent.Name():               Metrics.ZookeeperPacketsSent.Init,
+		Metrics.ZookeeperPendingSyncs.Name():              Metrics.ZookeeperPendingSyncs.Init,
+		Metrics.ZookeeperSyncedFollowers.Name():           Metrics.ZookeeperSyncedFollowers.Init,
+		Metrics.ZookeeperUp.Name():                        Metrics.ZookeeperUp.Init,
+		Metrics.ZookeeperWatchConnections.Name():          Metrics.ZookeeperWatchConnections.Init,
+		Metrics.ZookeeperWatchedPaths.Name():              Metrics.ZookeeperWatchedPaths.Init,
+		Metrics.ZookeeperWatches.Name():                   Metrics.ZookeeperWatches.Init,
+		Metrics.ZookeeperZnodes.Name():                    Metrics.ZookeeperZnodes.Init,
 	}
 }
 
@@ -155,6 +179,33 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"zookeeper.client.connections",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.client.connections")
+			metric.SetDescription("Number of sessions connected from a client host.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.client.latency.max",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.client.latency.max")
+			metric.SetDescription("Maximum latency of the requests of the sessions of a client host.")
+			metric.SetUnit("ms")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.client.outstanding_requests",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.client.outstanding_requests")
+			metric.SetDescription("Number of queued requests of the sessions of a client host.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"zookeeper.connections_alive",
 		func(metric pdata.Metric) {
@@ -287,6 +338,33 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"zookeeper.up",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.up")
+			metric.SetDescription("Whether the server is running in a non-error state (1) or not (0), from the ruok command, 0 when the server can't be queried.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.watch_connections",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.watch_connections")
+			metric.SetDescription("Number of client connections with watches.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.watched_paths",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.watched_paths")
+			metric.SetDescription("Number of z-node paths with watches.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"zookeeper.watches",
 		func(metric pdata.Metric) {
@@ -313,11 +391,14 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
+	// ClientAddress (Address of the client host, without the port of its connections.)
+	ClientAddress string
 	// ServerState (State of the Zookeeper server (leader, standalone or follower).)
 	ServerState string
 	// ZkVersion (Zookeeper version of the instance.)
 	ZkVersion string
 }{
+	"client.address",
 	"server.state",
 	"zk.version",
 }
@@ -20,12 +20,15 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
@@ -35,13 +38,39 @@ var zookeeperFormatRE = regexp.MustCompile(`(^zk_\w+)\s+([\w\.\-]+)`)
 
 const (
 	mntrCommand = "mntr"
+	ruokCommand = "ruok"
+	wchsCommand = "wchs"
+	consCommand = "cons"
+
+	// notWhitelistedSuffix ends the response of ZooKeeper 3.5+ to the 4lw
+	// commands missing from its 4lw.commands.whitelist.
+	notWhitelistedSuffix = "is not executed because it is not in the whitelist."
+
+	defaultAdminPort = "8080"
+
+	// the numbers of metrics of the wchs and cons commands, reported as
+	// failed when the commands fail
+	wchsMetricsCount = 2
+	consMetricsCount = 3
+	// downFailedMetricsCount is the number of metrics reported as failed
+	// when the server can't be queried, zookeeper.up being sent anyway.
+	downFailedMetricsCount = metricsLen + wchsMetricsCount + consMetricsCount
 )
 
+var errNotWhitelisted = errors.New("command is not in the 4lw whitelist")
+
 type zookeeperMetricsScraper struct {
 	logger *zap.Logger
 	config *Config
 	cancel context.CancelFunc
 
+	adminEndpoint string
+	httpClient    *http.Client
+	// useAdminServer is set once mntr turns out not to be whitelisted, the
+	// metrics being collected from the AdminServer until it can't be queried
+	// anymore, the 4lw commands being tried again then.
+	useAdminServer bool
+
 	// For mocking.
 	closeConnection       func(net.Conn) error
 	setConnectionDeadline func(net.Conn, time.Time) error
@@ -53,7 +82,7 @@ func (z *zookeeperMetricsScraper) Name() string {
 }
 
 func newZookeeperMetricsScraper(logger *zap.Logger, config *Config) (*zookeeperMetricsScraper, error) {
-	_, _, err := net.SplitHostPort(config.TCPAddr.Endpoint)
+	host, _, err := net.SplitHostPort(config.TCPAddr.Endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +91,19 @@ func newZookeeperMetricsScraper(logger *zap.Logger, config *Config) (*zookeeperM
 		return nil, errors.New("timeout must be a positive duration")
 	}
 
+	adminEndpoint := config.AdminEndpoint
+	if adminEndpoint == "" {
+		if host == "" {
+			host = "localhost"
+		}
+		adminEndpoint = "http://" + net.JoinHostPort(host, defaultAdminPort) + "/commands"
+	}
+
 	return &zookeeperMetricsScraper{
 		logger:                logger,
 		config:                config,
+		adminEndpoint:         adminEndpoint,
+		httpClient:            &http.Client{Timeout: config.Timeout},
 		closeConnection:       closeConnection,
 		setConnectionDeadline: setConnectionDeadline,
 		sendCmd:               sendCmd,
@@ -80,13 +119,106 @@ func (z *zookeeperMetricsScraper) scrape(ctx context.Context) (pdata.ResourceMet
 	var ctxWithTimeout context.Context
 	ctxWithTimeout, z.cancel = context.WithTimeout(ctx, z.config.Timeout)
 
+	if !z.useAdminServer {
+		rms, err := z.getResourceMetrics(ctxWithTimeout)
+		if !errors.Is(err, errNotWhitelisted) {
+			return rms, err
+		}
+		z.logger.Info("mntr is not in the 4lw whitelist, switching to the AdminServer",
+			zap.String("endpoint", z.adminEndpoint),
+		)
+	}
+
+	rms, available, err := z.getAdminServerResourceMetrics(ctxWithTimeout)
+	// e.g. once mntr was whitelisted and the AdminServer disabled, the 4lw
+	// commands are tried again on the next scrape
+	if z.useAdminServer && !available {
+		z.logger.Info("failed to query the AdminServer, switching back to the 4lw commands",
+			zap.String("endpoint", z.config.Endpoint),
+		)
+	}
+	z.useAdminServer = available
+	return rms, err
+}
+
+// downResourceMetrics reports the server as down when it can't be queried,
+// so that zookeeper.up is sent whatever the failure, e.g. to be alerted on.
+func downResourceMetrics(err error) (pdata.ResourceMetricsSlice, error) {
+	metrics := newMetrics(nil, nil)
+	metrics.AddGaugeDataPoint(metadata.M.ZookeeperUp.Name(), 0)
+	return metrics.ResourceMetrics(), scrapererror.NewPartialScrapeError(err, downFailedMetricsCount)
+}
+
+type stat struct {
+	metric pdata.Metric
+	val    int64
+}
+
+// getResourceMetrics collects the metrics of the mntr command, along with
+// the ruok, wchs and cons commands when they are whitelisted. The server is
+// reported as down when mntr fails, and the metrics are collected from the
+// AdminServer instead when mntr isn't whitelisted.
+func (z *zookeeperMetricsScraper) getResourceMetrics(ctx context.Context) (pdata.ResourceMetricsSlice, error) {
+	lines, err := z.runCommand(ctx, mntrCommand)
+	if errors.Is(err, errNotWhitelisted) {
+		return pdata.NewResourceMetricsSlice(), err
+	}
+	if err != nil {
+		return downResourceMetrics(err)
+	}
+
+	stats, attributes := z.getMetricsAndAttributes(mntrCommand, lines)
+	metrics := newMetrics(stats, attributes)
+
+	var errs scrapererror.ScrapeErrors
+	// the server is up when it responds to mntr, unless ruok is whitelisted
+	// and tells otherwise
+	up := int64(1)
+	lines, err = z.runCommand(ctx, ruokCommand)
+	switch {
+	case errors.Is(err, errNotWhitelisted):
+		z.logger.Debug("command is not in the 4lw whitelist", zap.String("command", ruokCommand))
+	case err != nil:
+		up = 0
+		errs.AddPartial(0, err)
+	default:
+		up = ruokUp(lines)
+	}
+	metrics.AddGaugeDataPoint(metadata.M.ZookeeperUp.Name(), up)
+
+	for _, c := range []struct {
+		command    string
+		numMetrics int
+		addMetrics func(*simple.Metrics, []string)
+	}{
+		{wchsCommand, wchsMetricsCount, z.addWchsMetrics},
+		{consCommand, consMetricsCount, z.addConsMetrics},
+	} {
+		lines, err := z.runCommand(ctx, c.command)
+		if errors.Is(err, errNotWhitelisted) {
+			z.logger.Debug("command is not in the 4lw whitelist", zap.String("command", c.command))
+			continue
+		}
+		if err != nil {
+			errs.AddPartial(c.numMetrics, err)
+			continue
+		}
+		c.addMetrics(&metrics, lines)
+	}
+	return metrics.ResourceMetrics(), errs.Combine()
+}
+
+// runCommand sends a 4lw command on a new connection and returns the lines of
+// the response. A connection can't be reused for several commands since the
+// server closes it after its response.
+func (z *zookeeperMetricsScraper) runCommand(ctx context.Context, command string) ([]string, error) {
 	conn, err := z.config.Dial()
 	if err != nil {
 		z.logger.Error("failed to establish connection",
 			zap.String("endpoint", z.config.Endpoint),
 			zap.Error(err),
 		)
-		return pdata.NewResourceMetricsSlice(), err
+		return nil, err
 	}
 	defer func() {
 		if closeErr := z.closeConnection(conn); closeErr != nil {
@@ -94,32 +226,35 @@ func (z *zookeeperMetricsScraper) scrape(ctx context.Context) (pdata.ResourceMet
 		}
 	}()
 
-	deadline, ok := ctxWithTimeout.Deadline()
+	deadline, ok := ctx.Deadline()
 	if ok {
 		if err := z.setConnectionDeadline(conn, deadline); err != nil {
 			z.logger.Warn("failed to set deadline on connection", zap.Error(err))
 		}
 	}
 
-	return z.getResourceMetrics(conn)
-}
-
-type stat struct {
-	metric pdata.Metric
-	val    int64
-}
-
-func (z *zookeeperMetricsScraper) getResourceMetrics(conn net.Conn) (pdata.ResourceMetricsSlice, error) {
-	scanner, err := z.sendCmd(conn, mntrCommand)
+	scanner, err := z.sendCmd(conn, command)
 	if err != nil {
 		z.logger.Error("failed to send command",
 			zap.Error(err),
-			zap.String("command", mntrCommand),
+			zap.String("command", command),
 		)
-		return pdata.NewResourceMetricsSlice(), err
+		return nil, err
 	}
 
-	stats, attributes := z.getMetricsAndAttributes(scanner)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) == 1 && strings.HasSuffix(lines[0], notWhitelistedSuffix) {
+		return nil, fmt.Errorf("%s: %w", command, errNotWhitelisted)
+	}
+	return lines, nil
+}
+
+// newMetrics creates the metrics of the stats, with the attributes of the
+// server as resource attributes.
+func newMetrics(stats []stat, attributes map[string]string) simple.Metrics {
 	metrics := simple.Metrics{
 		Metrics:                    pdata.NewMetrics(),
 		Timestamp:                  time.Now(),
@@ -137,18 +272,19 @@ func (z *zookeeperMetricsScraper) getResourceMetrics(conn net.Conn) (pdata.Resou
 			metrics.AddSumDataPoint(stat.metric.Name(), stat.val)
 		}
 	}
-	return metrics.ResourceMetrics(), nil
+	return metrics
 }
 
-func (z *zookeeperMetricsScraper) getMetricsAndAttributes(scanner *bufio.Scanner) ([]stat, map[string]string) {
+// getMetricsAndAttributes parses the lines of the mntr command, or of the
+// monitor command of the AdminServer formatted as mntr lines.
+func (z *zookeeperMetricsScraper) getMetricsAndAttributes(command string, lines []string) ([]stat, map[string]string) {
 	attributes := make(map[string]string, 2)
 	stats := make([]stat, 0, metricsLen)
-	for scanner.Scan() {
-		line := scanner.Text()
+	for _, line := range lines {
 		parts := zookeeperFormatRE.FindStringSubmatch(line)
 		if len(parts) != 3 {
 			z.logger.Warn("unexpected line in response",
-				zap.String("command", mntrCommand),
+				zap.String("command", command),
 				zap.String("line", line),
 			)
 			continue
@@ -169,7 +305,7 @@ func (z *zookeeperMetricsScraper) getMetricsAndAttributes(scanner *bufio.Scanner
 			int64Val, err := strconv.ParseInt(metricValue, 10, 64)
 			if err != nil {
 				z.logger.Debug(
-					fmt.Sprintf("non-integer value from %s", mntrCommand),
+					fmt.Sprintf("non-integer value from %s", command),
 					zap.String("value", metricValue),
 				)
 				continue
@@ -18,15 +18,18 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.opentelemetry.io/collector/testutil"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -60,6 +63,7 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 	var metricsV3414 []pdata.Metric
 	metricsV3414 = append(metricsV3414, commonMetrics...)
 	metricsV3414 = append(metricsV3414, metadata.Metrics.ZookeeperFsyncThresholdExceeds.New())
+	metricsV3414 = append(metricsV3414, fourLetterWordMetrics()...)
 
 	tests := []struct {
 		name                         string
@@ -88,7 +92,7 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 			name:                         "Test correctness with v3.5.5",
 			mockedZKOutputSourceFilename: "mntr-3.5.5",
 			expectedMetrics: func() []pdata.Metric {
-				out := make([]pdata.Metric, 0, len(commonMetrics)+3)
+				out := make([]pdata.Metric, 0, len(commonMetrics)+9)
 				out = append(out, commonMetrics...)
 
 				out = append(out, []pdata.Metric{
@@ -96,6 +100,7 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 					metadata.Metrics.ZookeeperSyncedFollowers.New(),
 					metadata.Metrics.ZookeeperPendingSyncs.New(),
 				}...)
+				out = append(out, fourLetterWordMetrics()...)
 				return out
 			}(),
 			expectedResourceAttributes: map[string]string{
@@ -113,7 +118,10 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 					level: zapcore.ErrorLevel,
 				},
 			},
-			wantErr: true,
+			// the server is reported as down
+			expectedMetrics:            []pdata.Metric{metadata.Metrics.ZookeeperUp.New()},
+			expectedNumResourceMetrics: 1,
+			wantErr:                    true,
 		},
 		{
 			name:                         "Unexpected line format in mntr",
@@ -124,7 +132,11 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 					level: zapcore.WarnLevel,
 				},
 			},
-			expectedNumResourceMetrics: 0,
+			expectedMetrics: fourLetterWordMetrics(),
+			expectedResourceAttributes: map[string]string{
+				"zk.version": "3.5.5-390fe37ea45dee01bf87dc1c042b5e3dcce88653",
+			},
+			expectedNumResourceMetrics: 1,
 		},
 		{
 			name:                         "Unexpected value type in mntr",
@@ -135,16 +147,20 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 					level: zapcore.DebugLevel,
 				},
 			},
-			expectedNumResourceMetrics: 0,
+			expectedMetrics: fourLetterWordMetrics(),
+			expectedResourceAttributes: map[string]string{
+				"zk.version": "3.5.5-390fe37ea45dee01bf87dc1c042b5e3dcce88653",
+			},
+			expectedNumResourceMetrics: 1,
 		},
 		{
 			name:                         "Error setting connection deadline",
 			mockedZKOutputSourceFilename: "mntr-3.4.14",
 			expectedLogs: []logMsg{
-				{
-					msg:   "failed to set deadline on connection",
-					level: zapcore.WarnLevel,
-				},
+				{msg: "failed to set deadline on connection", level: zapcore.WarnLevel},
+				{msg: "failed to set deadline on connection", level: zapcore.WarnLevel},
+				{msg: "failed to set deadline on connection", level: zapcore.WarnLevel},
+				{msg: "failed to set deadline on connection", level: zapcore.WarnLevel},
 			},
 			expectedMetrics: metricsV3414,
 			expectedResourceAttributes: map[string]string{
@@ -160,10 +176,10 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 			name:                         "Error closing connection",
 			mockedZKOutputSourceFilename: "mntr-3.4.14",
 			expectedLogs: []logMsg{
-				{
-					msg:   "failed to shutdown connection",
-					level: zapcore.WarnLevel,
-				},
+				{msg: "failed to shutdown connection", level: zapcore.WarnLevel},
+				{msg: "failed to shutdown connection", level: zapcore.WarnLevel},
+				{msg: "failed to shutdown connection", level: zapcore.WarnLevel},
+				{msg: "failed to shutdown connection", level: zapcore.WarnLevel},
 			},
 			expectedMetrics: metricsV3414,
 			expectedResourceAttributes: map[string]string{
@@ -187,6 +203,9 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 			sendCmd: func(conn net.Conn, s string) (*bufio.Scanner, error) {
 				return nil, errors.New("")
 			},
+			expectedMetrics:            []pdata.Metric{metadata.Metrics.ZookeeperUp.New()},
+			expectedNumResourceMetrics: 1,
+			wantErr:                    true,
 		},
 	}
 	for _, tt := range tests {
@@ -194,7 +213,12 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 			localAddr := testutil.GetAvailableLocalAddress(t)
 			if !tt.mockZKConnectionErr {
 				ms := mockedServer{ready: make(chan bool, 1)}
-				go ms.mockZKServer(t, localAddr, tt.mockedZKOutputSourceFilename)
+				go ms.mockZKServer(t, localAddr, map[string]string{
+					mntrCommand: tt.mockedZKOutputSourceFilename,
+					ruokCommand: "ruok",
+					wchsCommand: "wchs",
+					consCommand: "cons",
+				})
 				<-ms.ready
 			}
 
@@ -234,10 +258,9 @@ func TestZookeeperMetricsScraperScrape(t *testing.T) {
 
 			if tt.wantErr {
 				require.Error(t, err)
-				require.Equal(t, pdata.NewResourceMetricsSlice(), got)
-
-				require.NoError(t, z.shutdown(ctx))
-				return
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+			} else {
+				require.NoError(t, err)
 			}
 
 			require.Equal(t, tt.expectedNumResourceMetrics, got.Len())
@@ -282,26 +305,48 @@ func assertDescriptorEqual(t *testing.T, expected pdata.Metric, actual pdata.Met
 	require.Equal(t, expected.DataType(), actual.DataType())
 }
 
+func fourLetterWordMetrics() []pdata.Metric {
+	return []pdata.Metric{
+		metadata.Metrics.ZookeeperUp.New(),
+		metadata.Metrics.ZookeeperWatchConnections.New(),
+		metadata.Metrics.ZookeeperWatchedPaths.New(),
+		metadata.Metrics.ZookeeperClientConnections.New(),
+		metadata.Metrics.ZookeeperClientOutstandingRequests.New(),
+		metadata.Metrics.ZookeeperClientLatencyMax.New(),
+	}
+}
+
 type mockedServer struct {
 	ready chan bool
 }
 
-func (ms *mockedServer) mockZKServer(t *testing.T, endpoint string, filename string) {
+// mockZKServer responds to the 4lw commands with the testdata files of the
+// responses, the commands without response being answered as not in the
+// whitelist, and those with an empty file name not being answered.
+func (ms *mockedServer) mockZKServer(t *testing.T, endpoint string, responses map[string]string) {
 	listener, err := net.Listen("tcp", endpoint)
 	require.NoError(t, err)
-	defer listener.Close()
+	t.Cleanup(func() { listener.Close() })
 
 	ms.ready <- true
 
-	conn, err := listener.Accept()
-	require.NoError(t, err)
-
 	for {
-		out, err := ioutil.ReadFile(path.Join(".", "testdata", filename))
-		require.NoError(t, err)
-
-		conn.Write(out)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		cmd, _ := bufio.NewReader(conn).ReadString('\n')
+		cmd = strings.TrimSpace(cmd)
+		if filename, ok := responses[cmd]; ok && filename == "" {
+			// the connection is closed without a response
+		} else if ok {
+			out, err := ioutil.ReadFile(path.Join(".", "testdata", filename))
+			require.NoError(t, err)
+			conn.Write(out)
+		} else {
+			fmt.Fprintf(conn, "%s %s\n", cmd, notWhitelistedSuffix)
+		}
 		conn.Close()
-		return
 	}
 }